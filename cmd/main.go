@@ -3,25 +3,40 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"maqhaa/library/logging"
 	exRepo "maqhaa/order_service/external/repository"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/outbox"
 	"maqhaa/order_service/internal/app/repository"
+	"maqhaa/order_service/internal/app/schedule"
 	"maqhaa/order_service/internal/app/service"
+	"maqhaa/order_service/internal/app/webhook"
 	"maqhaa/order_service/internal/config"
 	"maqhaa/order_service/internal/database"
+	grpcHandler "maqhaa/order_service/internal/interface/grpc/handler"
+	"maqhaa/order_service/internal/interface/grpc/pb"
 	"maqhaa/order_service/internal/interface/http/handler"
 	"maqhaa/order_service/internal/interface/http/router"
+	"net"
 	"os"
 	"time"
+
+	// Client time zones are loaded without depending on the time zone database of the host
+	_ "time/tzdata"
+
+	"google.golang.org/grpc"
 )
 
 func main() {
 	// Define a command line flag for the config file path
 	configFilePath := flag.String("config", "config/config.yaml", "path to the config file")
 	logFile := flag.String("log.file", "../logs", "Logging file")
+	backfillPhone := flag.Bool("backfill.phone", false, "normalise the stored phone numbers to E.164, merge duplicate customers and exit")
 
 	flag.Parse()
 
@@ -48,23 +63,189 @@ func main() {
 	}
 	defer sqlDB.Close()
 
+	if *backfillPhone {
+		changed, err := repository.NewCustomerRepository(db).NormalizePhoneNumbers(context.Background())
+		if err != nil {
+			logging.Log.Fatalf("Error normalising phone numbers: %v", err)
+		}
+		logging.Log.Infof("Normalised %d phone numbers", changed)
+		return
+	}
+
 	// Initialize handlers
 	httpRouter := router.NewMuxRouter()
 
 	pingHandler := handler.NewPingHandler()
 	httpRouter.GET("/ping", pingHandler.Ping)
 
+	openAPIHandler := handler.NewOpenAPIHandler()
+	httpRouter.GET("/openapi.json", openAPIHandler.GetSpecHandler)
+
 	// Initialize product service
 	productRepo := exRepo.NewProductRepository(cfg.ExternalConnection.ProductService.Host)
 	orderRepository := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepository, productRepo)
+	clientRepository := repository.NewClientRepository(db)
+	promotionRepository := repository.NewPromotionRepository(db)
+	kitchenRepository := repository.NewKitchenRepository(db)
+	eventHub := event.NewHub(event.DefaultHistorySize)
+	orderService := service.NewOrderService(orderRepository, productRepo, promotionRepository, clientRepository, kitchenRepository, eventHub)
 	orderHandler := handler.NewOrderHandler(orderService)
 	httpRouter.POST("/order", orderHandler.CreateOrderHandler)
+	httpRouter.GET("/order", orderHandler.ListOrdersHandler)
+	httpRouter.GET("/order/scheduled", orderHandler.GetScheduledOrdersHandler)
 	httpRouter.GET("/order/{orderID}", orderHandler.GetOrderHandler)
+	httpRouter.PUT("/order/{orderID}", orderHandler.EditOrderHandler)
+	httpRouter.PUT("/order/{orderID}/status", orderHandler.UpdateStatusHandler)
+	httpRouter.PUT("/order/{orderID}/cancel", orderHandler.CancelOrderHandler)
+
+	eventService := service.NewEventService(eventHub, clientRepository,
+		cfg.Events.TokenSecret, time.Duration(cfg.Events.TokenTTLSeconds)*time.Second)
+	eventHandler := handler.NewEventHandler(eventService)
+	httpRouter.POST("/events/token", eventHandler.IssueStreamTokenHandler)
+	httpRouter.GET("/events/order", eventHandler.StreamOrderEventsHandler)
+
+	boardService := service.NewBoardService(orderRepository, clientRepository, eventHub,
+		cfg.Board.TokenSecret, time.Duration(cfg.Board.TokenTTLSeconds)*time.Second)
+	boardHandler := handler.NewBoardHandler(boardService, cfg.Board.AllowedOrigins)
+	httpRouter.GET("/board", boardHandler.GetBoardHandler)
+	httpRouter.POST("/board/token", boardHandler.IssueBoardTokenHandler)
+	httpRouter.GET("/board/ws", boardHandler.BoardSocketHandler)
+
+	promotionService := service.NewPromotionService(promotionRepository, clientRepository)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+	httpRouter.POST("/promotion", promotionHandler.CreatePromotionHandler)
+	httpRouter.GET("/promotion", promotionHandler.GetPromotionsHandler)
+
+	clientService := service.NewClientService(clientRepository)
+	clientHandler := handler.NewClientHandler(clientService)
+	httpRouter.USE(handler.NewLanguageMiddleware(clientService).Middleware)
+	httpRouter.GET("/client/setting", clientHandler.GetSettingHandler)
+	httpRouter.PUT("/client/setting", clientHandler.UpdateSettingHandler)
+	httpRouter.GET("/client/opening-hours", clientHandler.GetOpeningHoursHandler)
+	httpRouter.PUT("/client/opening-hours", clientHandler.UpdateOpeningHoursHandler)
+	httpRouter.GET("/client/holiday", clientHandler.GetHolidaysHandler)
+	httpRouter.POST("/client/holiday", clientHandler.AddHolidayHandler)
+	httpRouter.DELETE("/client/holiday/{holidayID}", clientHandler.DeleteHolidayHandler)
+	httpRouter.GET("/client/state", clientHandler.GetStoreStateHandler)
+	httpRouter.PUT("/client/pause", clientHandler.PauseOrdersHandler)
+
+	customerRepository := repository.NewCustomerRepository(db)
+	loyaltyRepository := repository.NewLoyaltyRepository(db)
+	customerService := service.NewCustomerService(customerRepository, loyaltyRepository, clientRepository, productRepo)
+	customerHandler := handler.NewCustomerHandler(customerService)
+	httpRouter.GET("/customer", customerHandler.LookupCustomerHandler)
+	httpRouter.GET("/customer/{customerID}", customerHandler.GetCustomerHandler)
+	httpRouter.GET("/customer/{customerID}/order", customerHandler.GetCustomerOrdersHandler)
+	httpRouter.GET("/customer/{customerID}/points", customerHandler.GetCustomerPointsHandler)
+
+	stockRepository := repository.NewStockRepository(db)
+	stockService := service.NewStockService(stockRepository, clientRepository, productRepo)
+	stockHandler := handler.NewStockHandler(stockService)
+	httpRouter.GET("/stock", stockHandler.GetStocksHandler)
+	httpRouter.PUT("/stock/{productID}", stockHandler.SetStockHandler)
+	httpRouter.DELETE("/stock/{productID}", stockHandler.DeleteStockHandler)
+
+	ingredientRepository := repository.NewIngredientRepository(db)
+	ingredientService := service.NewIngredientService(ingredientRepository, clientRepository, productRepo)
+	ingredientHandler := handler.NewIngredientHandler(ingredientService)
+	httpRouter.GET("/ingredient", ingredientHandler.GetIngredientsHandler)
+	httpRouter.POST("/ingredient", ingredientHandler.CreateIngredientHandler)
+	httpRouter.GET("/ingredient/consumption", ingredientHandler.GetConsumptionReportHandler)
+	httpRouter.GET("/recipe/{productID}", ingredientHandler.GetRecipeHandler)
+	httpRouter.PUT("/recipe/{productID}", ingredientHandler.UpdateRecipeHandler)
+
+	reportRepository := repository.NewReportRepository(db)
+	reportService := service.NewReportService(reportRepository, clientRepository, productRepo)
+	reportHandler := handler.NewReportHandler(reportService)
+	httpRouter.GET("/reports/daily", reportHandler.GetDailyReportHandler)
+	httpRouter.GET("/reports/products", reportHandler.GetProductRankingHandler)
+	httpRouter.GET("/reports/heatmap", reportHandler.GetHourlyHeatmapHandler)
+
+	shiftRepository := repository.NewShiftRepository(db)
+	shiftService := service.NewShiftService(shiftRepository, clientRepository)
+	shiftHandler := handler.NewShiftHandler(shiftService)
+	httpRouter.POST("/shift/open", shiftHandler.OpenShiftHandler)
+	httpRouter.GET("/shift/current", shiftHandler.GetCurrentShiftHandler)
+	httpRouter.PUT("/shift/current/close", shiftHandler.CloseShiftHandler)
+	httpRouter.GET("/shift", shiftHandler.GetShiftsHandler)
+	httpRouter.GET("/shift/{shiftID}", shiftHandler.GetShiftHandler)
+
+	kitchenService := service.NewKitchenService(orderRepository, kitchenRepository, clientRepository, eventHub)
+	kitchenHandler := handler.NewKitchenHandler(kitchenService)
+	httpRouter.GET("/kitchen/queue", kitchenHandler.GetQueueHandler)
+	httpRouter.PUT("/kitchen/detail/{detailID}/start", kitchenHandler.StartOrderDetailHandler)
+	httpRouter.PUT("/kitchen/detail/{detailID}/ready", kitchenHandler.ReadyOrderDetailHandler)
+	httpRouter.POST("/kitchen/station", kitchenHandler.CreateStationHandler)
+	httpRouter.GET("/kitchen/station", kitchenHandler.GetStationsHandler)
+	httpRouter.GET("/kitchen/station/{stationID}/queue", kitchenHandler.GetStationQueueHandler)
+	httpRouter.PUT("/kitchen/ticket/{ticketID}/bump", kitchenHandler.BumpTicketHandler)
+
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(webhookRepository, clientRepository, cfg.Webhook.AllowPrivateNetworks)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	httpRouter.POST("/webhook", webhookHandler.CreateWebhookHandler)
+	httpRouter.GET("/webhook", webhookHandler.GetWebhooksHandler)
+	httpRouter.DELETE("/webhook/{webhookID}", webhookHandler.DeactivateWebhookHandler)
+	httpRouter.GET("/webhook/delivery", webhookHandler.GetDeliveriesHandler)
+	httpRouter.PUT("/webhook/delivery/{deliveryID}/redeliver", webhookHandler.RedeliverHandler)
+
+	// Webhook deliveries are created from the outbox by their own relay, so partner webhooks do not
+	// wait for the configured publisher
+	outboxRepository := repository.NewOutboxRepository(db)
+	sinks := map[string]outbox.Publisher{
+		model.OutboxSinkPublisher: newOutboxPublisher(&cfg.Outbox),
+		model.OutboxSinkWebhook:   webhook.NewPublisher(webhookRepository),
+	}
+	for _, sink := range model.OutboxSinks {
+		outboxRelay := outbox.NewRelay(outboxRepository, sink, sinks[sink], time.Duration(cfg.Outbox.IntervalSeconds)*time.Second, cfg.Outbox.BatchSize,
+			cfg.Outbox.MaxAttempts, time.Duration(cfg.Outbox.BackoffSeconds)*time.Second)
+		go outboxRelay.Run(context.Background())
+	}
+
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository,
+		time.Duration(cfg.Webhook.IntervalSeconds)*time.Second,
+		cfg.Webhook.MaxAttempts,
+		time.Duration(cfg.Webhook.BackoffSeconds)*time.Second,
+		time.Duration(cfg.Webhook.TimeoutSeconds)*time.Second,
+		cfg.Webhook.AllowPrivateNetworks)
+	go webhookDispatcher.Run(context.Background())
+
+	scheduleReleaser := schedule.NewReleaser(orderRepository, eventHub, time.Duration(cfg.Schedule.IntervalSeconds)*time.Second, cfg.Schedule.BatchSize)
+	go scheduleReleaser.Run(context.Background())
+
+	if cfg.GRPCPort != "" {
+		go serveGRPC(cfg.GRPCPort, grpcHandler.NewOrderHandler(orderService))
+	}
 
 	httpRouter.SERVE(cfg.AppPort)
 }
 
+// serveGRPC serves the order API over gRPC next to the REST API.
+func serveGRPC(port string, orderHandler pb.OrderServiceServer) {
+	listener, err := net.Listen("tcp", port)
+	if err != nil {
+		logging.Log.Fatalf("Error listening on gRPC port: %v", err)
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterOrderServiceServer(server, orderHandler)
+
+	logging.Log.Infof("gRPC server listening on %v", port)
+	if err := server.Serve(listener); err != nil {
+		logging.Log.Fatalf("Error serving gRPC: %v", err)
+	}
+}
+
+func newOutboxPublisher(cfg *config.OutboxConfig) outbox.Publisher {
+	switch cfg.Publisher {
+	case outbox.PublisherWebhook:
+		logging.Log.Infof("Publishing order events to %v", cfg.WebhookURL)
+		return outbox.NewWebhookPublisher(cfg.WebhookURL, 10*time.Second)
+	default:
+		return outbox.NewLogPublisher()
+	}
+}
+
 func initLogging(logFolder string) {
 	logging.InitLogger()
 	currentDate := time.Now().Format("2006-01-02")
@@ -7,6 +7,7 @@ type Product struct {
 	Description string  `json:"description"`
 	Image       string  `json:"image"`
 	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
 	IsActive    bool    `json:"isActive"`
 	CreatedAt   string  `json:"createdAt"`
 }
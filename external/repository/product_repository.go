@@ -9,14 +9,19 @@ import (
 	"maqhaa/library/middleware"
 	"maqhaa/order_service/external/entity"
 	pb "maqhaa/order_service/external/model"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
 
+// maxProductLookups is the number of products GetProductsByIDs requests at the same time.
+const maxProductLookups = 8
+
 // ProductRepository handles database interactions related to products.
 type ProductRepository interface {
 	GetProductByID(ctx context.Context, productID uint, token string) (*entity.Product, error)
+	GetProductsByIDs(ctx context.Context, productIDs []uint, token string) (map[uint]*entity.Product, error)
 }
 
 // Implement the interface in the ProductRepository struct
@@ -31,10 +36,6 @@ func NewProductRepository(connetionURl string) ProductRepository {
 
 func (r *productRepository) GetProductByID(ctx context.Context, productID uint, token string) (*entity.Product, error) {
 	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
-	req := &pb.GetProductRequest{
-		ProductId: uint32(productID),
-		Token:     token, // Replace with a valid product ID for your test data
-	}
 	conn, err := grpc.Dial(r.connetionURl, grpc.WithInsecure())
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetProductByID  %s", err.Error())
@@ -42,8 +43,60 @@ func (r *productRepository) GetProductByID(ctx context.Context, productID uint,
 	}
 	defer conn.Close()
 
+	return getProduct(ctx, pb.NewProductClient(conn), productID, token)
+}
+
+// GetProductsByIDs looks up several products over one connection. The product service has no
+// batch RPC, so the products are requested concurrently. Products that can not be found are
+// missing from the result.
+func (r *productRepository) GetProductsByIDs(ctx context.Context, productIDs []uint, token string) (map[uint]*entity.Product, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	products := make(map[uint]*entity.Product, len(productIDs))
+	if len(productIDs) == 0 {
+		return products, nil
+	}
+
+	conn, err := grpc.Dial(r.connetionURl, grpc.WithInsecure())
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetProductsByIDs  %s", err.Error())
+		return nil, err
+	}
+	defer conn.Close()
+
 	client := pb.NewProductClient(conn)
 
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	lookups := make(chan struct{}, maxProductLookups)
+	for _, productID := range productIDs {
+		wg.Add(1)
+		lookups <- struct{}{}
+		go func(productID uint) {
+			defer wg.Done()
+			defer func() { <-lookups }()
+
+			product, err := getProduct(ctx, client, productID, token)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			products[productID] = product
+			mu.Unlock()
+		}(productID)
+	}
+	wg.Wait()
+
+	return products, nil
+}
+
+func getProduct(ctx context.Context, client pb.ProductClient, productID uint, token string) (*entity.Product, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	req := &pb.GetProductRequest{
+		ProductId: uint32(productID),
+		Token:     token, // Replace with a valid product ID for your test data
+	}
+
 	resp, err := client.GetProduct(context.Background(), req)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetProductByID  %s", err.Error())
@@ -61,9 +114,11 @@ func (r *productRepository) GetProductByID(ctx context.Context, productID uint,
 
 	product := &entity.Product{
 		ID:          uint(resp.Data.Id),
+		CategoryID:  uint(resp.Data.CategoryId),
 		Name:        resp.Data.Name,
 		Image:       resp.Data.Image,
 		Price:       float64(resp.Data.Price),
+		Currency:    resp.Data.Currency,
 		Description: resp.Data.Description,
 		IsActive:    resp.Data.IsActive,
 		CreatedAt:   resp.Data.CreatedAt,
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+type ClientSetting struct {
+	ID                   uint       `gorm:"primary_key" json:"id"`
+	ClientID             uint       `json:"client_id"`
+	CurrencyCode         string     `json:"currency_code"`
+	TaxName              string     `json:"tax_name"`
+	TaxRate              float64    `json:"tax_rate"`
+	TaxInclusive         bool       `json:"tax_inclusive"`
+	TaxOnServiceCharge   bool       `json:"tax_on_service_charge"`
+	ServiceChargeRate    float64    `json:"service_charge_rate"`
+	RoundingMode         string     `json:"rounding_mode"`
+	RoundingIncrement    float64    `json:"rounding_increment"`
+	Language             string     `json:"language"`
+	CountryCode          string     `json:"country_code"`
+	LoyaltySpendPerPoint float64    `json:"loyalty_spend_per_point"`
+	LoyaltyPointValue    float64    `json:"loyalty_point_value"`
+	LoyaltyExpiryDays    int        `json:"loyalty_expiry_days"`
+	ScheduleLeadMinutes  int        `json:"schedule_lead_minutes"`
+	Timezone             string     `json:"timezone"`
+	OrdersPaused         bool       `json:"orders_paused"`
+	PausedUntil          *time.Time `json:"paused_until"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+func (ClientSetting) TableName() string {
+	return "client_setting"
+}
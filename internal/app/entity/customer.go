@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Customer is a customer of a client, identified by the normalised phone number of their orders.
+type Customer struct {
+	ID          uint      `gorm:"primary_key" json:"id"`
+	ClientID    uint      `json:"client_id"`
+	PhoneNumber string    `json:"phone_number"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Customer) TableName() string {
+	return "customer"
+}
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Ingredient is a raw material of a client, e.g. milk or coffee beans. Unit is the unit its
+// quantities are measured in.
+type Ingredient struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	ClientID  uint      `json:"client_id"`
+	Name      string    `json:"name"`
+	Unit      string    `json:"unit"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Ingredient) TableName() string {
+	return "ingredient"
+}
+
+// RecipeItem is the quantity of an ingredient used for one unit of a product.
+type RecipeItem struct {
+	ID           uint    `gorm:"primary_key" json:"id"`
+	ClientID     uint    `json:"client_id"`
+	ProductID    uint    `json:"product_id"`
+	IngredientID uint    `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+}
+
+func (RecipeItem) TableName() string {
+	return "recipe_item"
+}
+
+// IngredientConsumption is the quantity of an ingredient used by a completed order. It is kept
+// when the recipe changes later.
+type IngredientConsumption struct {
+	ID           uint      `gorm:"primary_key" json:"id"`
+	ClientID     uint      `json:"client_id"`
+	OrderID      uint      `json:"order_id"`
+	IngredientID uint      `json:"ingredient_id"`
+	Quantity     float64   `json:"quantity"`
+	ConsumedAt   time.Time `json:"consumed_at"`
+}
+
+func (IngredientConsumption) TableName() string {
+	return "ingredient_consumption"
+}
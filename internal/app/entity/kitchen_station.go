@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+type KitchenStation struct {
+	ID         uint                     `gorm:"primary_key" json:"id"`
+	ClientID   uint                     `json:"client_id"`
+	Name       string                   `json:"name"`
+	IsActive   bool                     `json:"is_active"`
+	CreatedAt  time.Time                `json:"created_at"`
+	Categories []KitchenStationCategory `json:"categories,omitempty" gorm:"foreignkey:StationID"`
+}
+
+func (KitchenStation) TableName() string {
+	return "kitchen_station"
+}
+
+type KitchenStationCategory struct {
+	ID         uint `gorm:"primary_key" json:"id"`
+	StationID  uint `json:"station_id"`
+	CategoryID uint `json:"category_id"`
+}
+
+func (KitchenStationCategory) TableName() string {
+	return "kitchen_station_category"
+}
+
+// KitchenTicket groups the lines of an order that are prepared at the same station.
+// The lines of a ticket are the order details with the same order and station.
+type KitchenTicket struct {
+	ID           uint          `gorm:"primary_key" json:"id"`
+	OrderID      uint          `json:"order_id"`
+	StationID    uint          `json:"station_id"`
+	Status       int           `json:"status"`
+	CreatedAt    time.Time     `json:"created_at"`
+	BumpedAt     *time.Time    `json:"bumped_at"`
+	Order        *Order        `json:"order,omitempty" gorm:"foreignkey:OrderID"`
+	OrderDetails []OrderDetail `json:"order_details,omitempty" gorm:"-"`
+}
+
+func (KitchenTicket) TableName() string {
+	return "kitchen_ticket"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// LoyaltyLedger is an entry of the loyalty points ledger of a customer. Points is positive for
+// credits and negative for debits. Remaining is the part of a credit that has not been redeemed or
+// reversed yet, it is always zero for debits.
+type LoyaltyLedger struct {
+	ID         uint       `gorm:"primary_key" json:"id"`
+	ClientID   uint       `json:"client_id"`
+	CustomerID uint       `json:"customer_id"`
+	OrderID    uint       `json:"order_id"`
+	Type       string     `json:"type"`
+	Points     int        `json:"points"`
+	Remaining  int        `json:"remaining"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (LoyaltyLedger) TableName() string {
+	return "loyalty_ledger"
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// ClientOpeningHour is an opening period of a client on a day of the week. Weekday is 0 for
+// Sunday, times are HH:MM in the time zone of the service and a close time of 00:00 is midnight.
+type ClientOpeningHour struct {
+	ID        uint   `gorm:"primary_key" json:"id"`
+	ClientID  uint   `json:"client_id"`
+	Weekday   int    `json:"weekday"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+func (ClientOpeningHour) TableName() string {
+	return "client_opening_hour"
+}
+
+// ClientHoliday is an exception to the weekly opening hours of a client on a date. A holiday
+// without open and close time closes the client for the whole day, otherwise its periods replace
+// the weekly opening hours of that date. Date is YYYY-MM-DD.
+type ClientHoliday struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	ClientID  uint      `json:"client_id"`
+	Date      string    `json:"date"`
+	OpenTime  string    `json:"open_time"`
+	CloseTime string    `json:"close_time"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ClientHoliday) TableName() string {
+	return "client_holiday"
+}
@@ -3,19 +3,45 @@ package entity
 import "time"
 
 type Order struct {
-	ID           uint          `gorm:"primary_key" json:"id"`
-	OrderNumber  string        `json:"order_number"`
-	ClientID     uint          `json:"client_id"`
-	QueueNumber  int           `json:"queue_number"`
-	CustomerName string        `json:"customer_name"`
-	PhoneNumber  string        `json:"phone_number"`
-	Total        float64       `json:"total"`
-	Status       int           `json:"status"`
-	StatusText   string        `json:"status_text" gorm:"-"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
-	UpdatedBy    int           `json:"updated_by"`
-	OrderDetails []OrderDetail `json:"order_details,omitempty" gorm:"foreignkey:OrderID"`
+	ID                   uint                  `gorm:"primary_key" json:"id"`
+	OrderNumber          string                `json:"order_number"`
+	ClientID             uint                  `json:"client_id"`
+	QueueNumber          int                   `json:"queue_number"`
+	CustomerName         string                `json:"customer_name"`
+	PhoneNumber          string                `json:"phone_number"`
+	CustomerID           *uint                 `json:"customer_id"`
+	CurrencyCode         string                `json:"currency_code"`
+	PromoCode            string                `json:"promo_code"`
+	Subtotal             float64               `json:"subtotal"`
+	PromoDiscount        float64               `json:"promo_discount"`
+	ServiceChargeRate    float64               `json:"service_charge_rate"`
+	ServiceCharge        float64               `json:"service_charge"`
+	TaxRate              float64               `json:"tax_rate"`
+	TaxInclusive         bool                  `json:"tax_inclusive"`
+	Tax                  float64               `json:"tax"`
+	RoundingAdjustment   float64               `json:"rounding_adjustment"`
+	Total                float64               `json:"total"`
+	PointsRedeemed       int                   `json:"points_redeemed"`
+	PointsAmount         float64               `json:"points_amount"`
+	PointsEarned         int                   `json:"points_earned"`
+	StockReserved        bool                  `json:"stock_reserved"`
+	PaymentMethod        string                `json:"payment_method"`
+	PaidAt               *time.Time            `json:"paid_at"`
+	ShiftID              *uint                 `json:"shift_id"`
+	LockedAt             *time.Time            `json:"locked_at"`
+	Status               int                   `json:"status"`
+	StatusText           string                `json:"status_text" gorm:"-"`
+	ScheduledAt          *time.Time            `json:"scheduled_at"`
+	ReleasedAt           *time.Time            `json:"released_at"`
+	ProcessingAt         *time.Time            `json:"processing_at"`
+	CompletedAt          *time.Time            `json:"completed_at"`
+	CancelledAt          *time.Time            `json:"cancelled_at"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+	UpdatedBy            int                   `json:"updated_by"`
+	OrderDetails         []OrderDetail         `json:"order_details,omitempty" gorm:"foreignkey:OrderID"`
+	PromotionRedemptions []PromotionRedemption `json:"promotions,omitempty" gorm:"foreignkey:OrderID"`
+	KitchenTickets       []KitchenTicket       `json:"kitchen_tickets,omitempty" gorm:"foreignkey:OrderID"`
 }
 
 func (Order) TableName() string {
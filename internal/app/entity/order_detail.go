@@ -1,13 +1,20 @@
 package entity
 
+import "time"
+
 type OrderDetail struct {
-	ID        uint    `gorm:"primary_key" json:"id"`
-	OrderID   uint    `json:"order_id"`
-	ProductID uint    `json:"product_id"`
-	Price     float64 `json:"price"`
-	Quantity  int     `json:"quantity"`
-	Discount  float64 `json:"discount"`
-	Total     float64 `json:"total"`
+	ID         uint       `gorm:"primary_key" json:"id"`
+	OrderID    uint       `json:"order_id"`
+	ProductID  uint       `json:"product_id"`
+	CategoryID uint       `json:"category_id"`
+	StationID  uint       `json:"station_id"`
+	Price      float64    `json:"price"`
+	Quantity   int        `json:"quantity"`
+	Discount   float64    `json:"discount"`
+	Total      float64    `json:"total"`
+	PrepStatus int        `json:"prep_status"`
+	StartedAt  *time.Time `json:"started_at"`
+	ReadyAt    *time.Time `json:"ready_at"`
 }
 
 func (OrderDetail) TableName() string {
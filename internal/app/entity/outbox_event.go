@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// OutboxEvent is an order domain event written in the same transaction as the order change.
+// The relay publishes pending events to other services and marks them as published. A failed
+// event is retried from NextAttemptAt and is dead once it ran out of attempts. An event is written
+// once for every sink, Sink is the sink this row is published to.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primary_key" json:"id"`
+	Sink          string     `json:"sink"`
+	ClientID      uint       `json:"client_id"`
+	OrderID       uint       `json:"order_id"`
+	Type          string     `json:"type"`
+	Payload       string     `json:"payload"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	PublishedAt   *time.Time `json:"published_at"`
+	DeadAt        *time.Time `json:"dead_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "order_outbox"
+}
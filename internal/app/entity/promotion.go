@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+type Promotion struct {
+	ID                uint               `gorm:"primary_key" json:"id"`
+	ClientID          uint               `json:"client_id"`
+	Code              string             `json:"code"`
+	Name              string             `json:"name"`
+	Type              string             `json:"type"`
+	Value             float64            `json:"value"`
+	MaxDiscount       float64            `json:"max_discount"`
+	BuyQuantity       int                `json:"buy_quantity"`
+	GetQuantity       int                `json:"get_quantity"`
+	MinSpend          float64            `json:"min_spend"`
+	StartAt           *time.Time         `json:"start_at"`
+	EndAt             *time.Time         `json:"end_at"`
+	HourStart         int                `json:"hour_start"`
+	HourEnd           int                `json:"hour_end"`
+	UsageLimit        int                `json:"usage_limit"`
+	PerCustomerLimit  int                `json:"per_customer_limit"`
+	AutoApply         bool               `json:"auto_apply"`
+	IsActive          bool               `json:"is_active"`
+	CreatedAt         time.Time          `json:"created_at"`
+	PromotionProducts []PromotionProduct `json:"products,omitempty" gorm:"foreignkey:PromotionID"`
+}
+
+func (Promotion) TableName() string {
+	return "promotion"
+}
+
+type PromotionProduct struct {
+	ID          uint `gorm:"primary_key" json:"id"`
+	PromotionID uint `json:"promotion_id"`
+	ProductID   uint `json:"product_id"`
+}
+
+func (PromotionProduct) TableName() string {
+	return "promotion_product"
+}
+
+type PromotionRedemption struct {
+	ID          uint      `gorm:"primary_key" json:"id"`
+	PromotionID uint      `json:"promotion_id"`
+	OrderID     uint      `json:"order_id"`
+	ClientID    uint      `json:"client_id"`
+	PhoneNumber string    `json:"phone_number"`
+	Discount    float64   `json:"discount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (PromotionRedemption) TableName() string {
+	return "promotion_redemption"
+}
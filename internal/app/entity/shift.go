@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Shift is a cash register shift of a client from opening to closing. BusinessDate is the day the
+// shift opened in the time zone of the client. The cash totals are those of the cash movements
+// of the shift, they are stored when the shift closes. Variance is the counted cash minus the
+// expected cash.
+type Shift struct {
+	ID              uint       `gorm:"primary_key" json:"id"`
+	ClientID        uint       `json:"client_id"`
+	BusinessDate    string     `json:"business_date"`
+	Status          string     `json:"status"`
+	OpenedBy        string     `json:"opened_by"`
+	ClosedBy        string     `json:"closed_by"`
+	OpeningCash     float64    `json:"opening_cash"`
+	CashSales       float64    `json:"cash_sales"`
+	CashRefunds     float64    `json:"cash_refunds"`
+	CashAdjustments float64    `json:"cash_adjustments"`
+	ExpectedCash    float64    `json:"expected_cash"`
+	CountedCash     *float64   `json:"counted_cash"`
+	Variance        *float64   `json:"variance"`
+	OpeningNote     string     `json:"opening_note"`
+	ClosingNote     string     `json:"closing_note"`
+	OpenedAt        time.Time  `json:"opened_at"`
+	ClosedAt        *time.Time `json:"closed_at"`
+}
+
+func (Shift) TableName() string {
+	return "shift"
+}
+
+// ShiftCashMovement is cash taken or paid out by the register during a shift. Amount is positive
+// for cash taken and negative for cash paid out.
+type ShiftCashMovement struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	ShiftID   uint      `json:"shift_id"`
+	ClientID  uint      `json:"client_id"`
+	OrderID   uint      `json:"order_id"`
+	Type      string    `json:"type"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ShiftCashMovement) TableName() string {
+	return "shift_cash_movement"
+}
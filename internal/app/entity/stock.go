@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// ProductStock is the stock of a product at a client. Reserved is held by open orders and taken
+// from OnHand when they complete. Products without a stock row are not tracked.
+type ProductStock struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	ClientID  uint      `json:"client_id"`
+	ProductID uint      `json:"product_id"`
+	OnHand    int       `json:"on_hand"`
+	Reserved  int       `json:"reserved"`
+	Available int       `gorm:"-" json:"available"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ProductStock) TableName() string {
+	return "product_stock"
+}
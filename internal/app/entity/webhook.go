@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// WebhookSubscription is a partner endpoint that receives the order events of a client.
+// EventTypes is a comma separated list of event types.
+type WebhookSubscription struct {
+	ID         uint      `gorm:"primary_key" json:"id"`
+	ClientID   uint      `json:"client_id"`
+	URL        string    `json:"url"`
+	EventTypes string    `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscription"
+}
+
+// WebhookDelivery is one order event sent to one subscription. Deliveries that keep failing
+// are moved to the dead status and can be redelivered manually.
+type WebhookDelivery struct {
+	ID             uint                 `gorm:"primary_key" json:"id"`
+	SubscriptionID uint                 `json:"subscription_id"`
+	ClientID       uint                 `json:"client_id"`
+	EventID        uint                 `json:"event_id"`
+	EventType      string               `json:"event_type"`
+	Payload        string               `json:"payload"`
+	Status         string               `json:"status"`
+	Attempts       int                  `json:"attempts"`
+	NextAttemptAt  time.Time            `json:"next_attempt_at"`
+	LastStatusCode int                  `json:"last_status_code"`
+	LastError      string               `json:"last_error"`
+	DeliveredAt    *time.Time           `json:"delivered_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	Subscription   *WebhookSubscription `json:"-" gorm:"foreignkey:SubscriptionID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
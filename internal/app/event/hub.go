@@ -0,0 +1,216 @@
+package event
+
+import (
+	"fmt"
+	"maqhaa/order_service/internal/app/entity"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OrderCreated       = "order.created"
+	OrderEdited        = "order.edited"
+	OrderStatusChanged = "order.status_changed"
+	OrderCancelled     = "order.cancelled"
+	OrderReleased      = "order.released"
+
+	// Resync tells a reconnecting subscriber that the events it missed can not be replayed, it
+	// reloads the orders and continues from the ID of the resync event.
+	Resync = "resync"
+
+	// DefaultHistorySize is the number of recent events kept per client for replay.
+	DefaultHistorySize = 100
+
+	// subscriberBuffer is the number of events a subscriber may fall behind before it is dropped.
+	subscriberBuffer = 32
+)
+
+// Event is an order change delivered to subscribers of the client that owns the order. The ID is
+// the epoch of the hub, the time it started, and the sequence of the event, so IDs are not reused
+// after a restart.
+type Event struct {
+	ID        string        `json:"id"`
+	Type      string        `json:"type"`
+	ClientID  uint          `json:"client_id"`
+	OrderID   uint          `json:"order_id"`
+	Order     *entity.Order `json:"order,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+
+	sequence uint64
+}
+
+// Publisher publishes order events.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Subscription receives the events of one client. Events is closed when the subscription is
+// closed or when the subscriber falls too far behind, the subscriber is expected to reconnect
+// with the last event ID it has seen.
+type Subscription struct {
+	Events   chan Event
+	hub      *Hub
+	clientID uint
+}
+
+// Close removes the subscription from the hub.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub is an in-process pub/sub of order events, partitioned by client.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       uint64
+	lastID      uint64
+	historySize int
+	history     map[uint][]Event
+	trimmed     map[uint]uint64
+	subscribers map[uint]map[*Subscription]struct{}
+}
+
+// NewHub creates a Hub that keeps historySize events per client for replay.
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		epoch:       uint64(time.Now().UnixMilli()),
+		historySize: historySize,
+		history:     make(map[uint][]Event),
+		trimmed:     make(map[uint]uint64),
+		subscribers: make(map[uint]map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next event ID, stores the event for replay and delivers it to the
+// subscribers of the client. The order is copied, so the caller may keep changing it while
+// subscribers read the event. Publish never blocks on a slow subscriber.
+func (h *Hub) Publish(event Event) {
+	event.Order = snapshotOrder(event.Order)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.sequence = h.lastID
+	event.ID = h.eventID(h.lastID)
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	history := append(h.history[event.ClientID], event)
+	if len(history) > h.historySize {
+		h.trimmed[event.ClientID] = history[len(history)-h.historySize-1].sequence
+		history = history[len(history)-h.historySize:]
+	}
+	h.history[event.ClientID] = history
+
+	for subscription := range h.subscribers[event.ClientID] {
+		select {
+		case subscription.Events <- event:
+		default:
+			h.removeLocked(subscription)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for the events of a client. When lastEventID is not empty
+// the stored events published after it are returned for replay. A single Resync event is
+// returned instead when the events after lastEventID are not all stored, because it was issued
+// before the hub started or the history no longer reaches back to it.
+func (h *Hub) Subscribe(clientID uint, lastEventID string) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscription := &Subscription{
+		Events:   make(chan Event, subscriberBuffer),
+		hub:      h,
+		clientID: clientID,
+	}
+
+	if h.subscribers[clientID] == nil {
+		h.subscribers[clientID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[clientID][subscription] = struct{}{}
+
+	if lastEventID == "" {
+		return subscription, nil
+	}
+
+	epoch, sequence, ok := ParseEventID(lastEventID)
+	if !ok || epoch != h.epoch || sequence > h.lastID || sequence < h.trimmed[clientID] {
+		return subscription, []Event{{
+			ID:        h.eventID(h.lastID),
+			Type:      Resync,
+			ClientID:  clientID,
+			CreatedAt: time.Now(),
+			sequence:  h.lastID,
+		}}
+	}
+
+	var replay []Event
+	for _, event := range h.history[clientID] {
+		if event.sequence > sequence {
+			replay = append(replay, event)
+		}
+	}
+
+	return subscription, replay
+}
+
+// ParseEventID splits an event ID into the epoch of the hub and the sequence of the event.
+func ParseEventID(id string) (uint64, uint64, bool) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	epoch, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return epoch, sequence, true
+}
+
+func (h *Hub) eventID(sequence uint64) string {
+	return fmt.Sprintf("%d-%d", h.epoch, sequence)
+}
+
+// snapshotOrder returns a copy of an order and of its lines, tickets and redemptions.
+func snapshotOrder(order *entity.Order) *entity.Order {
+	if order == nil {
+		return nil
+	}
+
+	snapshot := *order
+	snapshot.OrderDetails = append([]entity.OrderDetail(nil), order.OrderDetails...)
+	snapshot.KitchenTickets = append([]entity.KitchenTicket(nil), order.KitchenTickets...)
+	snapshot.PromotionRedemptions = append([]entity.PromotionRedemption(nil), order.PromotionRedemptions...)
+
+	return &snapshot
+}
+
+func (h *Hub) unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeLocked(subscription)
+}
+
+func (h *Hub) removeLocked(subscription *Subscription) {
+	subscribers := h.subscribers[subscription.clientID]
+	if _, ok := subscribers[subscription]; !ok {
+		return
+	}
+
+	delete(subscribers, subscription)
+	close(subscription.Events)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, subscription.clientID)
+	}
+}
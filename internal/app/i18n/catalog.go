@@ -0,0 +1,149 @@
+package i18n
+
+import "fmt"
+
+// catalog holds the translated messages of one language. Errors are keyed by AppError code and
+// statuses by order status, a message may contain the verbs of the English message it replaces.
+// stockShortage is the message of an order line that is out of stock.
+type catalog struct {
+	errors        map[int]string
+	statuses      map[int]string
+	stockShortage string
+}
+
+// The English catalog is the source of the messages, it matches the message constants of the
+// service and model packages.
+var catalogs = map[string]catalog{
+	English: {
+		errors: map[int]string{
+			0:   "Success",
+			99:  "General System Error",
+			101: "User not found",
+			102: "Invalid Password",
+			201: "Invalid Format Request",
+			202: "Invalid Token",
+			203: "Invalid Request %s",
+			204: "Product Not Found",
+			205: "Invalid Product Price",
+			206: "Invalid Total",
+			207: "Promo Code Not Found",
+			208: "Promo Not Applicable",
+			209: "Promo Usage Limit Reached",
+			210: "Currency Mismatch",
+			221: "Order Not Found",
+			222: "Order Detail Not Found",
+			223: "Invalid Order Status",
+			224: "Kitchen Ticket Not Found",
+			225: "Webhook Delivery Not Found",
+			226: "Customer Not Found",
+			227: "Insufficient Loyalty Points",
+			228: "Invalid Scheduled Time",
+			229: "Store Closed",
+			230: "Holiday Not Found",
+			231: "Out of Stock",
+			232: "Stock Not Found",
+			233: "Ingredient Not Found",
+			234: "Shift Already Open",
+			235: "Shift Not Found",
+			236: "Order Locked by Closed Business Day",
+			237: "Order Not Paid",
+			238: "Webhook Not Found",
+			301: "Error query database",
+			302: "Error Update database",
+			601: "Data Not Found",
+		},
+		statuses: map[int]string{
+			0: "Unknown",
+			1: "Incoming",
+			2: "Paid",
+			3: "Processing",
+			4: "Success",
+			5: "Cancelled",
+		},
+		stockShortage: "only %d left in stock",
+	},
+	Indonesian: {
+		errors: map[int]string{
+			0:   "Berhasil",
+			99:  "Kesalahan Sistem",
+			101: "Pengguna tidak ditemukan",
+			102: "Kata Sandi Salah",
+			201: "Format Permintaan Tidak Valid",
+			202: "Token Tidak Valid",
+			203: "Permintaan Tidak Valid %s",
+			204: "Produk Tidak Ditemukan",
+			205: "Harga Produk Tidak Valid",
+			206: "Total Tidak Valid",
+			207: "Kode Promo Tidak Ditemukan",
+			208: "Promo Tidak Berlaku",
+			209: "Batas Penggunaan Promo Tercapai",
+			210: "Mata Uang Tidak Sesuai",
+			221: "Pesanan Tidak Ditemukan",
+			222: "Detail Pesanan Tidak Ditemukan",
+			223: "Status Pesanan Tidak Valid",
+			224: "Tiket Dapur Tidak Ditemukan",
+			225: "Pengiriman Webhook Tidak Ditemukan",
+			226: "Pelanggan Tidak Ditemukan",
+			227: "Poin Loyalitas Tidak Mencukupi",
+			228: "Waktu Terjadwal Tidak Valid",
+			229: "Toko Tutup",
+			230: "Hari Libur Tidak Ditemukan",
+			231: "Stok Habis",
+			232: "Stok Tidak Ditemukan",
+			233: "Bahan Tidak Ditemukan",
+			234: "Shift Sudah Dibuka",
+			235: "Shift Tidak Ditemukan",
+			236: "Pesanan Terkunci oleh Tutup Buku Harian",
+			237: "Pesanan Belum Dibayar",
+			238: "Webhook Tidak Ditemukan",
+			301: "Kesalahan kueri basis data",
+			302: "Kesalahan pembaruan basis data",
+			601: "Data Tidak Ditemukan",
+		},
+		statuses: map[int]string{
+			0: "Tidak Diketahui",
+			1: "Masuk",
+			2: "Dibayar",
+			3: "Diproses",
+			4: "Selesai",
+			5: "Dibatalkan",
+		},
+		stockShortage: "stok hanya tersisa %d",
+	},
+}
+
+// ErrorMessage returns the message of an AppError code in the language, or fallback when the
+// catalog has no message for the code.
+func ErrorMessage(lang string, code int, fallback string) string {
+	if message, ok := catalogs[lang].errors[code]; ok {
+		return message
+	}
+
+	return fallback
+}
+
+// StatusText returns the display text of an order status in the language. Unknown languages use
+// the English text.
+func StatusText(lang string, status int) string {
+	statuses, ok := catalogs[lang]
+	if !ok {
+		statuses = catalogs[DefaultLanguage]
+	}
+
+	if text, ok := statuses.statuses[status]; ok {
+		return text
+	}
+
+	return statuses.statuses[0]
+}
+
+// StockShortageMessage returns the message of an order line asking for more than the available
+// quantity in the language. Unknown languages use the English message.
+func StockShortageMessage(lang string, available int) string {
+	messages, ok := catalogs[lang]
+	if !ok {
+		messages = catalogs[DefaultLanguage]
+	}
+
+	return fmt.Sprintf(messages.stockShortage, available)
+}
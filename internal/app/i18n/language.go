@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+
+	// DefaultLanguage is used when neither the request nor the client setting selects a language.
+	DefaultLanguage = English
+)
+
+type languageKey struct{}
+
+// Languages returns the supported languages.
+func Languages() []string {
+	return []string{English, Indonesian}
+}
+
+// IsSupported reports whether there is a catalog for the language.
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Normalize returns the supported language of a language tag such as "id-ID", or an empty string
+// when the language is not supported.
+func Normalize(tag string) string {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+
+	if IsSupported(lang) {
+		return lang
+	}
+
+	return ""
+}
+
+// FromAcceptLanguage returns the supported language with the highest quality in an
+// Accept-Language header, or an empty string when none of them is supported.
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if lang := Normalize(fields[0]); lang != "" && quality > 0 {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].lang
+}
+
+// WithLanguage returns a copy of ctx that carries the language of the request.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// FromContext returns the language of the request, or DefaultLanguage when none was set.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok && lang != "" {
+		return lang
+	}
+
+	return DefaultLanguage
+}
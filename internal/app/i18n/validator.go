@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var universalTranslator = ut.New(en.New(), en.New(), id.New())
+
+// translation is a validation message that the translations of the validator do not provide.
+type translation struct {
+	tag     string
+	message string
+}
+
+// extraTranslations covers the rules used by the requests that are missing from the default
+// translations. {0} is the field and {1} the parameter of the rule.
+var extraTranslations = map[string][]translation{
+	English: {
+		{tag: "iso4217", message: "{0} must be a valid ISO 4217 currency code"},
+		{tag: "phone", message: "{0} must be a valid phone number"},
+	},
+	Indonesian: {
+		{tag: "required_without", message: "{0} wajib diisi jika {1} tidak diisi"},
+		{tag: "iso4217", message: "{0} harus berupa kode mata uang ISO 4217 yang valid"},
+		{tag: "datetime", message: "{0} harus menggunakan format {1}"},
+		{tag: "phone", message: "{0} harus berupa nomor telepon yang valid"},
+	},
+}
+
+// RegisterValidator registers the validation messages of every supported language on v.
+func RegisterValidator(v *validator.Validate) error {
+	if err := en_translations.RegisterDefaultTranslations(v, Translator(English)); err != nil {
+		return err
+	}
+	if err := id_translations.RegisterDefaultTranslations(v, Translator(Indonesian)); err != nil {
+		return err
+	}
+
+	for lang, translations := range extraTranslations {
+		trans := Translator(lang)
+		for _, t := range translations {
+			message := t.message
+			register := func(trans ut.Translator) error {
+				return trans.Add(t.tag, message, true)
+			}
+			translate := func(trans ut.Translator, fieldError validator.FieldError) string {
+				text, err := trans.T(fieldError.Tag(), fieldError.Field(), fieldError.Param())
+				if err != nil {
+					return fieldError.Error()
+				}
+				return text
+			}
+			if err := v.RegisterTranslation(t.tag, trans, register, translate); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Translator returns the validation translator of the language, unknown languages use English.
+func Translator(lang string) ut.Translator {
+	if trans, ok := universalTranslator.GetTranslator(lang); ok {
+		return trans
+	}
+
+	trans, _ := universalTranslator.GetTranslator(DefaultLanguage)
+	return trans
+}
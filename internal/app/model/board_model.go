@@ -0,0 +1,97 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"time"
+)
+
+const (
+	BoardMessageSnapshot   = "snapshot"
+	BoardMessageNowServing = "now_serving"
+	BoardMessageReady      = "ready"
+	BoardMessageRemoved    = "removed"
+
+	// BoardReadyLimit is the number of ready orders shown on the board.
+	BoardReadyLimit = 10
+
+	// DefaultBoardTokenTTL is the lifetime of a board token, long enough for a business day of a
+	// display that reconnects now and then.
+	DefaultBoardTokenTTL = 12 * time.Hour
+)
+
+// QueueBoardEntry is a queue number shown on a display board.
+type QueueBoardEntry struct {
+	OrderID      uint      `json:"order_id"`
+	OrderNumber  string    `json:"order_number"`
+	QueueNumber  int       `json:"queue_number"`
+	CustomerName string    `json:"customer_name"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// QueueBoard is the state of a display board: the orders being prepared and the orders that are
+// ready for pickup, most recent first.
+type QueueBoard struct {
+	NowServing []QueueBoardEntry `json:"now_serving"`
+	Ready      []QueueBoardEntry `json:"ready"`
+}
+
+// BoardMessage is pushed to display boards over the WebSocket. A snapshot carries the whole
+// board, the other types carry the entry that changed.
+type BoardMessage struct {
+	Type  string           `json:"type"`
+	Entry *QueueBoardEntry `json:"entry,omitempty"`
+	Board *QueueBoard      `json:"board,omitempty"`
+}
+
+func NewQueueBoardEntry(order *entity.Order) QueueBoardEntry {
+	updatedAt := order.UpdatedAt
+	if order.Status == OrderStatusSuccess && order.CompletedAt != nil {
+		updatedAt = *order.CompletedAt
+	}
+
+	return QueueBoardEntry{
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		QueueNumber:  order.QueueNumber,
+		CustomerName: order.CustomerName,
+		UpdatedAt:    updatedAt,
+	}
+}
+
+// NewBoardMessage returns the board message for an order in its current status, or nil when
+// the status is not shown on the board.
+func NewBoardMessage(order *entity.Order) *BoardMessage {
+	entry := NewQueueBoardEntry(order)
+
+	switch order.Status {
+	case OrderStatusProcessing:
+		return &BoardMessage{Type: BoardMessageNowServing, Entry: &entry}
+	case OrderStatusSuccess:
+		return &BoardMessage{Type: BoardMessageReady, Entry: &entry}
+	case OrderStatusCancelled:
+		return &BoardMessage{Type: BoardMessageRemoved, Entry: &entry}
+	default:
+		return nil
+	}
+}
+
+type QueueBoardResponse struct {
+	HTTPResponse
+	Data *struct {
+		Board *QueueBoard `json:"board,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// BoardToken authorizes a display board to open the board WebSocket. It is signed by the service
+// and expires, so the client API token does not have to be put in the URL of the board.
+type BoardToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type BoardTokenResponse struct {
+	HTTPResponse
+	Data *struct {
+		BoardToken *BoardToken `json:"board_token,omitempty"`
+	} `json:"data,omitempty"`
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"strings"
+	"time"
+)
+
+const (
+	RoundingModeNone    = "none"
+	RoundingModeNearest = "nearest"
+	RoundingModeUp      = "up"
+	RoundingModeDown    = "down"
+)
+
+// ClientSettingRequest updates the client setting. Fields left out of the request keep their
+// current value.
+type ClientSettingRequest struct {
+	CurrencyCode         *string  `json:"currency_code" validate:"omitempty,iso4217"`
+	TaxName              *string  `json:"tax_name"`
+	TaxRate              *float64 `json:"tax_rate" validate:"omitempty,gte=0,lte=100"`
+	TaxInclusive         *bool    `json:"tax_inclusive"`
+	TaxOnServiceCharge   *bool    `json:"tax_on_service_charge"`
+	ServiceChargeRate    *float64 `json:"service_charge_rate" validate:"omitempty,gte=0,lte=100"`
+	RoundingMode         *string  `json:"rounding_mode" validate:"omitempty,oneof=none nearest up down"`
+	RoundingIncrement    *float64 `json:"rounding_increment" validate:"omitempty,gte=0"`
+	Language             *string  `json:"language" validate:"omitempty,oneof=en id"`
+	CountryCode          *string  `json:"country_code" validate:"omitempty,len=2"`
+	LoyaltySpendPerPoint *float64 `json:"loyalty_spend_per_point" validate:"omitempty,gte=0"`
+	LoyaltyPointValue    *float64 `json:"loyalty_point_value" validate:"omitempty,gte=0"`
+	LoyaltyExpiryDays    *int     `json:"loyalty_expiry_days" validate:"omitempty,gte=0"`
+	ScheduleLeadMinutes  *int     `json:"schedule_lead_minutes" validate:"omitempty,gte=0,lte=1440"`
+	Timezone             *string  `json:"timezone"`
+}
+
+// ApplyClientSetting copies the fields set in the request to the setting.
+func ApplyClientSetting(setting *entity.ClientSetting, request *ClientSettingRequest) {
+	if request.CurrencyCode != nil {
+		setting.CurrencyCode = strings.ToUpper(*request.CurrencyCode)
+	}
+	if request.TaxName != nil {
+		setting.TaxName = *request.TaxName
+	}
+	if request.TaxRate != nil {
+		setting.TaxRate = *request.TaxRate
+	}
+	if request.TaxInclusive != nil {
+		setting.TaxInclusive = *request.TaxInclusive
+	}
+	if request.TaxOnServiceCharge != nil {
+		setting.TaxOnServiceCharge = *request.TaxOnServiceCharge
+	}
+	if request.ServiceChargeRate != nil {
+		setting.ServiceChargeRate = *request.ServiceChargeRate
+	}
+	if request.RoundingMode != nil {
+		setting.RoundingMode = *request.RoundingMode
+	}
+	if request.RoundingIncrement != nil {
+		setting.RoundingIncrement = *request.RoundingIncrement
+	}
+	if request.Language != nil {
+		setting.Language = *request.Language
+	}
+	if request.CountryCode != nil {
+		setting.CountryCode = strings.ToUpper(*request.CountryCode)
+	}
+	if request.LoyaltySpendPerPoint != nil {
+		setting.LoyaltySpendPerPoint = *request.LoyaltySpendPerPoint
+	}
+	if request.LoyaltyPointValue != nil {
+		setting.LoyaltyPointValue = *request.LoyaltyPointValue
+	}
+	if request.LoyaltyExpiryDays != nil {
+		setting.LoyaltyExpiryDays = *request.LoyaltyExpiryDays
+	}
+	if request.ScheduleLeadMinutes != nil {
+		setting.ScheduleLeadMinutes = *request.ScheduleLeadMinutes
+	}
+	if request.Timezone != nil {
+		setting.Timezone = *request.Timezone
+	}
+}
+
+type ClientSettingResponse struct {
+	HTTPResponse
+	Data *struct {
+		Setting *entity.ClientSetting `json:"setting,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// IsTimezone reports whether name is an IANA time zone, e.g. Asia/Jakarta.
+func IsTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// ClientLocation returns the time zone of a client. Clients without a valid time zone use the
+// time zone of the service.
+func ClientLocation(setting *entity.ClientSetting) *time.Location {
+	if setting.Timezone != "" {
+		if loc, err := time.LoadLocation(setting.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
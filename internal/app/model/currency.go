@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"maqhaa/order_service/internal/app/entity"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for clients that have not configured a currency.
+const DefaultCurrency = "IDR"
+
+// currencyMinorUnits holds the number of decimals shown for a currency. IDR is listed with
+// zero decimals because sen are not used in practice, even though ISO 4217 defines two.
+var currencyMinorUnits = map[string]int{
+	"IDR": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"USD": 2,
+	"EUR": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"AUD": 2,
+	"GBP": 2,
+	"THB": 2,
+	"PHP": 2,
+	"KWD": 3,
+	"BHD": 3,
+}
+
+// CurrencyMinorUnits returns the number of decimals of a currency, two when it is unknown.
+func CurrencyMinorUnits(currency string) int {
+	if units, ok := currencyMinorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return 2
+}
+
+// RoundAmount rounds an amount to the minor units of a currency, e.g. to whole rupiah for IDR and to
+// fils for KWD. An empty currency is DefaultCurrency.
+func RoundAmount(amount float64, currency string) float64 {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	pow := math.Pow(10, float64(CurrencyMinorUnits(currency)))
+	return math.Round(amount*pow) / pow
+}
+
+// FormatAmount formats an amount with the currency code, thousand separators and the minor
+// units of the currency, e.g. "IDR 25,800" or "USD 12.50".
+func FormatAmount(amount float64, currency string) string {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	units := CurrencyMinorUnits(currency)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	formatted := strconv.FormatFloat(RoundAmount(amount, currency), 'f', units, 64)
+
+	integer, fraction := formatted, ""
+	if i := strings.IndexByte(formatted, '.'); i >= 0 {
+		integer, fraction = formatted[:i], formatted[i:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s %s%s%s", strings.ToUpper(currency), sign, grouped.String(), fraction)
+}
+
+// OrderAmounts holds the amounts of an order formatted for display.
+type OrderAmounts struct {
+	Currency           string               `json:"currency"`
+	Subtotal           string               `json:"subtotal"`
+	PromoDiscount      string               `json:"promo_discount"`
+	ServiceCharge      string               `json:"service_charge"`
+	Tax                string               `json:"tax"`
+	RoundingAdjustment string               `json:"rounding_adjustment"`
+	Total              string               `json:"total"`
+	PointsAmount       string               `json:"points_amount"`
+	AmountDue          string               `json:"amount_due"`
+	OrderDetails       []OrderDetailAmounts `json:"order_details,omitempty"`
+}
+
+type OrderDetailAmounts struct {
+	ProductID uint   `json:"product_id"`
+	Price     string `json:"price"`
+	Discount  string `json:"discount"`
+	Total     string `json:"total"`
+}
+
+// NewOrderAmounts formats the amounts of an order in the currency of the order.
+func NewOrderAmounts(order *entity.Order) *OrderAmounts {
+	currency := order.CurrencyCode
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	amounts := &OrderAmounts{
+		Currency:           currency,
+		Subtotal:           FormatAmount(order.Subtotal, currency),
+		PromoDiscount:      FormatAmount(order.PromoDiscount, currency),
+		ServiceCharge:      FormatAmount(order.ServiceCharge, currency),
+		Tax:                FormatAmount(order.Tax, currency),
+		RoundingAdjustment: FormatAmount(order.RoundingAdjustment, currency),
+		Total:              FormatAmount(order.Total, currency),
+		PointsAmount:       FormatAmount(order.PointsAmount, currency),
+		AmountDue:          FormatAmount(order.Total-order.PointsAmount, currency),
+	}
+
+	for _, detail := range order.OrderDetails {
+		amounts.OrderDetails = append(amounts.OrderDetails, OrderDetailAmounts{
+			ProductID: detail.ProductID,
+			Price:     FormatAmount(detail.Price, currency),
+			Discount:  FormatAmount(detail.Discount, currency),
+			Total:     FormatAmount(detail.Total, currency),
+		})
+	}
+
+	return amounts
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"strings"
+	"time"
+)
+
+const (
+	// CustomerFavouriteLimit is the number of favourite products of a customer profile.
+	CustomerFavouriteLimit = 5
+
+	CustomerOrderDefaultLimit = 20
+	CustomerOrderMaxLimit     = 100
+)
+
+// NormalizePhoneNumber removes the formatting of a phone number, a leading "00" is written as "+".
+// ParsePhoneNumber also adds the calling code to local numbers.
+func NormalizePhoneNumber(phoneNumber string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phoneNumber) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		}
+	}
+
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	if normalized == "+" {
+		return ""
+	}
+
+	return normalized
+}
+
+// FavouriteProduct is a product a customer ordered, ranked by the quantity ordered.
+type FavouriteProduct struct {
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	OrderCount  int    `json:"order_count"`
+}
+
+// CustomerStats summarises the orders of a customer, cancelled orders are not counted.
+type CustomerStats struct {
+	OrderCount    int        `json:"order_count"`
+	LifetimeSpend float64    `json:"lifetime_spend"`
+	FirstOrderAt  *time.Time `json:"first_order_at"`
+	LastOrderAt   *time.Time `json:"last_order_at"`
+}
+
+type CustomerProfile struct {
+	Customer          *entity.Customer   `json:"customer"`
+	Stats             CustomerStats      `json:"stats"`
+	PointsBalance     int                `json:"points_balance"`
+	FavouriteProducts []FavouriteProduct `json:"favourite_products"`
+}
+
+type CustomerResponse struct {
+	HTTPResponse
+	Data *struct {
+		Profile *CustomerProfile `json:"profile,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type CustomerOrdersResponse struct {
+	HTTPResponse
+	Data *struct {
+		Orders []*entity.Order `json:"orders"`
+	} `json:"data,omitempty"`
+}
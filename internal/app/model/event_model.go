@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// DefaultStreamTokenTTL is the lifetime of a stream token. The token is only checked when the
+// stream is opened, a client requests a new one when a reconnect is refused.
+const DefaultStreamTokenTTL = 5 * time.Minute
+
+// StreamToken authorizes a front-of-house screen to open the order event stream. It is signed by
+// the service and expires, so the client API token does not have to be put in the URL of the
+// stream.
+type StreamToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type StreamTokenResponse struct {
+	HTTPResponse
+	Data *struct {
+		StreamToken *StreamToken `json:"stream_token,omitempty"`
+	} `json:"data,omitempty"`
+}
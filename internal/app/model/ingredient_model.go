@@ -0,0 +1,115 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"sort"
+)
+
+type IngredientRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Unit string `json:"unit" validate:"required,max=20"`
+}
+
+type IngredientResponse struct {
+	HTTPResponse
+	Data *struct {
+		Ingredient *entity.Ingredient `json:"ingredient,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListIngredientResponse struct {
+	HTTPResponse
+	Data *struct {
+		Ingredients []*entity.Ingredient `json:"ingredients"`
+	} `json:"data,omitempty"`
+}
+
+type RecipeItemRequest struct {
+	IngredientID uint    `json:"ingredient_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"gt=0"`
+}
+
+// RecipeRequest replaces the recipe of a product, an empty list removes it.
+type RecipeRequest struct {
+	Items []RecipeItemRequest `json:"items" validate:"dive"`
+}
+
+type RecipeResponse struct {
+	HTTPResponse
+	Data *struct {
+		Items []*entity.RecipeItem `json:"items"`
+	} `json:"data,omitempty"`
+}
+
+// IngredientUsage is the quantity of an ingredient used on a day, Date is empty for the total
+// of the whole report.
+type IngredientUsage struct {
+	Date         string  `json:"date,omitempty"`
+	IngredientID uint    `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Quantity     float64 `json:"quantity"`
+}
+
+// ConsumptionReport is the ingredient usage of completed orders per day and per ingredient.
+type ConsumptionReport struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Days   []IngredientUsage `json:"days"`
+	Totals []IngredientUsage `json:"totals"`
+}
+
+type ConsumptionReportResponse struct {
+	HTTPResponse
+	Data *struct {
+		Report *ConsumptionReport `json:"report,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// IngredientQuantities returns the quantity of every ingredient used by the order lines according
+// to the recipes of their products. Products without a recipe use no ingredients.
+func IngredientQuantities(details []entity.OrderDetail, recipes []*entity.RecipeItem) map[uint]float64 {
+	ordered := StockQuantities(details)
+
+	quantities := make(map[uint]float64)
+	for _, item := range recipes {
+		if quantity, ok := ordered[item.ProductID]; ok {
+			quantities[item.IngredientID] += item.Quantity * float64(quantity)
+		}
+	}
+	return quantities
+}
+
+// NewConsumptionReport creates the report of a date range from the daily usage, adding the total
+// of every ingredient ordered by name.
+func NewConsumptionReport(dateRange *DateRange, days []IngredientUsage) *ConsumptionReport {
+	totals := []IngredientUsage{}
+	index := make(map[uint]int)
+	for _, day := range days {
+		i, ok := index[day.IngredientID]
+		if !ok {
+			i = len(totals)
+			index[day.IngredientID] = i
+			totals = append(totals, IngredientUsage{IngredientID: day.IngredientID, Name: day.Name, Unit: day.Unit})
+		}
+		totals[i].Quantity += day.Quantity
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Name != totals[j].Name {
+			return totals[i].Name < totals[j].Name
+		}
+		return totals[i].IngredientID < totals[j].IngredientID
+	})
+
+	if days == nil {
+		days = []IngredientUsage{}
+	}
+
+	return &ConsumptionReport{
+		From:   dateRange.FirstDay(),
+		To:     dateRange.LastDay(),
+		Days:   days,
+		Totals: totals,
+	}
+}
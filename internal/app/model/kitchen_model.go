@@ -0,0 +1,99 @@
+package model
+
+import "maqhaa/order_service/internal/app/entity"
+
+const (
+	PrepStatusPending        = 0
+	PrepStatusPendingMessage = "Pending"
+	PrepStatusStarted        = 1
+	PrepStatusStartedMessage = "Started"
+	PrepStatusReady          = 2
+	PrepStatusReadyMessage   = "Ready"
+)
+
+// KitchenOrderStatus returns the order status implied by the preparation state of its lines.
+// An order moves to Processing once any line is started and to Success once every line is
+// ready. The current status is kept when none of the rules apply or the order is already
+// finished or cancelled.
+func KitchenOrderStatus(current int, details []entity.OrderDetail) int {
+	if len(details) == 0 || current >= OrderStatusSuccess {
+		return current
+	}
+
+	switch KitchenTicketStatus(details) {
+	case PrepStatusReady:
+		return OrderStatusSuccess
+	case PrepStatusStarted:
+		if current < OrderStatusProcessing {
+			return OrderStatusProcessing
+		}
+	}
+
+	return current
+}
+
+// KitchenTicketStatus returns the combined preparation status of a set of lines, e.g. the lines
+// of a station ticket: Ready when all are ready and Started when any has been started.
+func KitchenTicketStatus(details []entity.OrderDetail) int {
+	if len(details) == 0 {
+		return PrepStatusPending
+	}
+
+	allReady := true
+	anyStarted := false
+	for _, detail := range details {
+		if detail.PrepStatus != PrepStatusReady {
+			allReady = false
+		}
+		if detail.PrepStatus != PrepStatusPending {
+			anyStarted = true
+		}
+	}
+
+	if allReady {
+		return PrepStatusReady
+	}
+	if anyStarted {
+		return PrepStatusStarted
+	}
+	return PrepStatusPending
+}
+
+type KitchenQueueGroup struct {
+	Status     int             `json:"status"`
+	StatusText string          `json:"status_text"`
+	Orders     []*entity.Order `json:"orders"`
+}
+
+type KitchenQueueResponse struct {
+	HTTPResponse
+	Data *struct {
+		Queue []KitchenQueueGroup `json:"queue"`
+	} `json:"data,omitempty"`
+}
+
+type KitchenStationRequest struct {
+	Name        string `json:"name" validate:"required"`
+	CategoryIDs []uint `json:"category_ids" validate:"required,min=1"`
+}
+
+type KitchenStationResponse struct {
+	HTTPResponse
+	Data *struct {
+		Station *entity.KitchenStation `json:"station,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListKitchenStationResponse struct {
+	HTTPResponse
+	Data *struct {
+		Stations []*entity.KitchenStation `json:"stations"`
+	} `json:"data,omitempty"`
+}
+
+type KitchenTicketResponse struct {
+	HTTPResponse
+	Data *struct {
+		Tickets []*entity.KitchenTicket `json:"tickets"`
+	} `json:"data,omitempty"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"math"
+	"time"
+)
+
+const (
+	// Credits of the loyalty ledger
+	LoyaltyEntryEarn   = "earn"
+	LoyaltyEntryRefund = "refund"
+
+	// Debits of the loyalty ledger
+	LoyaltyEntryRedeem  = "redeem"
+	LoyaltyEntryReverse = "reverse"
+
+	LoyaltyLedgerLimit = 50
+)
+
+// LoyaltyPoints returns the points earned by spending amount, a point per full
+// LoyaltySpendPerPoint. No points are earned when loyalty is disabled.
+func LoyaltyPoints(setting *entity.ClientSetting, amount float64) int {
+	if setting.LoyaltySpendPerPoint <= 0 || amount <= 0 {
+		return 0
+	}
+
+	// The epsilon keeps an exact multiple from being rounded down
+	return int(math.Floor(amount/setting.LoyaltySpendPerPoint + 1e-9))
+}
+
+// LoyaltyPointsAmount returns the amount paid by redeeming points, rounded to the minor units of the
+// currency of the client.
+func LoyaltyPointsAmount(setting *entity.ClientSetting, points int) float64 {
+	return RoundAmount(float64(points)*setting.LoyaltyPointValue, setting.CurrencyCode)
+}
+
+// LoyaltyExpiry returns when points credited at now expire, or nil when points do not expire.
+func LoyaltyExpiry(setting *entity.ClientSetting, now time.Time) *time.Time {
+	if setting.LoyaltyExpiryDays <= 0 {
+		return nil
+	}
+
+	expiresAt := now.AddDate(0, 0, setting.LoyaltyExpiryDays)
+	return &expiresAt
+}
+
+// CustomerPoints is the points balance of a customer with the latest entries of the ledger.
+type CustomerPoints struct {
+	Balance int                     `json:"balance"`
+	Ledger  []*entity.LoyaltyLedger `json:"ledger"`
+}
+
+type CustomerPointsResponse struct {
+	HTTPResponse
+	Data *struct {
+		Points *CustomerPoints `json:"points,omitempty"`
+	} `json:"data,omitempty"`
+}
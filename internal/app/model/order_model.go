@@ -1,6 +1,9 @@
 package model
 
-import "maqhaa/order_service/internal/app/entity"
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"time"
+)
 
 const (
 	OrderStatusIncoming          = 1
@@ -11,13 +14,53 @@ const (
 	OrderStatusProcessingMessage = "Processing"
 	OrderStatusSuccess           = 4
 	OrderStatusSuccessMessage    = "Success"
+	OrderStatusCancelled         = 5
+	OrderStatusCancelledMessage  = "Cancelled"
 )
 
+// OrderStatusText returns the display text of an order status.
+func OrderStatusText(status int) string {
+	switch status {
+	case OrderStatusIncoming:
+		return OrderStatusIncomingMessage
+	case OrderStatusPaid:
+		return OrderStatusPaidMessage
+	case OrderStatusProcessing:
+		return OrderStatusProcessingMessage
+	case OrderStatusSuccess:
+		return OrderStatusSuccessMessage
+	case OrderStatusCancelled:
+		return OrderStatusCancelledMessage
+	default:
+		return "Unknown"
+	}
+}
+
+// CanChangeOrderStatus reports whether an order can move to a status. Orders move forward through
+// Paid, Processing and Success and can be cancelled until they are finished. Orders waiting for
+// release to the kitchen can be paid in advance but not prepared.
+func CanChangeOrderStatus(order *entity.Order, status int) bool {
+	if order.Status >= OrderStatusSuccess {
+		return false
+	}
+	if status == OrderStatusCancelled {
+		return true
+	}
+	if status <= order.Status {
+		return false
+	}
+
+	return order.ReleasedAt != nil || status < OrderStatusProcessing
+}
+
 type OrderRequest struct {
 	ID           uint          `json:"order_id"`
 	ClientID     uint          `json:"client_id" validate:"required"`
 	CustomerName string        `json:"customer_name" validate:"required"`
-	PhoneNumber  string        `json:"phone_number"`
+	PhoneNumber  string        `json:"phone_number" validate:"omitempty,phone"`
+	PromoCode    string        `json:"promo_code"`
+	RedeemPoints int           `json:"redeem_points" validate:"gte=0"`
+	ScheduledAt  *time.Time    `json:"scheduled_at"`
 	Total        float64       `json:"total" validate:"required,gt=0"`
 	Orders       []OrderDetail `validate:"required,dive"`
 }
@@ -30,6 +73,11 @@ type OrderDetail struct {
 	Total     float64 `json:"total" validate:"required,gt=0"`
 }
 
+type UpdateStatusRequest struct {
+	Status        int    `json:"status" validate:"required,oneof=2 3 4"`
+	PaymentMethod string `json:"payment_method" validate:"omitempty,oneof=cash card qris transfer other"`
+}
+
 type OrderResponse struct {
 	HTTPResponse
 	Data *struct {
@@ -38,9 +86,17 @@ type OrderResponse struct {
 	} `json:"data,omitempty"`
 }
 
+type ListOrderResponse struct {
+	HTTPResponse
+	Data *struct {
+		Orders []*entity.Order `json:"orders"`
+	} `json:"data,omitempty"`
+}
+
 type GetOrderResponse struct {
 	HTTPResponse
 	Data *struct {
-		Order *entity.Order `json:"order,omitempty"`
+		Order   *entity.Order `json:"order,omitempty"`
+		Amounts *OrderAmounts `json:"amounts,omitempty"`
 	} `json:"data,omitempty"`
 }
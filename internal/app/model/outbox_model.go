@@ -0,0 +1,12 @@
+package model
+
+const (
+	// OutboxSinkPublisher is the configured publisher of order events to other services.
+	OutboxSinkPublisher = "publisher"
+	// OutboxSinkWebhook fans order events out to the webhook subscriptions of partners.
+	OutboxSinkWebhook = "webhook"
+)
+
+// OutboxSinks are the sinks every order event is written for. Each sink is relayed on its own,
+// so a sink that is down does not hold back the others.
+var OutboxSinks = []string{OutboxSinkPublisher, OutboxSinkWebhook}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultCountry is the country of local phone numbers of clients without a country setting.
+const DefaultCountry = "ID"
+
+// ErrInvalidPhoneNumber is returned for a phone number that can not be written in E.164.
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+type phoneCountry struct {
+	callingCode string
+	trunkPrefix string
+}
+
+// phoneCountries are the countries clients can set as their default country, by ISO 3166 code.
+var phoneCountries = map[string]phoneCountry{
+	"AU": {callingCode: "61", trunkPrefix: "0"},
+	"GB": {callingCode: "44", trunkPrefix: "0"},
+	"ID": {callingCode: "62", trunkPrefix: "0"},
+	"JP": {callingCode: "81", trunkPrefix: "0"},
+	"MY": {callingCode: "60", trunkPrefix: "0"},
+	"PH": {callingCode: "63", trunkPrefix: "0"},
+	"SG": {callingCode: "65"},
+	"TH": {callingCode: "66", trunkPrefix: "0"},
+	"US": {callingCode: "1", trunkPrefix: "1"},
+	"VN": {callingCode: "84", trunkPrefix: "0"},
+}
+
+// IsPhoneCountry reports whether local phone numbers of the country can be normalised.
+func IsPhoneCountry(country string) bool {
+	_, ok := phoneCountries[strings.ToUpper(country)]
+	return ok
+}
+
+// ParsePhoneNumber normalises a phone number to E.164, e.g. "0812-3456-7890" to "+6281234567890"
+// for a client in Indonesia. International numbers, written with "+", "00" or the calling code of
+// the country, keep their country. Other numbers are local numbers of the country.
+func ParsePhoneNumber(phoneNumber string, country string) (string, error) {
+	number := NormalizePhoneNumber(phoneNumber)
+	if number == "" {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	if !strings.HasPrefix(number, "+") {
+		local, ok := phoneCountries[strings.ToUpper(country)]
+		if !ok {
+			return "", ErrInvalidPhoneNumber
+		}
+
+		switch {
+		case local.trunkPrefix != "" && strings.HasPrefix(number, local.trunkPrefix) && len(number)-len(local.trunkPrefix) >= minNationalDigits:
+			number = "+" + local.callingCode + number[len(local.trunkPrefix):]
+		case strings.HasPrefix(number, local.callingCode) && len(number)-len(local.callingCode) >= minNationalDigits:
+			number = "+" + number
+		default:
+			number = "+" + local.callingCode + number
+		}
+	}
+
+	if !isE164(number) {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	return number, nil
+}
+
+// ValidPhoneNumber reports whether a phone number only has digits and formatting characters and
+// can be normalised. Local numbers are checked as numbers of DefaultCountry, the country of the
+// client is only known when the number is normalised.
+func ValidPhoneNumber(phoneNumber string) bool {
+	for i, r := range strings.TrimSpace(phoneNumber) {
+		switch {
+		case r >= '0' && r <= '9', r == ' ', r == '-', r == '.', r == '(', r == ')':
+		case r == '+' && i == 0:
+		default:
+			return false
+		}
+	}
+
+	_, err := ParsePhoneNumber(phoneNumber, DefaultCountry)
+	return err == nil
+}
+
+// minNationalDigits is the shortest national number, it tells a calling code or trunk prefix
+// apart from the first digits of a short local number.
+const minNationalDigits = 7
+
+// isE164 reports whether a number is "+" followed by up to 15 digits, the first one not zero.
+func isE164(number string) bool {
+	digits := strings.TrimPrefix(number, "+")
+	if len(digits) == len(number) || len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return false
+	}
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
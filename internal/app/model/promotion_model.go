@@ -0,0 +1,43 @@
+package model
+
+import "maqhaa/order_service/internal/app/entity"
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypeBOGO       = "bogo"
+	PromotionTypeBundle     = "bundle"
+)
+
+type PromotionRequest struct {
+	Code             string  `json:"code" validate:"required_without=AutoApply"`
+	Name             string  `json:"name" validate:"required"`
+	Type             string  `json:"type" validate:"required,oneof=percentage fixed bogo bundle"`
+	Value            float64 `json:"value" validate:"gte=0"`
+	MaxDiscount      float64 `json:"max_discount" validate:"gte=0"`
+	BuyQuantity      int     `json:"buy_quantity" validate:"gte=0"`
+	GetQuantity      int     `json:"get_quantity" validate:"gte=0"`
+	MinSpend         float64 `json:"min_spend" validate:"gte=0"`
+	StartAt          string  `json:"start_at" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	EndAt            string  `json:"end_at" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	HourStart        int     `json:"hour_start" validate:"gte=0,lte=24"`
+	HourEnd          int     `json:"hour_end" validate:"gte=0,lte=24"`
+	UsageLimit       int     `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int     `json:"per_customer_limit" validate:"gte=0"`
+	AutoApply        bool    `json:"auto_apply"`
+	ProductIDs       []uint  `json:"product_ids"`
+}
+
+type PromotionResponse struct {
+	HTTPResponse
+	Data *struct {
+		Promotion *entity.Promotion `json:"promotion,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListPromotionResponse struct {
+	HTTPResponse
+	Data *struct {
+		Promotions []*entity.Promotion `json:"promotions"`
+	} `json:"data,omitempty"`
+}
@@ -0,0 +1,315 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+// MaxReportDays is the longest date range of a report.
+const MaxReportDays = 92
+
+const (
+	// RankingDefaultLimit is the number of products of a product ranking without a limit.
+	RankingDefaultLimit = 10
+	// RankingMaxLimit is the largest number of products of a product ranking.
+	RankingMaxLimit = 100
+
+	RankingByQuantity = "quantity"
+	RankingByRevenue  = "revenue"
+)
+
+const reportDateLayout = "2006-01-02"
+
+// ErrInvalidDateRange is returned for report dates that are not YYYY-MM-DD, end before they
+// start or span more than MaxReportDays.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// DateRange is the period of a report. From is the start of the first day and To the start of the
+// day after the last day, both in the time zone of the service.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// FirstDay returns the first day of the range as YYYY-MM-DD.
+func (r DateRange) FirstDay() string {
+	return r.From.Format(reportDateLayout)
+}
+
+// LastDay returns the last day of the range as YYYY-MM-DD.
+func (r DateRange) LastDay() string {
+	return r.To.AddDate(0, 0, -1).Format(reportDateLayout)
+}
+
+// ParseDateRange parses the inclusive YYYY-MM-DD days of a report in the location of now. A
+// missing first day is today and a missing last day is the first day.
+func ParseDateRange(from, to string, now time.Time) (*DateRange, error) {
+	first := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if from != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, from, now.Location())
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		first = parsed
+	}
+
+	last := first
+	if to != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, to, now.Location())
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		last = parsed
+	}
+
+	if last.Before(first) || last.After(first.AddDate(0, 0, MaxReportDays-1)) {
+		return nil, ErrInvalidDateRange
+	}
+
+	return &DateRange{From: first, To: last.AddDate(0, 0, 1)}, nil
+}
+
+// DailySalesRow is the aggregate of the orders of a client with one status created on a day.
+// LineDiscounts are the discounts given on the order lines and PromoDiscount the discounts of
+// promotions on the orders.
+type DailySalesRow struct {
+	Date          string
+	Status        int
+	OrderCount    int
+	GrossSales    float64
+	LineDiscounts float64
+	PromoDiscount float64
+	ServiceCharge float64
+	Tax           float64
+	Total         float64
+}
+
+// StatusSales is the number and total of the orders with a status.
+type StatusSales struct {
+	Status     int     `json:"status"`
+	StatusText string  `json:"status_text"`
+	OrderCount int     `json:"order_count"`
+	Total      float64 `json:"total"`
+}
+
+// DailySales is the sales summary of a business day, Date is empty for the summary of the whole
+// report. Cancelled orders only count in the breakdown by status. Gross sales are the order lines
+// at their price, net sales are after discounts and before service charge and tax, and the
+// average ticket is the net sales per order.
+type DailySales struct {
+	Date          string        `json:"date,omitempty"`
+	OrderCount    int           `json:"order_count"`
+	GrossSales    float64       `json:"gross_sales"`
+	Discounts     float64       `json:"discounts"`
+	NetSales      float64       `json:"net_sales"`
+	ServiceCharge float64       `json:"service_charge"`
+	Tax           float64       `json:"tax"`
+	Total         float64       `json:"total"`
+	AverageTicket float64       `json:"average_ticket"`
+	Statuses      []StatusSales `json:"statuses"`
+}
+
+// DailyReport is the sales summary of a client per business day of a date range.
+type DailyReport struct {
+	ClientID     uint          `json:"client_id"`
+	CurrencyCode string        `json:"currency_code"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Days         []*DailySales `json:"days"`
+	Summary      *DailySales   `json:"summary"`
+}
+
+type DailyReportResponse struct {
+	HTTPResponse
+	Data *struct {
+		Report *DailyReport `json:"report,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// NewDailyReport creates the report of a date range from the aggregates per day and status. Every
+// day of the range is listed, days without orders have zero sales.
+func NewDailyReport(clientID uint, currencyCode string, dateRange *DateRange, rows []DailySalesRow) *DailyReport {
+	report := &DailyReport{
+		ClientID:     clientID,
+		CurrencyCode: currencyCode,
+		From:         dateRange.FirstDay(),
+		To:           dateRange.LastDay(),
+		Days:         []*DailySales{},
+		Summary:      newDailySales(""),
+	}
+
+	days := make(map[string]*DailySales)
+	for day := dateRange.From; day.Before(dateRange.To); day = day.AddDate(0, 0, 1) {
+		sales := newDailySales(day.Format(reportDateLayout))
+		days[sales.Date] = sales
+		report.Days = append(report.Days, sales)
+	}
+
+	for _, row := range rows {
+		if sales, ok := days[row.Date]; ok {
+			sales.add(row)
+			report.Summary.add(row)
+		}
+	}
+
+	for _, sales := range report.Days {
+		sales.round()
+	}
+	report.Summary.round()
+
+	return report
+}
+
+func newDailySales(date string) *DailySales {
+	sales := &DailySales{Date: date}
+	for status := OrderStatusIncoming; status <= OrderStatusCancelled; status++ {
+		sales.Statuses = append(sales.Statuses, StatusSales{Status: status, StatusText: OrderStatusText(status)})
+	}
+	return sales
+}
+
+func (s *DailySales) add(row DailySalesRow) {
+	if row.Status >= OrderStatusIncoming && row.Status <= OrderStatusCancelled {
+		s.Statuses[row.Status-OrderStatusIncoming].OrderCount += row.OrderCount
+		s.Statuses[row.Status-OrderStatusIncoming].Total += row.Total
+	}
+
+	if row.Status == OrderStatusCancelled {
+		return
+	}
+
+	s.OrderCount += row.OrderCount
+	s.GrossSales += row.GrossSales
+	s.Discounts += row.LineDiscounts + row.PromoDiscount
+	s.ServiceCharge += row.ServiceCharge
+	s.Tax += row.Tax
+	s.Total += row.Total
+}
+
+func (s *DailySales) round() {
+	s.GrossSales = roundReportAmount(s.GrossSales)
+	s.Discounts = roundReportAmount(s.Discounts)
+	s.NetSales = roundReportAmount(s.GrossSales - s.Discounts)
+	s.ServiceCharge = roundReportAmount(s.ServiceCharge)
+	s.Tax = roundReportAmount(s.Tax)
+	s.Total = roundReportAmount(s.Total)
+	if s.OrderCount > 0 {
+		s.AverageTicket = roundReportAmount(s.NetSales / float64(s.OrderCount))
+	}
+	for i := range s.Statuses {
+		s.Statuses[i].Total = roundReportAmount(s.Statuses[i].Total)
+	}
+}
+
+func roundReportAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// ProductSales is the sales of a product in a date range. Revenue is the total of its order
+// lines after line discounts.
+type ProductSales struct {
+	Rank        int     `json:"rank"`
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Revenue     float64 `json:"revenue"`
+	OrderCount  int     `json:"order_count"`
+}
+
+// ProductRanking is the best selling products of a client in a date range. Cancelled orders are
+// not counted.
+type ProductRanking struct {
+	ClientID     uint           `json:"client_id"`
+	CurrencyCode string         `json:"currency_code"`
+	From         string         `json:"from"`
+	To           string         `json:"to"`
+	SortBy       string         `json:"sort_by"`
+	Products     []ProductSales `json:"products"`
+}
+
+type ProductRankingResponse struct {
+	HTTPResponse
+	Data *struct {
+		Ranking *ProductRanking `json:"ranking,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// IsRankingSort reports whether products can be ranked by sortBy.
+func IsRankingSort(sortBy string) bool {
+	return sortBy == RankingByQuantity || sortBy == RankingByRevenue
+}
+
+// CSVRecords returns the ranking as CSV records with a header.
+func (r *ProductRanking) CSVRecords() [][]string {
+	records := [][]string{{"rank", "product_id", "product_name", "quantity", "revenue", "order_count"}}
+	for _, product := range r.Products {
+		records = append(records, []string{
+			strconv.Itoa(product.Rank),
+			strconv.FormatUint(uint64(product.ProductID), 10),
+			product.ProductName,
+			strconv.Itoa(product.Quantity),
+			strconv.FormatFloat(product.Revenue, 'f', -1, 64),
+			strconv.Itoa(product.OrderCount),
+		})
+	}
+	return records
+}
+
+// HeatmapBucket is the number and total of the orders created in a quarter of an hour starting at
+// Start. Quarters are converted to the time zone of the client exactly, also for time zones
+// that are not a whole number of hours away from the service.
+type HeatmapBucket struct {
+	Start      time.Time
+	OrderCount int
+	Sales      float64
+}
+
+// HourlyHeatmap is the number of orders and the sales of a client by day of the week and hour of
+// the day. Orders and Sales are indexed by weekday, 0 for Sunday, and hour in the time zone of
+// the client.
+type HourlyHeatmap struct {
+	ClientID     uint           `json:"client_id"`
+	CurrencyCode string         `json:"currency_code"`
+	Timezone     string         `json:"timezone"`
+	From         string         `json:"from"`
+	To           string         `json:"to"`
+	Statuses     []int          `json:"statuses"`
+	Orders       [7][24]int     `json:"orders"`
+	Sales        [7][24]float64 `json:"sales"`
+}
+
+type HourlyHeatmapResponse struct {
+	HTTPResponse
+	Data *struct {
+		Heatmap *HourlyHeatmap `json:"heatmap,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// NewHourlyHeatmap creates the heatmap of a date range in the location of the client from the
+// quarter hour buckets of its orders.
+func NewHourlyHeatmap(clientID uint, currencyCode string, loc *time.Location, dateRange *DateRange, statuses []int, buckets []HeatmapBucket) *HourlyHeatmap {
+	heatmap := &HourlyHeatmap{
+		ClientID:     clientID,
+		CurrencyCode: currencyCode,
+		Timezone:     loc.String(),
+		From:         dateRange.FirstDay(),
+		To:           dateRange.LastDay(),
+		Statuses:     statuses,
+	}
+
+	for _, bucket := range buckets {
+		start := bucket.Start.In(loc)
+		heatmap.Orders[start.Weekday()][start.Hour()] += bucket.OrderCount
+		heatmap.Sales[start.Weekday()][start.Hour()] += bucket.Sales
+	}
+
+	for weekday := range heatmap.Sales {
+		for hour := range heatmap.Sales[weekday] {
+			heatmap.Sales[weekday][hour] = roundReportAmount(heatmap.Sales[weekday][hour])
+		}
+	}
+
+	return heatmap
+}
@@ -9,6 +9,27 @@ type HTTPResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// FieldError is a violation of a validation rule by one field of a request. Field is the JSON
+// path of the field, e.g. Orders[0].quantity.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ProblemDetails is the RFC 7807 body of error responses. Code and Message repeat the AppError
+// so clients that read the code of the envelope keep working.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     int          `json:"code"`
+	Message  string       `json:"message"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
 // NewHTTPResponse creates a new HTTPResponse instance with the provided code, message, and optional data.
 func NewHTTPResponse(code int, message string, data interface{}) *HTTPResponse {
 	return &HTTPResponse{
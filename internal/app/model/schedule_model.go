@@ -0,0 +1,54 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"time"
+)
+
+const (
+	// DefaultScheduleLeadMinutes is how long before pickup a scheduled order is released to the
+	// kitchen when the client did not configure a lead time.
+	DefaultScheduleLeadMinutes = 15
+
+	// MaxScheduleDays is how far ahead an order can be scheduled.
+	MaxScheduleDays = 7
+)
+
+type OpeningHourRequest struct {
+	Weekday   int    `json:"weekday" validate:"gte=0,lte=6"`
+	OpenTime  string `json:"open_time" validate:"required,datetime=15:04"`
+	CloseTime string `json:"close_time" validate:"required,datetime=15:04"`
+}
+
+type OpeningHoursRequest struct {
+	Hours []OpeningHourRequest `json:"hours" validate:"dive"`
+}
+
+type OpeningHoursResponse struct {
+	HTTPResponse
+	Data *struct {
+		Hours []*entity.ClientOpeningHour `json:"hours"`
+	} `json:"data,omitempty"`
+}
+
+// ScheduleLead returns how long before pickup the scheduled orders of a client are released.
+func ScheduleLead(setting *entity.ClientSetting) time.Duration {
+	if setting.ScheduleLeadMinutes <= 0 {
+		return DefaultScheduleLeadMinutes * time.Minute
+	}
+	return time.Duration(setting.ScheduleLeadMinutes) * time.Minute
+}
+
+// IsScheduleDue reports whether a scheduled order must be released to the kitchen at now.
+func IsScheduleDue(setting *entity.ClientSetting, scheduledAt time.Time, now time.Time) bool {
+	return !now.Before(scheduledAt.Add(-ScheduleLead(setting)))
+}
+
+// ValidScheduledTime reports whether an order can be scheduled for pickup at scheduledAt: in the
+// future, at most MaxScheduleDays ahead and while the client is open.
+func ValidScheduledTime(hours []*entity.ClientOpeningHour, holidays []*entity.ClientHoliday, scheduledAt time.Time, now time.Time) bool {
+	if !scheduledAt.After(now) || scheduledAt.After(now.AddDate(0, 0, MaxScheduleDays)) {
+		return false
+	}
+	return IsWithinOpeningHours(hours, holidays, scheduledAt.In(now.Location()))
+}
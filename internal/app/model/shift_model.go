@@ -0,0 +1,119 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"math"
+)
+
+const (
+	PaymentMethodCash     = "cash"
+	PaymentMethodCard     = "card"
+	PaymentMethodQRIS     = "qris"
+	PaymentMethodTransfer = "transfer"
+	PaymentMethodOther    = "other"
+)
+
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+)
+
+const (
+	// CashMovementSale is the cash taken for an order paid in cash.
+	CashMovementSale = "sale"
+	// CashMovementRefund is the cash paid back for a cancelled order that was paid in cash.
+	CashMovementRefund = "refund"
+	// CashMovementAdjustment is the change in the cash taken for an order edited after it was
+	// paid in cash.
+	CashMovementAdjustment = "adjustment"
+)
+
+type OpenShiftRequest struct {
+	OpeningCash float64 `json:"opening_cash" validate:"gte=0"`
+	Cashier     string  `json:"cashier" validate:"required,max=100"`
+	Note        string  `json:"note" validate:"max=255"`
+}
+
+type CloseShiftRequest struct {
+	CountedCash *float64 `json:"counted_cash" validate:"required,gte=0"`
+	Cashier     string   `json:"cashier" validate:"required,max=100"`
+	Note        string   `json:"note" validate:"max=255"`
+}
+
+// CashTotals is the sum of the cash movements of a shift by type. Refunds are positive.
+type CashTotals struct {
+	Sales       float64
+	Refunds     float64
+	Adjustments float64
+}
+
+// PaymentSummary is the number and total of the orders of a shift paid with one method.
+type PaymentSummary struct {
+	PaymentMethod string  `json:"payment_method"`
+	OrderCount    int     `json:"order_count"`
+	Total         float64 `json:"total"`
+}
+
+// ShiftReport is the closing report of a shift, its cash reconciliation and the payments of the
+// orders paid during the shift that were not cancelled.
+type ShiftReport struct {
+	Shift        *entity.Shift    `json:"shift"`
+	CurrencyCode string           `json:"currency_code"`
+	OrderCount   int              `json:"order_count"`
+	Total        float64          `json:"total"`
+	Payments     []PaymentSummary `json:"payments"`
+}
+
+type ShiftResponse struct {
+	HTTPResponse
+	Data *struct {
+		Report *ShiftReport `json:"report,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListShiftResponse struct {
+	HTTPResponse
+	Data *struct {
+		Shifts []*entity.Shift `json:"shifts"`
+	} `json:"data,omitempty"`
+}
+
+// OrderAmountDue returns the amount of an order that is paid, its total less the amount paid by
+// redeeming points.
+func OrderAmountDue(order *entity.Order) float64 {
+	return roundReportAmount(math.Max(order.Total-order.PointsAmount, 0))
+}
+
+// SettleShift sets the cash totals of a shift and the cash expected in the register, the opening
+// cash plus the cash taken less the cash paid back. The variance is set once the cash is counted.
+func SettleShift(shift *entity.Shift, totals CashTotals) {
+	shift.CashSales = roundReportAmount(totals.Sales)
+	shift.CashRefunds = roundReportAmount(totals.Refunds)
+	shift.CashAdjustments = roundReportAmount(totals.Adjustments)
+	shift.ExpectedCash = roundReportAmount(shift.OpeningCash + totals.Sales + totals.Adjustments - totals.Refunds)
+
+	shift.Variance = nil
+	if shift.CountedCash != nil {
+		variance := roundReportAmount(*shift.CountedCash - shift.ExpectedCash)
+		shift.Variance = &variance
+	}
+}
+
+// NewShiftReport returns the closing report of a shift with the payments of its orders by method.
+func NewShiftReport(shift *entity.Shift, currency string, payments []PaymentSummary) *ShiftReport {
+	report := &ShiftReport{
+		Shift:        shift,
+		CurrencyCode: currency,
+		Payments:     []PaymentSummary{},
+	}
+
+	for _, payment := range payments {
+		payment.Total = roundReportAmount(payment.Total)
+		report.OrderCount += payment.OrderCount
+		report.Total += payment.Total
+		report.Payments = append(report.Payments, payment)
+	}
+	report.Total = roundReportAmount(report.Total)
+
+	return report
+}
@@ -0,0 +1,76 @@
+package model
+
+import "maqhaa/order_service/internal/app/entity"
+
+type StockRequest struct {
+	OnHand int `json:"on_hand" validate:"gte=0"`
+}
+
+type StockResponse struct {
+	HTTPResponse
+	Data *struct {
+		Stock *entity.ProductStock `json:"stock,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListStockResponse struct {
+	HTTPResponse
+	Data *struct {
+		Stocks []*entity.ProductStock `json:"stocks"`
+	} `json:"data,omitempty"`
+}
+
+// StockShortage is an order line asking for more of a product than is available. Line is the
+// index of the line in the order.
+type StockShortage struct {
+	Line      int  `json:"line"`
+	ProductID uint `json:"product_id"`
+	Requested int  `json:"requested"`
+	Available int  `json:"available"`
+}
+
+// StockAvailable returns the quantity of a stock that is not reserved by open orders.
+func StockAvailable(stock *entity.ProductStock) int {
+	available := stock.OnHand - stock.Reserved
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// StockQuantities returns the quantity ordered of every product, summing the lines of a product.
+func StockQuantities(details []entity.OrderDetail) map[uint]int {
+	quantities := make(map[uint]int)
+	for _, detail := range details {
+		quantities[detail.ProductID] += detail.Quantity
+	}
+	return quantities
+}
+
+// StockShortages returns the lines of products whose ordered quantity exceeds the available stock,
+// in line order. Products without stock are not tracked and never short.
+func StockShortages(details []entity.OrderDetail, stocks map[uint]*entity.ProductStock) []StockShortage {
+	quantities := StockQuantities(details)
+
+	var shortages []StockShortage
+	for i, detail := range details {
+		stock, ok := stocks[detail.ProductID]
+		if !ok {
+			continue
+		}
+
+		available := StockAvailable(stock)
+		if quantities[detail.ProductID] <= available {
+			continue
+		}
+
+		shortages = append(shortages, StockShortage{
+			Line:      i,
+			ProductID: detail.ProductID,
+			Requested: quantities[detail.ProductID],
+			Available: available,
+		})
+	}
+
+	return shortages
+}
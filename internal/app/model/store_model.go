@@ -0,0 +1,251 @@
+package model
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"sort"
+	"time"
+)
+
+const (
+	StoreStateOpen    = "open"
+	StoreStateClosed  = "closed"
+	StoreStateHoliday = "holiday"
+	StoreStatePaused  = "paused"
+
+	// MaxPauseMinutes is the longest pause with an automatic resume, a week.
+	MaxPauseMinutes = 7 * 24 * 60
+
+	minutesPerDay = 24 * 60
+)
+
+type HolidayRequest struct {
+	Date      string `json:"date" validate:"required,datetime=2006-01-02"`
+	OpenTime  string `json:"open_time" validate:"omitempty,datetime=15:04"`
+	CloseTime string `json:"close_time" validate:"omitempty,datetime=15:04"`
+	Note      string `json:"note" validate:"max=255"`
+}
+
+type HolidayResponse struct {
+	HTTPResponse
+	Data *struct {
+		Holiday *entity.ClientHoliday `json:"holiday,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListHolidayResponse struct {
+	HTTPResponse
+	Data *struct {
+		Holidays []*entity.ClientHoliday `json:"holidays"`
+	} `json:"data,omitempty"`
+}
+
+// PauseRequest pauses or resumes new orders. A pause with minutes resumes by itself.
+type PauseRequest struct {
+	Paused  bool `json:"paused"`
+	Minutes int  `json:"minutes" validate:"gte=0,lte=10080"`
+}
+
+// StoreState tells whether a client accepts orders now. OpensAt is the next time orders are
+// accepted and ClosesAt the time an open client stops accepting orders, both are nil when unknown
+// within the scheduling window.
+type StoreState struct {
+	Open        bool       `json:"open"`
+	State       string     `json:"state"`
+	Note        string     `json:"note,omitempty"`
+	PausedUntil *time.Time `json:"paused_until"`
+	OpensAt     *time.Time `json:"opens_at"`
+	ClosesAt    *time.Time `json:"closes_at"`
+}
+
+type StoreStateResponse struct {
+	HTTPResponse
+	Data *struct {
+		State *StoreState `json:"state,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// openPeriod is a time span in which a client is open, end excluded.
+type openPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// IsOrdersPaused reports whether the client paused new orders at now.
+func IsOrdersPaused(setting *entity.ClientSetting, now time.Time) bool {
+	return setting.OrdersPaused && (setting.PausedUntil == nil || now.Before(*setting.PausedUntil))
+}
+
+// ValidOpeningHour reports whether an opening period starts before it closes. A close time of
+// 00:00 is midnight at the end of the day.
+func ValidOpeningHour(openTime, closeTime string) bool {
+	opens, ok := clockMinutes(openTime)
+	if !ok {
+		return false
+	}
+	closes, ok := clockMinutes(closeTime)
+	if !ok {
+		return false
+	}
+	if closes == 0 {
+		closes = minutesPerDay
+	}
+	return opens < closes
+}
+
+// IsWithinOpeningHours reports whether t falls in one of the opening periods of its date. Holidays
+// replace the weekly opening hours of their date and a client without weekly opening hours is
+// open on every other day.
+func IsWithinOpeningHours(hours []*entity.ClientOpeningHour, holidays []*entity.ClientHoliday, t time.Time) bool {
+	for _, period := range dayPeriods(hours, holidays, t) {
+		if !t.Before(period.start) && t.Before(period.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStoreState returns whether a client accepts orders at now, with the next opening or closing
+// time within MaxScheduleDays.
+func NewStoreState(setting *entity.ClientSetting, hours []*entity.ClientOpeningHour, holidays []*entity.ClientHoliday, now time.Time) *StoreState {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	windowEnd := today.AddDate(0, 0, MaxScheduleDays+1)
+
+	var periods []openPeriod
+	for day := today; day.Before(windowEnd); day = day.AddDate(0, 0, 1) {
+		periods = append(periods, dayPeriods(hours, holidays, day)...)
+	}
+	periods = mergePeriods(periods)
+
+	state := &StoreState{State: StoreStateClosed}
+	from := now
+
+	if IsOrdersPaused(setting, now) {
+		state.State = StoreStatePaused
+		state.PausedUntil = setting.PausedUntil
+		if setting.PausedUntil == nil {
+			return state
+		}
+		from = *setting.PausedUntil
+	} else {
+		for _, period := range periods {
+			if now.Before(period.start) || !now.Before(period.end) {
+				continue
+			}
+
+			state.Open = true
+			state.State = StoreStateOpen
+			if period.end.Before(windowEnd) {
+				closesAt := period.end
+				state.ClosesAt = &closesAt
+			}
+			return state
+		}
+
+		date := now.Format("2006-01-02")
+		for _, holiday := range holidays {
+			if holiday.Date == date {
+				state.State = StoreStateHoliday
+				state.Note = holiday.Note
+			}
+		}
+	}
+
+	for _, period := range periods {
+		if !period.end.After(from) {
+			continue
+		}
+
+		opensAt := period.start
+		if opensAt.Before(from) {
+			opensAt = from
+		}
+		state.OpensAt = &opensAt
+		break
+	}
+
+	return state
+}
+
+// dayPeriods returns the opening periods on the date of day, in the location of day.
+func dayPeriods(hours []*entity.ClientOpeningHour, holidays []*entity.ClientHoliday, day time.Time) []openPeriod {
+	date := day.Format("2006-01-02")
+	var periods []openPeriod
+
+	isHoliday := false
+	for _, holiday := range holidays {
+		if holiday.Date != date {
+			continue
+		}
+
+		isHoliday = true
+		if period, ok := clockPeriod(day, holiday.OpenTime, holiday.CloseTime); ok {
+			periods = append(periods, period)
+		}
+	}
+	if isHoliday {
+		return periods
+	}
+
+	if len(hours) == 0 {
+		period, _ := clockPeriod(day, "00:00", "00:00")
+		return []openPeriod{period}
+	}
+
+	for _, hour := range hours {
+		if hour.Weekday != int(day.Weekday()) {
+			continue
+		}
+		if period, ok := clockPeriod(day, hour.OpenTime, hour.CloseTime); ok {
+			periods = append(periods, period)
+		}
+	}
+
+	return periods
+}
+
+// clockPeriod returns the period between two HH:MM times on the date of day.
+func clockPeriod(day time.Time, openTime, closeTime string) (openPeriod, bool) {
+	if !ValidOpeningHour(openTime, closeTime) {
+		return openPeriod{}, false
+	}
+
+	opens, _ := clockMinutes(openTime)
+	closes, _ := clockMinutes(closeTime)
+	if closes == 0 {
+		closes = minutesPerDay
+	}
+
+	return openPeriod{
+		start: time.Date(day.Year(), day.Month(), day.Day(), 0, opens, 0, 0, day.Location()),
+		end:   time.Date(day.Year(), day.Month(), day.Day(), 0, closes, 0, 0, day.Location()),
+	}, true
+}
+
+// mergePeriods sorts periods and joins the ones that overlap or touch, so a client open until
+// midnight and from midnight is open without a break.
+func mergePeriods(periods []openPeriod) []openPeriod {
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+	var merged []openPeriod
+	for _, period := range periods {
+		last := len(merged) - 1
+		if last >= 0 && !period.start.After(merged[last].end) {
+			if period.end.After(merged[last].end) {
+				merged[last].end = period.end
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+
+	return merged
+}
+
+// clockMinutes returns the minutes since midnight of a HH:MM time.
+func clockMinutes(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
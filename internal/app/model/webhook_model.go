@@ -0,0 +1,43 @@
+package model
+
+import "maqhaa/order_service/internal/app/entity"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+type WebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=order.created order.edited order.status_changed order.cancelled order.released"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16"`
+}
+
+type WebhookResponse struct {
+	HTTPResponse
+	Data *struct {
+		Webhook *entity.WebhookSubscription `json:"webhook,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListWebhookResponse struct {
+	HTTPResponse
+	Data *struct {
+		Webhooks []*entity.WebhookSubscription `json:"webhooks"`
+	} `json:"data,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	HTTPResponse
+	Data *struct {
+		Delivery *entity.WebhookDelivery `json:"delivery,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type ListWebhookDeliveryResponse struct {
+	HTTPResponse
+	Data *struct {
+		Deliveries []*entity.WebhookDelivery `json:"deliveries"`
+	} `json:"data,omitempty"`
+}
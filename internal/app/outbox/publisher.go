@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/entity"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PublisherLog     = "log"
+	PublisherWebhook = "webhook"
+)
+
+// Message is the envelope of a published order event. Consumers use the ID to drop
+// duplicates, since an event can be delivered more than once.
+type Message struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	ClientID  uint            `json:"client_id"`
+	OrderID   uint            `json:"order_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func NewMessage(e *entity.OutboxEvent) Message {
+	return Message{
+		ID:        e.ID,
+		Type:      e.Type,
+		ClientID:  e.ClientID,
+		OrderID:   e.OrderID,
+		CreatedAt: e.CreatedAt,
+		Data:      json.RawMessage(e.Payload),
+	}
+}
+
+// Publisher delivers outbox messages to other services. A message is only marked as published
+// when Publish returns nil.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// LogPublisher writes the messages to the application log.
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, message Message) error {
+	logging.Log.WithFields(logrus.Fields{
+		"event_id": message.ID,
+		"type":     message.Type,
+		"order_id": message.OrderID,
+	}).Infof("Order event %s", message.Data)
+
+	return nil
+}
+
+// WebhookPublisher posts the messages as JSON to a single URL. Any response other than 2xx is
+// treated as a failure and the message is retried.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, message Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatUint(uint64(message.ID), 10))
+	req.Header.Set("X-Event-Type", message.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// MemoryPublisher keeps the messages in memory, it is meant for tests. Fail can be set to make
+// Publish return an error for selected messages.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+	Fail     func(message Message) error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, message Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Fail != nil {
+		if err := p.Fail(message); err != nil {
+			return err
+		}
+	}
+
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+// Messages returns the published messages in the order they were published.
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Message(nil), p.messages...)
+}
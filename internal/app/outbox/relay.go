@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+const (
+	DefaultInterval    = 5 * time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 10
+	DefaultBackoff     = 10 * time.Second
+
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff = 6 * time.Hour
+)
+
+// Relay publishes the pending outbox events of one sink. Events are marked as published after the publisher
+// accepted them, so an event is delivered at least once and may be delivered again when the
+// service stops in between. A failed event is retried with exponential backoff and is dead after
+// maxAttempts attempts, so events that keep failing do not hold back the rest of the outbox. Only
+// one relay should run per sink.
+type Relay struct {
+	outboxRepo  repository.OutboxRepository
+	sink        string
+	publisher   Publisher
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func NewRelay(outboxRepo repository.OutboxRepository, sink string, publisher Publisher, interval time.Duration, batchSize int, maxAttempts int, backoff time.Duration) *Relay {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	return &Relay{
+		outboxRepo:  outboxRepo,
+		sink:        sink,
+		publisher:   publisher,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// Run publishes pending events every interval until the context is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ProcessBatch(ctx, time.Now()); err != nil {
+			logging.Log.Errorf("Error relaying %s outbox events  %s", r.sink, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch publishes the events that are due at now and returns the number of published
+// events. Events of the same order are published in the order they were written: when an event
+// fails, the later events of that order wait until it is published or dead.
+func (r *Relay) ProcessBatch(ctx context.Context, now time.Time) (int, error) {
+	events, err := r.outboxRepo.GetPendingEvents(ctx, r.sink, now, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[uint]bool)
+	for _, e := range events {
+		if blocked[e.OrderID] {
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, NewMessage(e)); err != nil {
+			blocked[e.OrderID] = true
+			logging.Log.Errorf("Error publishing outbox event %d  %s", e.ID, err.Error())
+
+			e.Attempts++
+			e.LastError = err.Error()
+			if e.Attempts >= r.maxAttempts {
+				e.DeadAt = &now
+				logging.Log.Errorf("Outbox event %d is dead after %d attempts", e.ID, e.Attempts)
+			} else {
+				e.NextAttemptAt = now.Add(Backoff(r.backoff, e.Attempts))
+			}
+			if err := r.outboxRepo.MarkFailed(ctx, e); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		// When marking fails the event stays pending and is published again
+		if err := r.outboxRepo.MarkPublished(ctx, e.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts:
+// base, 2*base, 4*base and so on, capped at MaxBackoff.
+func Backoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrHolidayNotFound is returned when deleting a holiday the client does not have.
+var ErrHolidayNotFound = errors.New("holiday not found")
+
+type ClientRepository interface {
+	GetClientByToken(ctx context.Context, token string) (*entity.Client, error)
+	GetClientByID(ctx context.Context, clientID uint) (*entity.Client, error)
+	GetClientSetting(ctx context.Context, clientID uint) (*entity.ClientSetting, error)
+	SaveClientSetting(ctx context.Context, setting *entity.ClientSetting) (*entity.ClientSetting, error)
+	GetOpeningHours(ctx context.Context, clientID uint) ([]*entity.ClientOpeningHour, error)
+	SaveOpeningHours(ctx context.Context, clientID uint, hours []*entity.ClientOpeningHour) ([]*entity.ClientOpeningHour, error)
+	GetHolidays(ctx context.Context, clientID uint, from time.Time) ([]*entity.ClientHoliday, error)
+	AddHoliday(ctx context.Context, holiday *entity.ClientHoliday) (*entity.ClientHoliday, error)
+	DeleteHoliday(ctx context.Context, clientID uint, holidayID uint) error
+}
+
+type clientRepository struct {
+	db *gorm.DB
+}
+
+func NewClientRepository(db *gorm.DB) ClientRepository {
+	return &clientRepository{
+		db: db,
+	}
+}
+
+func (r *clientRepository) GetClientByToken(ctx context.Context, token string) (*entity.Client, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var client entity.Client
+
+	if err := r.db.Where("token = ? AND is_active = ?", token, true).First(&client).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetClientByToken  %s", err.Error())
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (r *clientRepository) GetClientByID(ctx context.Context, clientID uint) (*entity.Client, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var client entity.Client
+
+	if err := r.db.Where("id = ? AND is_active = ?", clientID, true).First(&client).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetClientByID  %s", err.Error())
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// GetClientSetting returns the settings of a client. Clients without a stored setting get the
+// defaults, which means no tax and no service charge.
+func (r *clientRepository) GetClientSetting(ctx context.Context, clientID uint) (*entity.ClientSetting, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var setting entity.ClientSetting
+
+	err := r.db.Where("client_id = ?", clientID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entity.ClientSetting{ClientID: clientID}, nil
+	}
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetClientSetting  %s", err.Error())
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+func (r *clientRepository) SaveClientSetting(ctx context.Context, setting *entity.ClientSetting) (*entity.ClientSetting, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	// Save inserts the setting when it has no ID yet and updates every column otherwise
+	if err := r.db.Save(setting).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SaveClientSetting  %s", err.Error())
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// GetOpeningHours returns the opening hours of a client ordered by weekday and open time.
+func (r *clientRepository) GetOpeningHours(ctx context.Context, clientID uint) ([]*entity.ClientOpeningHour, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	hours := []*entity.ClientOpeningHour{}
+
+	if err := r.db.Where("client_id = ?", clientID).
+		Order("weekday ASC, open_time ASC").
+		Find(&hours).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOpeningHours  %s", err.Error())
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// SaveOpeningHours replaces the opening hours of a client.
+func (r *clientRepository) SaveOpeningHours(ctx context.Context, clientID uint, hours []*entity.ClientOpeningHour) ([]*entity.ClientOpeningHour, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	if err := tx.Where("client_id = ?", clientID).Delete(&entity.ClientOpeningHour{}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SaveOpeningHours  %s", err.Error())
+		return nil, err
+	}
+
+	for _, hour := range hours {
+		hour.ClientID = clientID
+		if err := tx.Create(hour).Error; err != nil {
+			tx.Rollback()
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SaveOpeningHours  %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// GetHolidays returns the holidays of a client on or after the date of from, earliest first.
+func (r *clientRepository) GetHolidays(ctx context.Context, clientID uint, from time.Time) ([]*entity.ClientHoliday, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	holidays := []*entity.ClientHoliday{}
+
+	if err := r.db.Where("client_id = ? AND date >= ?", clientID, from.Format("2006-01-02")).
+		Order("date ASC, open_time ASC").
+		Find(&holidays).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetHolidays  %s", err.Error())
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+func (r *clientRepository) AddHoliday(ctx context.Context, holiday *entity.ClientHoliday) (*entity.ClientHoliday, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Create(holiday).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddHoliday  %s", err.Error())
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// DeleteHoliday removes a holiday of a client.
+func (r *clientRepository) DeleteHoliday(ctx context.Context, clientID uint, holidayID uint) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	result := r.db.Where("id = ? AND client_id = ?", holidayID, clientID).Delete(&entity.ClientHoliday{})
+	if result.Error != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeleteHoliday  %s", result.Error.Error())
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomerRepository interface {
+	GetCustomerByID(ctx context.Context, clientID uint, customerID uint) (*entity.Customer, error)
+	GetCustomerByPhone(ctx context.Context, clientID uint, phoneNumber string) (*entity.Customer, error)
+	GetCustomerStats(ctx context.Context, customerID uint) (*model.CustomerStats, error)
+	GetFavouriteProducts(ctx context.Context, customerID uint, limit int) ([]model.FavouriteProduct, error)
+	GetCustomerOrders(ctx context.Context, customerID uint, limit int, offset int) ([]*entity.Order, error)
+	NormalizePhoneNumbers(ctx context.Context) (int, error)
+}
+
+type customerRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomerRepository(db *gorm.DB) CustomerRepository {
+	return &customerRepository{
+		db: db,
+	}
+}
+
+func (r *customerRepository) GetCustomerByID(ctx context.Context, clientID uint, customerID uint) (*entity.Customer, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var customer entity.Customer
+
+	if err := r.db.Where("id = ? AND client_id = ?", customerID, clientID).
+		First(&customer).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetCustomerByID  %s", err.Error())
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+func (r *customerRepository) GetCustomerByPhone(ctx context.Context, clientID uint, phoneNumber string) (*entity.Customer, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var customer entity.Customer
+
+	if err := r.db.Where("client_id = ? AND phone_number = ?", clientID, phoneNumber).
+		First(&customer).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetCustomerByPhone  %s", err.Error())
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// GetCustomerStats returns the number of orders and the lifetime spend of a customer, cancelled
+// orders are not counted.
+func (r *customerRepository) GetCustomerStats(ctx context.Context, customerID uint) (*model.CustomerStats, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var stats model.CustomerStats
+
+	if err := r.db.Table("`order`").
+		Select("COUNT(*) AS order_count, IFNULL(SUM(total), 0) AS lifetime_spend, MIN(created_at) AS first_order_at, MAX(created_at) AS last_order_at").
+		Where("customer_id = ? AND status <> ?", customerID, model.OrderStatusCancelled).
+		Scan(&stats).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetCustomerStats  %s", err.Error())
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetFavouriteProducts returns the products a customer ordered the most, cancelled orders are
+// not counted.
+func (r *customerRepository) GetFavouriteProducts(ctx context.Context, customerID uint, limit int) ([]model.FavouriteProduct, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var products []model.FavouriteProduct
+
+	if err := r.db.Table("order_detail").
+		Select("order_detail.product_id, SUM(order_detail.quantity) AS quantity, COUNT(DISTINCT order_detail.order_id) AS order_count").
+		Joins("JOIN `order` ON `order`.id = order_detail.order_id").
+		Where("`order`.customer_id = ? AND `order`.status <> ?", customerID, model.OrderStatusCancelled).
+		Group("order_detail.product_id").
+		Order("quantity DESC, order_count DESC, order_detail.product_id ASC").
+		Limit(limit).
+		Scan(&products).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetFavouriteProducts  %s", err.Error())
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// GetCustomerOrders returns the orders of a customer with their lines, latest first.
+func (r *customerRepository) GetCustomerOrders(ctx context.Context, customerID uint, limit int, offset int) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Preload("OrderDetails").
+		Where("customer_id = ?", customerID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetCustomerOrders  %s", err.Error())
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.StatusText = model.OrderStatusText(order.Status)
+	}
+
+	return orders, nil
+}
+
+// linkCustomer links an order to the customer of its phone number within the transaction of the
+// order change, creating the customer on their first order. The customer keeps the latest name.
+// Orders without a phone number have no customer.
+func linkCustomer(tx *gorm.DB, order *entity.Order) error {
+	phoneNumber := model.NormalizePhoneNumber(order.PhoneNumber)
+	if phoneNumber == "" {
+		order.CustomerID = nil
+		return nil
+	}
+
+	customer := entity.Customer{
+		ClientID:    order.ClientID,
+		PhoneNumber: phoneNumber,
+		Name:        order.CustomerName,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&customer).Error; err != nil {
+		return err
+	}
+
+	// The ID is not returned when the customer already exists
+	if err := tx.Select("id").
+		Where("client_id = ? AND phone_number = ?", order.ClientID, phoneNumber).
+		First(&customer).
+		Error; err != nil {
+		return err
+	}
+
+	order.CustomerID = &customer.ID
+	return nil
+}
+
+// NormalizePhoneNumbers rewrites the stored phone numbers of orders, promotion redemptions and
+// customers to E.164 with the country of their client, the rules ParsePhoneNumber applies to new
+// orders. Customers whose numbers turn out to be the same are merged into one, their orders and
+// loyalty points move to the customer kept. Numbers that can not be parsed are left as they are.
+// It returns the number of rows changed and is run once by the -backfill.phone flag.
+func (r *customerRepository) NormalizePhoneNumbers(ctx context.Context) (int, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	var clientIDs []uint
+	if err := r.db.Model(&entity.Client{}).Pluck("id", &clientIDs).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error NormalizePhoneNumbers  %s", err.Error())
+		return 0, err
+	}
+
+	changed := 0
+	for _, clientID := range clientIDs {
+		var setting entity.ClientSetting
+		if err := r.db.Where("client_id = ?", clientID).Limit(1).Find(&setting).Error; err != nil {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error NormalizePhoneNumbers  %s", err.Error())
+			return changed, err
+		}
+
+		country := setting.CountryCode
+		if country == "" {
+			country = model.DefaultCountry
+		}
+
+		count, err := normalizeClientPhoneNumbers(r.db, clientID, country)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error NormalizePhoneNumbers  %s", err.Error())
+			return changed, err
+		}
+		changed += count
+	}
+
+	return changed, nil
+}
+
+// normalizeClientPhoneNumbers normalises the phone numbers of one client in a transaction.
+func normalizeClientPhoneNumbers(db *gorm.DB, clientID uint, country string) (int, error) {
+	changed := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"`order`", "promotion_redemption"} {
+			var rows []struct {
+				ID          uint
+				PhoneNumber string
+			}
+			if err := tx.Table(table).Select("id, phone_number").
+				Where("client_id = ? AND phone_number <> ''", clientID).
+				Scan(&rows).
+				Error; err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				number, err := model.ParsePhoneNumber(row.PhoneNumber, country)
+				if err != nil || number == row.PhoneNumber {
+					continue
+				}
+				if err := tx.Table(table).Where("id = ?", row.ID).Update("phone_number", number).Error; err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+
+		var customers []entity.Customer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("client_id = ?", clientID).
+			Order("id").
+			Find(&customers).
+			Error; err != nil {
+			return err
+		}
+
+		// The customer already stored with the normalised number is kept, otherwise the oldest one
+		kept := make(map[string]entity.Customer)
+		for _, customer := range customers {
+			number, err := model.ParsePhoneNumber(customer.PhoneNumber, country)
+			if err != nil {
+				continue
+			}
+			if current, ok := kept[number]; !ok || (customer.PhoneNumber == number && current.PhoneNumber != number) {
+				kept[number] = customer
+			}
+		}
+
+		for _, customer := range customers {
+			number, err := model.ParsePhoneNumber(customer.PhoneNumber, country)
+			if err != nil || kept[number].ID == customer.ID {
+				continue
+			}
+
+			target := kept[number].ID
+			if err := tx.Model(&entity.Order{}).Where("customer_id = ?", customer.ID).Update("customer_id", target).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.LoyaltyLedger{}).Where("customer_id = ?", customer.ID).Update("customer_id", target).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&entity.Customer{}, customer.ID).Error; err != nil {
+				return err
+			}
+			changed++
+		}
+
+		for number, customer := range kept {
+			if customer.PhoneNumber == number {
+				continue
+			}
+			if err := tx.Model(&entity.Customer{}).Where("id = ?", customer.ID).Update("phone_number", number).Error; err != nil {
+				return err
+			}
+			changed++
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
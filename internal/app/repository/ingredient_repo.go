@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IngredientRepository interface {
+	GetIngredients(ctx context.Context, clientID uint) ([]*entity.Ingredient, error)
+	AddIngredient(ctx context.Context, ingredient *entity.Ingredient) (*entity.Ingredient, error)
+	GetRecipe(ctx context.Context, clientID uint, productID uint) ([]*entity.RecipeItem, error)
+	SaveRecipe(ctx context.Context, clientID uint, productID uint, items []*entity.RecipeItem) ([]*entity.RecipeItem, error)
+	GetConsumption(ctx context.Context, clientID uint, from time.Time, to time.Time, loc *time.Location) ([]model.IngredientUsage, error)
+}
+
+type ingredientRepository struct {
+	db *gorm.DB
+}
+
+func NewIngredientRepository(db *gorm.DB) IngredientRepository {
+	return &ingredientRepository{
+		db: db,
+	}
+}
+
+// GetIngredients returns the ingredients of a client ordered by name.
+func (r *ingredientRepository) GetIngredients(ctx context.Context, clientID uint) ([]*entity.Ingredient, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	ingredients := []*entity.Ingredient{}
+
+	if err := r.db.Where("client_id = ?", clientID).
+		Order("name ASC, id ASC").
+		Find(&ingredients).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetIngredients  %s", err.Error())
+		return nil, err
+	}
+
+	return ingredients, nil
+}
+
+func (r *ingredientRepository) AddIngredient(ctx context.Context, ingredient *entity.Ingredient) (*entity.Ingredient, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Create(ingredient).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddIngredient  %s", err.Error())
+		return nil, err
+	}
+
+	return ingredient, nil
+}
+
+// GetRecipe returns the ingredients used for one unit of a product.
+func (r *ingredientRepository) GetRecipe(ctx context.Context, clientID uint, productID uint) ([]*entity.RecipeItem, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	items := []*entity.RecipeItem{}
+
+	if err := r.db.Where("client_id = ? AND product_id = ?", clientID, productID).
+		Order("ingredient_id ASC").
+		Find(&items).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetRecipe  %s", err.Error())
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SaveRecipe replaces the recipe of a product. Orders completed before keep the consumption of
+// the old recipe.
+func (r *ingredientRepository) SaveRecipe(ctx context.Context, clientID uint, productID uint, items []*entity.RecipeItem) ([]*entity.RecipeItem, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	if err := tx.Where("client_id = ? AND product_id = ?", clientID, productID).Delete(&entity.RecipeItem{}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SaveRecipe  %s", err.Error())
+		return nil, err
+	}
+
+	for _, item := range items {
+		item.ClientID = clientID
+		item.ProductID = productID
+		if err := tx.Create(item).Error; err != nil {
+			tx.Rollback()
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SaveRecipe  %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetConsumption returns the quantity of every ingredient used per day in loc by the orders
+// completed from up to but not including to, ordered by day and ingredient name. The quantities are
+// summed per quarter of an hour in the database and added to the day the quarter falls on in loc.
+func (r *ingredientRepository) GetConsumption(ctx context.Context, clientID uint, from time.Time, to time.Time, loc *time.Location) ([]model.IngredientUsage, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	var quarters []struct {
+		Start time.Time
+		model.IngredientUsage
+	}
+	if err := r.db.Table("ingredient_consumption").
+		Select("TIMESTAMP(DATE(ingredient_consumption.consumed_at), MAKETIME(HOUR(ingredient_consumption.consumed_at), FLOOR(MINUTE(ingredient_consumption.consumed_at) / 15) * 15, 0)) AS start, ingredient_consumption.ingredient_id, ingredient.name, ingredient.unit, SUM(ingredient_consumption.quantity) AS quantity").
+		Joins("JOIN ingredient ON ingredient.id = ingredient_consumption.ingredient_id").
+		Where("ingredient_consumption.client_id = ? AND ingredient_consumption.consumed_at >= ? AND ingredient_consumption.consumed_at < ?", clientID, from, to).
+		Group("start, ingredient_consumption.ingredient_id, ingredient.name, ingredient.unit").
+		Scan(&quarters).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetConsumption  %s", err.Error())
+		return nil, err
+	}
+
+	type dayIngredient struct {
+		date         string
+		ingredientID uint
+	}
+	var usage []model.IngredientUsage
+	index := make(map[dayIngredient]int)
+	for _, quarter := range quarters {
+		key := dayIngredient{quarter.Start.In(loc).Format("2006-01-02"), quarter.IngredientID}
+		i, ok := index[key]
+		if !ok {
+			i = len(usage)
+			index[key] = i
+			day := quarter.IngredientUsage
+			day.Date = key.date
+			day.Quantity = 0
+			usage = append(usage, day)
+		}
+		usage[i].Quantity += quarter.Quantity
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Date != usage[j].Date {
+			return usage[i].Date < usage[j].Date
+		}
+		if usage[i].Name != usage[j].Name {
+			return usage[i].Name < usage[j].Name
+		}
+		return usage[i].IngredientID < usage[j].IngredientID
+	})
+
+	return usage, nil
+}
+
+// consumeIngredients records the ingredients used by a completed order according to the current
+// recipes of its products.
+func consumeIngredients(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	var details []entity.OrderDetail
+	if err := tx.Where("order_id = ?", order.ID).Find(&details).Error; err != nil {
+		return err
+	}
+
+	quantities := model.StockQuantities(details)
+	if len(quantities) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+
+	var recipes []*entity.RecipeItem
+	if err := tx.Where("client_id = ? AND product_id IN ?", order.ClientID, productIDs).Find(&recipes).Error; err != nil {
+		return err
+	}
+
+	used := model.IngredientQuantities(details, recipes)
+	ingredientIDs := make([]uint, 0, len(used))
+	for ingredientID := range used {
+		ingredientIDs = append(ingredientIDs, ingredientID)
+	}
+	sort.Slice(ingredientIDs, func(i, j int) bool { return ingredientIDs[i] < ingredientIDs[j] })
+
+	for _, ingredientID := range ingredientIDs {
+		consumption := &entity.IngredientConsumption{
+			ClientID:     order.ClientID,
+			OrderID:      order.ID,
+			IngredientID: ingredientID,
+			Quantity:     used[ingredientID],
+			ConsumedAt:   now,
+		}
+		if err := tx.Create(consumption).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
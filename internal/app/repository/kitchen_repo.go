@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type KitchenRepository interface {
+	AddStation(ctx context.Context, station *entity.KitchenStation) (*entity.KitchenStation, error)
+	GetStations(ctx context.Context, clientID uint) ([]*entity.KitchenStation, error)
+	GetStationTickets(ctx context.Context, clientID uint, stationID uint) ([]*entity.KitchenTicket, error)
+	GetTicketByID(ctx context.Context, clientID uint, ticketID uint) (*entity.KitchenTicket, error)
+	BumpTicket(ctx context.Context, ticketID uint) (*entity.KitchenTicket, error)
+}
+
+type kitchenRepository struct {
+	db *gorm.DB
+}
+
+func NewKitchenRepository(db *gorm.DB) KitchenRepository {
+	return &kitchenRepository{
+		db: db,
+	}
+}
+
+func (r *kitchenRepository) AddStation(ctx context.Context, station *entity.KitchenStation) (*entity.KitchenStation, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Create(station).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddStation  %s", err.Error())
+		return nil, err
+	}
+
+	return station, nil
+}
+
+func (r *kitchenRepository) GetStations(ctx context.Context, clientID uint) ([]*entity.KitchenStation, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var stations []*entity.KitchenStation
+
+	if err := r.db.Preload("Categories").
+		Where("client_id = ? AND is_active = ?", clientID, true).
+		Order("id ASC").
+		Find(&stations).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetStations  %s", err.Error())
+		return nil, err
+	}
+
+	return stations, nil
+}
+
+// GetStationTickets returns the open tickets of a station released to the kitchen, oldest first,
+// with their order and the lines routed to the station. Station zero holds the lines without a
+// station. Tickets of unpaid, completed and cancelled orders are not returned.
+func (r *kitchenRepository) GetStationTickets(ctx context.Context, clientID uint, stationID uint) ([]*entity.KitchenTicket, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var tickets []*entity.KitchenTicket
+
+	if err := r.db.Preload("Order").
+		Joins("JOIN `order` ON kitchen_ticket.order_id = `order`.id").
+		Where("`order`.client_id = ? AND kitchen_ticket.station_id = ? AND kitchen_ticket.status <> ?", clientID, stationID, model.PrepStatusReady).
+		Where("`order`.released_at IS NOT NULL").
+		Where("`order`.status >= ? AND `order`.status < ?", model.OrderStatusPaid, model.OrderStatusSuccess).
+		Order("kitchen_ticket.created_at ASC").
+		Find(&tickets).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetStationTickets  %s", err.Error())
+		return nil, err
+	}
+
+	if len(tickets) == 0 {
+		return tickets, nil
+	}
+
+	orderIDs := make([]uint, 0, len(tickets))
+	for _, ticket := range tickets {
+		orderIDs = append(orderIDs, ticket.OrderID)
+	}
+
+	var details []entity.OrderDetail
+	if err := r.db.Where("order_id IN ? AND station_id = ?", orderIDs, stationID).
+		Order("id ASC").
+		Find(&details).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetStationTickets  %s", err.Error())
+		return nil, err
+	}
+
+	for _, ticket := range tickets {
+		for _, detail := range details {
+			if detail.OrderID == ticket.OrderID {
+				ticket.OrderDetails = append(ticket.OrderDetails, detail)
+			}
+		}
+		if ticket.Order != nil {
+			ticket.Order.StatusText = model.OrderStatusText(ticket.Order.Status)
+		}
+	}
+
+	return tickets, nil
+}
+
+func (r *kitchenRepository) GetTicketByID(ctx context.Context, clientID uint, ticketID uint) (*entity.KitchenTicket, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var ticket entity.KitchenTicket
+
+	if err := r.db.Preload("Order").
+		Joins("JOIN `order` ON kitchen_ticket.order_id = `order`.id").
+		Where("kitchen_ticket.id = ? AND `order`.client_id = ?", ticketID, clientID).
+		First(&ticket).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetTicketByID  %s", err.Error())
+		return nil, err
+	}
+
+	return &ticket, nil
+}
+
+// BumpTicket marks every line of a ticket as ready and advances the order in the same
+// transaction, the same way marking the lines one by one would. Only tickets of paid orders that
+// are not finished can be bumped.
+func (r *kitchenRepository) BumpTicket(ctx context.Context, ticketID uint) (*entity.KitchenTicket, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+	now := time.Now()
+
+	var ticket entity.KitchenTicket
+	if err := tx.First(&ticket, ticketID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error BumpTicket  %s", err.Error())
+		return nil, err
+	}
+
+	var order entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, ticket.OrderID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error BumpTicket  %s", err.Error())
+		return nil, err
+	}
+
+	if err := checkPreparation(&order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.OrderDetail{}).
+		Where("order_id = ? AND station_id = ? AND prep_status <> ?", ticket.OrderID, ticket.StationID, model.PrepStatusReady).
+		Updates(map[string]interface{}{
+			"prep_status": model.PrepStatusReady,
+			"started_at":  gorm.Expr("COALESCE(started_at, ?)", now),
+			"ready_at":    now,
+		}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error BumpTicket  %s", err.Error())
+		return nil, err
+	}
+
+	if err := syncOrderPreparation(tx, &order, now); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error BumpTicket  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	for _, t := range order.KitchenTickets {
+		if t.ID == ticket.ID {
+			ticket = t
+		}
+	}
+	for _, detail := range order.OrderDetails {
+		if detail.StationID == ticket.StationID {
+			ticket.OrderDetails = append(ticket.OrderDetails, detail)
+		}
+	}
+	order.StatusText = model.OrderStatusText(order.Status)
+	order.OrderDetails = nil
+	order.KitchenTickets = nil
+	ticket.Order = &order
+
+	return &ticket, nil
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientPoints is returned when a customer redeems more points than their balance.
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+type LoyaltyRepository interface {
+	GetBalance(ctx context.Context, customerID uint, now time.Time) (int, error)
+	GetLedger(ctx context.Context, customerID uint, limit int) ([]*entity.LoyaltyLedger, error)
+}
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyRepository(db *gorm.DB) LoyaltyRepository {
+	return &loyaltyRepository{
+		db: db,
+	}
+}
+
+// GetBalance returns the points of a customer that can be redeemed at now.
+func (r *loyaltyRepository) GetBalance(ctx context.Context, customerID uint, now time.Time) (int, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var balance int
+
+	if err := unexpiredCredits(r.db.Model(&entity.LoyaltyLedger{}), customerID, now).
+		Select("IFNULL(SUM(remaining), 0)").
+		Scan(&balance).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetBalance  %s", err.Error())
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// GetLedger returns the latest entries of the ledger of a customer.
+func (r *loyaltyRepository) GetLedger(ctx context.Context, customerID uint, limit int) ([]*entity.LoyaltyLedger, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var ledger []*entity.LoyaltyLedger
+
+	if err := r.db.Where("customer_id = ?", customerID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&ledger).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetLedger  %s", err.Error())
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+func unexpiredCredits(db *gorm.DB, customerID uint, now time.Time) *gorm.DB {
+	return db.Where("customer_id = ? AND remaining > 0 AND (expires_at IS NULL OR expires_at > ?)", customerID, now)
+}
+
+// redeemLoyaltyPoints debits the points redeemed by an order within the transaction of the order.
+// The credits expiring first are used first. ErrInsufficientPoints is returned when the unexpired
+// credits of the customer do not cover the points.
+func redeemLoyaltyPoints(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if order.PointsRedeemed <= 0 {
+		return nil
+	}
+	if order.CustomerID == nil {
+		return ErrInsufficientPoints
+	}
+
+	var credits []*entity.LoyaltyLedger
+	if err := unexpiredCredits(tx, *order.CustomerID, now).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Order("expires_at IS NULL, expires_at ASC, id ASC").
+		Find(&credits).
+		Error; err != nil {
+		return err
+	}
+
+	points := order.PointsRedeemed
+	for _, credit := range credits {
+		if points == 0 {
+			break
+		}
+
+		used := credit.Remaining
+		if used > points {
+			used = points
+		}
+		if err := tx.Model(&entity.LoyaltyLedger{}).Where("id = ?", credit.ID).
+			Update("remaining", gorm.Expr("remaining - ?", used)).Error; err != nil {
+			return err
+		}
+		points -= used
+	}
+	if points > 0 {
+		return ErrInsufficientPoints
+	}
+
+	return tx.Create(&entity.LoyaltyLedger{
+		ClientID:   order.ClientID,
+		CustomerID: *order.CustomerID,
+		OrderID:    order.ID,
+		Type:       model.LoyaltyEntryRedeem,
+		Points:     -order.PointsRedeemed,
+	}).Error
+}
+
+// accrueLoyaltyPoints credits the customer of an order with the points of its paid amount when the
+// order is paid, once per order. The spend ratio and the expiry are read from the client setting.
+func accrueLoyaltyPoints(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if order.CustomerID == nil || order.PointsEarned > 0 {
+		return nil
+	}
+
+	setting, err := loyaltySetting(tx, order.ClientID)
+	if err != nil || setting == nil {
+		return err
+	}
+
+	points := model.LoyaltyPoints(setting, order.Total-order.PointsAmount)
+	if points == 0 {
+		return nil
+	}
+
+	if err := creditEarnedPoints(tx, order, setting, points, now); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Update("points_earned", points).Error; err != nil {
+		return err
+	}
+	order.PointsEarned = points
+
+	return nil
+}
+
+// adjustLoyaltyPoints corrects the points earned by a paid order that is edited, previous is the
+// stored order before the edit. A higher total credits the difference, a lower total removes it
+// from the points of the order that have not been redeemed yet. When the edit changes the
+// customer, the points move from the previous customer to the new one. The points earned are set
+// on order and stored with it.
+func adjustLoyaltyPoints(tx *gorm.DB, previous *entity.Order, order *entity.Order, now time.Time) error {
+	order.PointsEarned = previous.PointsEarned
+	if previous.PaidAt == nil {
+		return nil
+	}
+
+	setting, err := loyaltySetting(tx, order.ClientID)
+	if err != nil {
+		return err
+	}
+
+	earned := previous.PointsEarned
+	if !sameCustomer(previous.CustomerID, order.CustomerID) {
+		if err := removeEarnedPoints(tx, previous, earned); err != nil {
+			return err
+		}
+		earned = 0
+	}
+
+	points := 0
+	if order.CustomerID != nil && setting != nil {
+		points = model.LoyaltyPoints(setting, order.Total-order.PointsAmount)
+	}
+
+	switch {
+	case points > earned:
+		if err := creditEarnedPoints(tx, order, setting, points-earned, now); err != nil {
+			return err
+		}
+	case points < earned:
+		if err := removeEarnedPoints(tx, order, earned-points); err != nil {
+			return err
+		}
+	}
+	order.PointsEarned = points
+
+	return nil
+}
+
+// loyaltySetting returns the client setting for the loyalty points of a client, nil when the
+// client has no setting.
+func loyaltySetting(tx *gorm.DB, clientID uint) (*entity.ClientSetting, error) {
+	var setting entity.ClientSetting
+	err := tx.Where("client_id = ?", clientID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+// creditEarnedPoints adds an earn entry of the order to the ledger of its customer.
+func creditEarnedPoints(tx *gorm.DB, order *entity.Order, setting *entity.ClientSetting, points int, now time.Time) error {
+	return tx.Create(&entity.LoyaltyLedger{
+		ClientID:   order.ClientID,
+		CustomerID: *order.CustomerID,
+		OrderID:    order.ID,
+		Type:       model.LoyaltyEntryEarn,
+		Points:     points,
+		Remaining:  points,
+		ExpiresAt:  model.LoyaltyExpiry(setting, now),
+	}).Error
+}
+
+// removeEarnedPoints removes up to points from the earn entries of an order for its customer that
+// have not been redeemed yet and records the removal as a reverse entry.
+func removeEarnedPoints(tx *gorm.DB, order *entity.Order, points int) error {
+	if order.CustomerID == nil || points <= 0 {
+		return nil
+	}
+
+	var entries []*entity.LoyaltyLedger
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND customer_id = ? AND type = ? AND remaining > 0", order.ID, *order.CustomerID, model.LoyaltyEntryEarn).
+		Order("id DESC").
+		Find(&entries).
+		Error; err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if removed == points {
+			break
+		}
+
+		used := entry.Remaining
+		if used > points-removed {
+			used = points - removed
+		}
+		if err := tx.Model(&entity.LoyaltyLedger{}).Where("id = ?", entry.ID).
+			Update("remaining", gorm.Expr("remaining - ?", used)).Error; err != nil {
+			return err
+		}
+		removed += used
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	return tx.Create(&entity.LoyaltyLedger{
+		ClientID:   order.ClientID,
+		CustomerID: *order.CustomerID,
+		OrderID:    order.ID,
+		Type:       model.LoyaltyEntryReverse,
+		Points:     -removed,
+	}).Error
+}
+
+// sameCustomer reports whether two orders belong to the same customer.
+func sameCustomer(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// reverseLoyaltyPoints undoes the loyalty entries of a cancelled order. The points earned by the
+// order are removed as far as they have not been redeemed yet, and the points redeemed by the
+// order are credited back with a new expiry.
+func reverseLoyaltyPoints(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	var entries []*entity.LoyaltyLedger
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", order.ID).
+		Find(&entries).
+		Error; err != nil {
+		return err
+	}
+
+	var setting entity.ClientSetting
+	if err := tx.Where("client_id = ?", order.ClientID).First(&setting).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch entry.Type {
+		case model.LoyaltyEntryEarn:
+			if entry.Remaining == 0 {
+				continue
+			}
+			if err := tx.Model(&entity.LoyaltyLedger{}).Where("id = ?", entry.ID).Update("remaining", 0).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&entity.LoyaltyLedger{
+				ClientID:   entry.ClientID,
+				CustomerID: entry.CustomerID,
+				OrderID:    order.ID,
+				Type:       model.LoyaltyEntryReverse,
+				Points:     -entry.Remaining,
+			}).Error; err != nil {
+				return err
+			}
+		case model.LoyaltyEntryRedeem:
+			if err := tx.Create(&entity.LoyaltyLedger{
+				ClientID:   entry.ClientID,
+				CustomerID: entry.CustomerID,
+				OrderID:    order.ID,
+				Type:       model.LoyaltyEntryRefund,
+				Points:     -entry.Points,
+				Remaining:  -entry.Points,
+				ExpiresAt:  model.LoyaltyExpiry(&setting, now),
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
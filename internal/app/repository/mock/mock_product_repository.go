@@ -41,3 +41,16 @@ func (m *MockProductRepository) GetProductByID(ctx context.Context, productID ui
 
 	return nil, errors.New("GetProductByIDFunc not implemented in the mock")
 }
+
+// GetProductsByIDs is the mock implementation for the GetProductsByIDs method, it looks up every
+// product with GetProductByID.
+func (m *MockProductRepository) GetProductsByIDs(ctx context.Context, productIDs []uint, token string) (map[uint]*entity.Product, error) {
+	products := make(map[uint]*entity.Product, len(productIDs))
+	for _, productID := range productIDs {
+		if product, err := m.GetProductByID(ctx, productID, token); err == nil {
+			products[productID] = product
+		}
+	}
+
+	return products, nil
+}
@@ -2,23 +2,48 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maqhaa/library/logging"
 	"maqhaa/library/middleware"
 	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
 	"maqhaa/order_service/internal/app/model"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
 	AddOrder(ctx context.Context, order *entity.Order) (*entity.Order, error)
 	GetOrderByID(ctx context.Context, orderID uint, clientToken string) (*entity.Order, error)
 	EditOrder(ctx context.Context, order *entity.Order) (*entity.Order, error)
+	GetOrdersByStatus(ctx context.Context, clientToken string, statuses []int) ([]*entity.Order, error)
+	GetOrdersByPhone(ctx context.Context, clientID uint, statuses []int, phoneNumber string) ([]*entity.Order, error)
+	GetOrdersUpdatedSince(ctx context.Context, clientToken string, statuses []int, since time.Time) ([]*entity.Order, error)
+	GetOrderDetailByID(ctx context.Context, detailID uint, clientToken string) (*entity.OrderDetail, error)
+	UpdatePrepStatus(ctx context.Context, detailID uint, prepStatus int) (*entity.Order, error)
+	UpdateOrderStatus(ctx context.Context, order *entity.Order, status int) (*entity.Order, error)
+	GetScheduledOrders(ctx context.Context, clientToken string) ([]*entity.Order, error)
+	GetOrdersToRelease(ctx context.Context, now time.Time, limit int) ([]*entity.Order, error)
+	ReleaseOrder(ctx context.Context, orderID uint, now time.Time) (*entity.Order, error)
 }
 
+// ErrOrderNotScheduled is returned when releasing an order that was already released or cancelled.
+var ErrOrderNotScheduled = errors.New("order is not waiting for release")
+
+// ErrOrderNotPaid is returned when the kitchen prepares an order that has not been paid.
+var ErrOrderNotPaid = errors.New("order is not paid")
+
+// ErrInvalidOrderStatus is returned when the locked order row no longer allows the change, e.g.
+// because a concurrent request finished or cancelled the order.
+var ErrInvalidOrderStatus = errors.New("invalid order status")
+
+// ErrOrderLocked is returned when editing or cancelling an order of a closed business day.
+var ErrOrderLocked = errors.New("order is locked")
+
 type orderRepository struct {
 	db *gorm.DB
 }
@@ -33,28 +58,32 @@ func (r *orderRepository) AddOrder(ctx context.Context, order *entity.Order) (*e
 	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
 	tx := r.db.Begin()
 
-	// Get the current date
-	currentDate := time.Now().Format("2006-01-02")
+	// Scheduled orders get their queue number when they are released to the kitchen
+	if order.ReleasedAt != nil {
+		if err := assignQueueNumber(tx, order); err != nil {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
-	// Retrieve the latest queue number for the current date and client
-	var latestQueueNumber int
-	if err := tx.Table("order").
-		Where("DATE(created_at) = ? AND client_id = ?", currentDate, order.ClientID).
-		Select("IFNULL(MAX(queue_number), 0)").
-		Set("gorm:query_option", "FOR UPDATE").
-		Scan(&latestQueueNumber).
-		Error; err != nil {
-		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+	if err := linkCustomer(tx, order); err != nil {
 		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
 		return nil, err
 	}
 
-	// Increment the queue number
-	latestQueueNumber++
-	order.QueueNumber = latestQueueNumber
+	if err := reserveStock(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+		return nil, err
+	}
 
-	// Generate the order number with leading zeros
-	order.OrderNumber = fmt.Sprintf("ORD-%04d", latestQueueNumber)
+	if err := checkPromotionUsage(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+		return nil, err
+	}
 
 	// Create the order
 	if err := tx.Create(order).Error; err != nil {
@@ -63,6 +92,18 @@ func (r *orderRepository) AddOrder(ctx context.Context, order *entity.Order) (*e
 		return nil, err
 	}
 
+	if err := redeemLoyaltyPoints(tx, order, time.Now()); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if err := addOutboxEvent(tx, event.OrderCreated, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddOrder  %s", err.Error())
@@ -86,26 +127,87 @@ func (r *orderRepository) GetOrderByID(ctx context.Context, orderID uint, client
 	}
 
 	// Set the StatusText based on the Status value
-	switch order.Status {
-	case 1:
-		order.StatusText = model.OrderStatusIncomingMessage
-	case 2:
-		order.StatusText = model.OrderStatusPaidMessage
-	case 3:
-		order.StatusText = model.OrderStatusProcessingMessage
-	case 4:
-		order.StatusText = model.OrderStatusSuccessMessage
-	default:
-		order.StatusText = "Unknown"
-	}
+	order.StatusText = model.OrderStatusText(order.Status)
 
 	return &order, nil
 }
 
+// EditOrder replaces the lines and amounts of an order. The order row is locked and checked again
+// so an order that was finished, cancelled or locked since it was read is not changed. Once the
+// kitchen started a line the order can no longer be edited, the status of the order is kept.
 func (r *orderRepository) EditOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
 	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
 	tx := r.db.Begin()
 
+	var current entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, order.ID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error EditOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if current.Status >= model.OrderStatusSuccess {
+		tx.Rollback()
+		return nil, ErrInvalidOrderStatus
+	}
+	if current.LockedAt != nil {
+		tx.Rollback()
+		return nil, ErrOrderLocked
+	}
+
+	var started int64
+	if err := tx.Model(&entity.OrderDetail{}).
+		Where("order_id = ? AND prep_status <> ?", order.ID, model.PrepStatusPending).
+		Count(&started).
+		Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error EditOrder  %s", err.Error())
+		return nil, err
+	}
+	if started > 0 {
+		tx.Rollback()
+		return nil, ErrInvalidOrderStatus
+	}
+
+	// The status and the payment are changed by status changes, an edit keeps the stored ones
+	order.Status = current.Status
+	order.PaymentMethod = current.PaymentMethod
+	order.PaidAt = current.PaidAt
+	order.ShiftID = current.ShiftID
+
+	if err := linkCustomer(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error linking customer %s", err.Error())
+		return nil, err
+	}
+
+	// The change in the cash due of an order paid in cash is taken or paid out by the open shift
+	if err := adjustPayment(tx, &current, order, time.Now()); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error adjusting payment %s", err.Error())
+		return nil, err
+	}
+
+	// The points earned by a paid order follow its new total
+	if err := adjustLoyaltyPoints(tx, &current, order, time.Now()); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error adjusting loyalty points %s", err.Error())
+		return nil, err
+	}
+
+	// The stock of the old lines is returned before the new lines are reserved
+	if err := releaseStock(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error releasing stock %s", err.Error())
+		return nil, err
+	}
+
+	if err := reserveStock(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error reserving stock %s", err.Error())
+		return nil, err
+	}
+
 	// Update order
 	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(order).Error; err != nil {
 		tx.Rollback()
@@ -113,6 +215,27 @@ func (r *orderRepository) EditOrder(ctx context.Context, order *entity.Order) (*
 		return nil, err
 	}
 
+	// Updates skips zero values, so fields that can be cleared by an edit are written explicitly
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+		"customer_id":         order.CustomerID,
+		"scheduled_at":        order.ScheduledAt,
+		"stock_reserved":      order.StockReserved,
+		"promo_code":          order.PromoCode,
+		"subtotal":            order.Subtotal,
+		"promo_discount":      order.PromoDiscount,
+		"service_charge_rate": order.ServiceChargeRate,
+		"service_charge":      order.ServiceCharge,
+		"tax_rate":            order.TaxRate,
+		"tax_inclusive":       order.TaxInclusive,
+		"tax":                 order.Tax,
+		"rounding_adjustment": order.RoundingAdjustment,
+		"points_earned":       order.PointsEarned,
+	}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error updating order %s", err.Error())
+		return nil, err
+	}
+
 	// Remove all old order details
 	if err := tx.Where("order_id = ?", order.ID).Delete(&entity.OrderDetail{}).Error; err != nil {
 		tx.Rollback()
@@ -130,6 +253,50 @@ func (r *orderRepository) EditOrder(ctx context.Context, order *entity.Order) (*
 		}
 	}
 
+	// Replace the promotion redemptions of the order
+	if err := checkPromotionUsage(tx, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error checking promotion usage %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Where("order_id = ?", order.ID).Delete(&entity.PromotionRedemption{}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error deleting old promotion redemptions %s", err.Error())
+		return nil, err
+	}
+
+	for _, redemption := range order.PromotionRedemptions {
+		redemption.OrderID = order.ID
+		if err := tx.Create(&redemption).Error; err != nil {
+			tx.Rollback()
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error adding promotion redemption %s", err.Error())
+			return nil, err
+		}
+	}
+
+	// Replace the kitchen tickets of the order
+	if err := tx.Where("order_id = ?", order.ID).Delete(&entity.KitchenTicket{}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error deleting old kitchen tickets %s", err.Error())
+		return nil, err
+	}
+
+	for _, ticket := range order.KitchenTickets {
+		ticket.OrderID = order.ID
+		if err := tx.Create(&ticket).Error; err != nil {
+			tx.Rollback()
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error adding kitchen ticket %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if err := addOutboxEvent(tx, event.OrderEdited, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error adding outbox event %s", err.Error())
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
@@ -138,3 +305,473 @@ func (r *orderRepository) EditOrder(ctx context.Context, order *entity.Order) (*
 
 	return order, nil
 }
+
+func (r *orderRepository) GetOrdersByStatus(ctx context.Context, clientToken string, statuses []int) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Preload("OrderDetails").
+		Joins("JOIN client ON `order`.client_id = client.id").
+		Where("client.token = ? AND `order`.status IN ?", clientToken, statuses).
+		Order("`order`.created_at ASC").
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOrdersByStatus  %s", err.Error())
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.StatusText = model.OrderStatusText(order.Status)
+	}
+
+	return orders, nil
+}
+
+// GetOrdersByPhone returns the orders of a client with one of the statuses and the normalised
+// phone number, oldest first.
+func (r *orderRepository) GetOrdersByPhone(ctx context.Context, clientID uint, statuses []int, phoneNumber string) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Preload("OrderDetails").
+		Where("client_id = ? AND phone_number = ? AND status IN ?", clientID, phoneNumber, statuses).
+		Order("created_at ASC").
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOrdersByPhone  %s", err.Error())
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.StatusText = model.OrderStatusText(order.Status)
+	}
+
+	return orders, nil
+}
+
+// GetOrdersUpdatedSince returns the orders with one of the statuses that changed after since,
+// most recently updated first. Order details are not loaded.
+func (r *orderRepository) GetOrdersUpdatedSince(ctx context.Context, clientToken string, statuses []int, since time.Time) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Joins("JOIN client ON `order`.client_id = client.id").
+		Where("client.token = ? AND `order`.status IN ? AND `order`.updated_at >= ?", clientToken, statuses, since).
+		Order("`order`.updated_at DESC").
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOrdersUpdatedSince  %s", err.Error())
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.StatusText = model.OrderStatusText(order.Status)
+	}
+
+	return orders, nil
+}
+
+func (r *orderRepository) GetOrderDetailByID(ctx context.Context, detailID uint, clientToken string) (*entity.OrderDetail, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var detail entity.OrderDetail
+
+	if err := r.db.Joins("JOIN `order` ON order_detail.order_id = `order`.id").
+		Joins("JOIN client ON `order`.client_id = client.id").
+		Where("order_detail.id = ? AND client.token = ?", detailID, clientToken).
+		First(&detail).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOrderDetailByID  %s", err.Error())
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// UpdatePrepStatus sets the preparation status of an order line and advances the kitchen tickets
+// and the order status when the state of all lines requires it. The order row is locked so lines
+// that are bumped at the same time cannot miss the transition to Success. Only paid orders that
+// are not finished are prepared.
+func (r *orderRepository) UpdatePrepStatus(ctx context.Context, detailID uint, prepStatus int) (*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+	now := time.Now()
+
+	var detail entity.OrderDetail
+	if err := tx.First(&detail, detailID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdatePrepStatus  %s", err.Error())
+		return nil, err
+	}
+
+	var order entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, detail.OrderID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdatePrepStatus  %s", err.Error())
+		return nil, err
+	}
+
+	if err := checkPreparation(&order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	detailUpdates := map[string]interface{}{"prep_status": prepStatus}
+	if detail.StartedAt == nil {
+		detailUpdates["started_at"] = now
+	}
+	if prepStatus == model.PrepStatusReady {
+		detailUpdates["ready_at"] = now
+	}
+	if err := tx.Model(&entity.OrderDetail{}).Where("id = ?", detail.ID).Updates(detailUpdates).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdatePrepStatus  %s", err.Error())
+		return nil, err
+	}
+
+	if err := syncOrderPreparation(tx, &order, now); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdatePrepStatus  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	order.StatusText = model.OrderStatusText(order.Status)
+	return &order, nil
+}
+
+// UpdateOrderStatus moves an order to a new status and records when it started processing,
+// completed or was cancelled. The first status from Paid records the payment of the order in the
+// open shift with the payment method of order. Completing an order takes its reserved stock and
+// records the ingredients it used, cancelling returns the stock and pays back cash payments. The
+// order row is locked and the change is checked against it, so concurrent requests can not pay,
+// cancel or complete an order twice.
+func (r *orderRepository) UpdateOrderStatus(ctx context.Context, order *entity.Order, status int) (*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	now := time.Now()
+	tx := r.db.Begin()
+
+	var current entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, order.ID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdateOrderStatus  %s", err.Error())
+		return nil, err
+	}
+
+	if !model.CanChangeOrderStatus(&current, status) {
+		tx.Rollback()
+		return nil, ErrInvalidOrderStatus
+	}
+	if status == model.OrderStatusCancelled && current.LockedAt != nil {
+		tx.Rollback()
+		return nil, ErrOrderLocked
+	}
+
+	if current.PaidAt == nil {
+		current.PaymentMethod = order.PaymentMethod
+	}
+	order = &current
+
+	updates := map[string]interface{}{"status": status}
+	switch status {
+	case model.OrderStatusProcessing:
+		if order.ProcessingAt == nil {
+			updates["processing_at"] = now
+			order.ProcessingAt = &now
+		}
+	case model.OrderStatusSuccess:
+		if order.ProcessingAt == nil {
+			updates["processing_at"] = now
+			order.ProcessingAt = &now
+		}
+		updates["completed_at"] = now
+		order.CompletedAt = &now
+	case model.OrderStatusCancelled:
+		updates["cancelled_at"] = now
+		order.CancelledAt = &now
+	}
+
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdateOrderStatus  %s", err.Error())
+		return nil, err
+	}
+
+	order.Status = status
+	order.StatusText = model.OrderStatusText(status)
+
+	var err error
+	if status == model.OrderStatusCancelled {
+		err = reverseLoyaltyPoints(tx, order, now)
+		if err == nil {
+			err = releaseStock(tx, order)
+		}
+		if err == nil {
+			err = refundPayment(tx, order, now)
+		}
+	} else if status >= model.OrderStatusPaid {
+		// The customer earns the points of the order when it is paid
+		if order.PaidAt == nil {
+			err = recordPayment(tx, order, now)
+			if err == nil {
+				err = accrueLoyaltyPoints(tx, order, now)
+			}
+		}
+		if err == nil && status == model.OrderStatusSuccess {
+			err = completeOrder(tx, order, now)
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdateOrderStatus  %s", err.Error())
+		return nil, err
+	}
+
+	eventType := event.OrderStatusChanged
+	if status == model.OrderStatusCancelled {
+		eventType = event.OrderCancelled
+	}
+	if err := addOutboxEvent(tx, eventType, order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdateOrderStatus  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// completeOrder settles what a completed order used: its reserved stock and the ingredients of
+// the recipes of its products.
+func completeOrder(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if err := commitStock(tx, order); err != nil {
+		return err
+	}
+
+	return consumeIngredients(tx, order, now)
+}
+
+// GetScheduledOrders returns the scheduled orders that have not been released to the kitchen,
+// earliest pickup first.
+func (r *orderRepository) GetScheduledOrders(ctx context.Context, clientToken string) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Preload("OrderDetails").
+		Joins("JOIN client ON `order`.client_id = client.id").
+		Where("client.token = ? AND `order`.released_at IS NULL AND `order`.status <> ?", clientToken, model.OrderStatusCancelled).
+		Order("`order`.scheduled_at ASC").
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetScheduledOrders  %s", err.Error())
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.StatusText = model.OrderStatusText(order.Status)
+	}
+
+	return orders, nil
+}
+
+// GetOrdersToRelease returns up to limit scheduled orders of all clients whose pickup time is
+// within the lead time of their client at now, earliest pickup first.
+func (r *orderRepository) GetOrdersToRelease(ctx context.Context, now time.Time, limit int) ([]*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var orders []*entity.Order
+
+	if err := r.db.Joins("LEFT JOIN client_setting ON client_setting.client_id = `order`.client_id").
+		Where("`order`.released_at IS NULL AND `order`.status <> ?", model.OrderStatusCancelled).
+		Where("`order`.scheduled_at <= DATE_ADD(?, INTERVAL IF(IFNULL(client_setting.schedule_lead_minutes, 0) > 0, client_setting.schedule_lead_minutes, ?) MINUTE)",
+			now, model.DefaultScheduleLeadMinutes).
+		Order("`order`.scheduled_at ASC").
+		Limit(limit).
+		Find(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOrdersToRelease  %s", err.Error())
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ReleaseOrder releases a scheduled order to the kitchen and gives it the next queue number of
+// the day. ErrOrderNotScheduled is returned when the order was released or cancelled meanwhile.
+func (r *orderRepository) ReleaseOrder(ctx context.Context, orderID uint, now time.Time) (*entity.Order, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	var order entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error ReleaseOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if order.ReleasedAt != nil || order.Status == model.OrderStatusCancelled {
+		tx.Rollback()
+		return nil, ErrOrderNotScheduled
+	}
+
+	order.ReleasedAt = &now
+	if err := assignQueueNumber(tx, &order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error ReleaseOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+		"released_at":  order.ReleasedAt,
+		"queue_number": order.QueueNumber,
+		"order_number": order.OrderNumber,
+	}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error ReleaseOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Where("order_id = ?", order.ID).Order("id ASC").Find(&order.OrderDetails).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error ReleaseOrder  %s", err.Error())
+		return nil, err
+	}
+
+	order.StatusText = model.OrderStatusText(order.Status)
+	if err := addOutboxEvent(tx, event.OrderReleased, &order); err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error ReleaseOrder  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// checkPreparation returns why the kitchen can not prepare an order, if it can not. Orders are
+// prepared once paid so that a completed order has always recorded its payment.
+func checkPreparation(order *entity.Order) error {
+	if order.Status >= model.OrderStatusSuccess {
+		return ErrInvalidOrderStatus
+	}
+	if order.Status < model.OrderStatusPaid {
+		return ErrOrderNotPaid
+	}
+
+	return nil
+}
+
+// assignQueueNumber gives an order the next queue number of its client on the day the order was
+// released to the kitchen in the time zone of the client, together with the order number derived
+// from it.
+func assignQueueNumber(tx *gorm.DB, order *entity.Order) error {
+	loc := time.Local
+	var settings []entity.ClientSetting
+	if err := tx.Where("client_id = ?", order.ClientID).Limit(1).Find(&settings).Error; err != nil {
+		return err
+	}
+	if len(settings) > 0 {
+		loc = model.ClientLocation(&settings[0])
+	}
+
+	releasedAt := order.ReleasedAt.In(loc)
+	dayStart := time.Date(releasedAt.Year(), releasedAt.Month(), releasedAt.Day(), 0, 0, 0, 0, loc)
+
+	// Retrieve the latest queue number for the release date and client
+	var latestQueueNumber int
+	if err := tx.Table("order").
+		Where("released_at >= ? AND released_at < ? AND client_id = ?", dayStart, dayStart.AddDate(0, 0, 1), order.ClientID).
+		Select("IFNULL(MAX(queue_number), 0)").
+		Set("gorm:query_option", "FOR UPDATE").
+		Scan(&latestQueueNumber).
+		Error; err != nil {
+		return err
+	}
+
+	// Increment the queue number
+	latestQueueNumber++
+	order.QueueNumber = latestQueueNumber
+
+	// Generate the order number with leading zeros
+	order.OrderNumber = fmt.Sprintf("ORD-%04d", latestQueueNumber)
+
+	return nil
+}
+
+// syncOrderPreparation recalculates the status of the kitchen tickets and of the order from the
+// preparation status of the order lines. The caller must hold a lock on the order row.
+// A status change of the order is written to the outbox and a completed order takes its reserved
+// stock and records the ingredients it used. Loyalty points are not credited here, the customer
+// earns them when the order is paid.
+func syncOrderPreparation(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if err := tx.Where("order_id = ?", order.ID).Order("id ASC").Find(&order.OrderDetails).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("order_id = ?", order.ID).Order("id ASC").Find(&order.KitchenTickets).Error; err != nil {
+		return err
+	}
+
+	for i := range order.KitchenTickets {
+		ticket := &order.KitchenTickets[i]
+
+		var lines []entity.OrderDetail
+		for _, detail := range order.OrderDetails {
+			if detail.StationID == ticket.StationID {
+				lines = append(lines, detail)
+			}
+		}
+
+		status := model.KitchenTicketStatus(lines)
+		if status == ticket.Status {
+			continue
+		}
+
+		ticketUpdates := map[string]interface{}{"status": status}
+		if status == model.PrepStatusReady {
+			ticketUpdates["bumped_at"] = now
+			ticket.BumpedAt = &now
+		}
+		if err := tx.Model(&entity.KitchenTicket{}).Where("id = ?", ticket.ID).Updates(ticketUpdates).Error; err != nil {
+			return err
+		}
+		ticket.Status = status
+	}
+
+	status := model.KitchenOrderStatus(order.Status, order.OrderDetails)
+	if status == order.Status {
+		return nil
+	}
+
+	orderUpdates := map[string]interface{}{"status": status}
+	if status >= model.OrderStatusProcessing && order.ProcessingAt == nil {
+		orderUpdates["processing_at"] = now
+		order.ProcessingAt = &now
+	}
+	if status == model.OrderStatusSuccess {
+		orderUpdates["completed_at"] = now
+		order.CompletedAt = &now
+	}
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(orderUpdates).Error; err != nil {
+		return err
+	}
+	order.Status = status
+
+	if status == model.OrderStatusSuccess {
+		if err := completeOrder(tx, order, now); err != nil {
+			return err
+		}
+	}
+
+	return addOutboxEvent(tx, event.OrderStatusChanged, order)
+}
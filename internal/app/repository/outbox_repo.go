@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// maxOutboxErrorLength is the size of the last_error column.
+const maxOutboxErrorLength = 500
+
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, sink string, now time.Time, limit int) ([]*entity.OutboxEvent, error)
+	MarkPublished(ctx context.Context, eventID uint) error
+	MarkFailed(ctx context.Context, e *entity.OutboxEvent) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// GetPendingEvents returns the oldest events of the sink that are due at now in the order they were written.
+// An event waits while an earlier event of the same order is backing off, so the events of an
+// order are published in order. Dead events are skipped.
+func (r *outboxRepository) GetPendingEvents(ctx context.Context, sink string, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var events []*entity.OutboxEvent
+
+	if err := r.db.Where("sink = ? AND published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", sink, now).
+		Where("NOT EXISTS (SELECT 1 FROM order_outbox earlier WHERE earlier.sink = order_outbox.sink AND earlier.order_id = order_outbox.order_id AND earlier.id < order_outbox.id AND earlier.published_at IS NULL AND earlier.dead_at IS NULL AND earlier.next_attempt_at > ?)", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetPendingEvents  %s", err.Error())
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, eventID uint) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error MarkPublished  %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed stores the attempts, the error and the next attempt of an event that failed, or the
+// time it died.
+func (r *outboxRepository) MarkFailed(ctx context.Context, e *entity.OutboxEvent) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	message := e.LastError
+	if len(message) > maxOutboxErrorLength {
+		message = message[:maxOutboxErrorLength]
+	}
+
+	if err := r.db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", e.ID).
+		Updates(map[string]interface{}{
+			"attempts":        e.Attempts,
+			"last_error":      message,
+			"next_attempt_at": e.NextAttemptAt,
+			"dead_at":         e.DeadAt,
+		}).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error MarkFailed  %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// addOutboxEvent writes an order event to the outbox for every sink within the transaction of the
+// order change, so the event is stored if and only if the change is committed.
+func addOutboxEvent(tx *gorm.DB, eventType string, order *entity.Order) error {
+	order.StatusText = model.OrderStatusText(order.Status)
+
+	payload, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	events := make([]*entity.OutboxEvent, 0, len(model.OutboxSinks))
+	for _, sink := range model.OutboxSinks {
+		events = append(events, &entity.OutboxEvent{
+			Sink:          sink,
+			ClientID:      order.ClientID,
+			OrderID:       order.ID,
+			Type:          eventType,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+		})
+	}
+
+	return tx.Create(&events).Error
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPromoCodeExists is returned when adding a promotion with a code the client already uses.
+var ErrPromoCodeExists = errors.New("promo code already exists")
+
+// ErrPromoUsageLimit is returned when a redemption of an order exceeds a usage limit of its
+// promotion.
+var ErrPromoUsageLimit = errors.New("promotion usage limit reached")
+
+type PromotionRepository interface {
+	AddPromotion(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+	GetPromotions(ctx context.Context, clientID uint) ([]*entity.Promotion, error)
+	GetPromotionByCode(ctx context.Context, clientID uint, code string) (*entity.Promotion, error)
+	GetAutoApplyPromotions(ctx context.Context, clientID uint, now time.Time) ([]*entity.Promotion, error)
+	CountRedemptions(ctx context.Context, promotionID uint, phoneNumber string, excludeOrderID uint) (int64, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+// AddPromotion adds a promotion of a client. The client row is locked so two promotions with the
+// same code can not be added at the same time. Automatic promotions may have no code.
+func (r *promotionRepository) AddPromotion(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity.Client{}, promotion.ClientID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddPromotion  %s", err.Error())
+		return nil, err
+	}
+
+	if promotion.Code != "" {
+		var count int64
+		if err := tx.Model(&entity.Promotion{}).
+			Where("client_id = ? AND code = ?", promotion.ClientID, promotion.Code).
+			Count(&count).
+			Error; err != nil {
+			tx.Rollback()
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddPromotion  %s", err.Error())
+			return nil, err
+		}
+		if count > 0 {
+			tx.Rollback()
+			return nil, ErrPromoCodeExists
+		}
+	}
+
+	// Promotion products are created in the same statement through the association
+	if err := tx.Create(promotion).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddPromotion  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddPromotion  %s", err.Error())
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *promotionRepository) GetPromotions(ctx context.Context, clientID uint) ([]*entity.Promotion, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var promotions []*entity.Promotion
+
+	if err := r.db.Preload("PromotionProducts").
+		Where("client_id = ?", clientID).
+		Order("id DESC").
+		Find(&promotions).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetPromotions  %s", err.Error())
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func (r *promotionRepository) GetPromotionByCode(ctx context.Context, clientID uint, code string) (*entity.Promotion, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var promotion entity.Promotion
+
+	if err := r.db.Preload("PromotionProducts").
+		Where("client_id = ? AND code = ? AND is_active = ?", clientID, code, true).
+		First(&promotion).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetPromotionByCode  %s", err.Error())
+		return nil, err
+	}
+
+	return &promotion, nil
+}
+
+func (r *promotionRepository) GetAutoApplyPromotions(ctx context.Context, clientID uint, now time.Time) ([]*entity.Promotion, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var promotions []*entity.Promotion
+
+	// The hour window is checked by the service, only the date range is filtered here
+	if err := r.db.Preload("PromotionProducts").
+		Where("client_id = ? AND auto_apply = ? AND is_active = ?", clientID, true, true).
+		Where("(start_at IS NULL OR start_at <= ?) AND (end_at IS NULL OR end_at >= ?)", now, now).
+		Find(&promotions).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetAutoApplyPromotions  %s", err.Error())
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// CountRedemptions counts how many times a promotion has been redeemed. When phoneNumber
+// is not empty only the redemptions of that customer are counted. Redemptions belonging to
+// excludeOrderID are ignored so an edited order does not count against itself, redemptions of
+// cancelled orders are not counted either.
+func (r *promotionRepository) CountRedemptions(ctx context.Context, promotionID uint, phoneNumber string, excludeOrderID uint) (int64, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var count int64
+
+	query := countedRedemptions(r.db, promotionID, excludeOrderID)
+	if phoneNumber != "" {
+		query = query.Where("promotion_redemption.phone_number = ?", phoneNumber)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error CountRedemptions  %s", err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// checkPromotionUsage counts the redemptions of the promotions of an order again within the
+// transaction of the order. The promotion rows are locked, so two orders can not both take the
+// last redemption the service allowed. The redemptions of the order itself are not counted.
+func checkPromotionUsage(tx *gorm.DB, order *entity.Order) error {
+	for _, redemption := range order.PromotionRedemptions {
+		var promotion entity.Promotion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, redemption.PromotionID).Error; err != nil {
+			return err
+		}
+
+		if promotion.UsageLimit > 0 {
+			var count int64
+			if err := countedRedemptions(tx, promotion.ID, order.ID).
+				Count(&count).
+				Error; err != nil {
+				return err
+			}
+			if count >= int64(promotion.UsageLimit) {
+				return ErrPromoUsageLimit
+			}
+		}
+
+		if promotion.PerCustomerLimit > 0 {
+			var count int64
+			if err := countedRedemptions(tx, promotion.ID, order.ID).
+				Where("promotion_redemption.phone_number = ?", redemption.PhoneNumber).
+				Count(&count).
+				Error; err != nil {
+				return err
+			}
+			if count >= int64(promotion.PerCustomerLimit) {
+				return ErrPromoUsageLimit
+			}
+		}
+	}
+
+	return nil
+}
+
+// countedRedemptions selects the redemptions of a promotion that count against its limits: those
+// of orders other than excludeOrderID that are not cancelled. A cancelled order gives its
+// redemption back, the row is kept as history.
+func countedRedemptions(db *gorm.DB, promotionID uint, excludeOrderID uint) *gorm.DB {
+	return db.Model(&entity.PromotionRedemption{}).
+		Joins("JOIN `order` ON `order`.id = promotion_redemption.order_id").
+		Where("promotion_redemption.promotion_id = ? AND promotion_redemption.order_id <> ?", promotionID, excludeOrderID).
+		Where("`order`.status <> ?", model.OrderStatusCancelled)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	GetDailySales(ctx context.Context, clientID uint, from time.Time, to time.Time, loc *time.Location) ([]model.DailySalesRow, error)
+	GetProductSales(ctx context.Context, clientID uint, from time.Time, to time.Time, sortBy string, limit int) ([]model.ProductSales, error)
+	GetHeatmapBuckets(ctx context.Context, clientID uint, from time.Time, to time.Time, statuses []int) ([]model.HeatmapBucket, error)
+}
+
+// rankingOrders are the orders of the product ranking by sort, ties are ranked by the other
+// measure and then by product.
+var rankingOrders = map[string]string{
+	model.RankingByQuantity: "quantity DESC, revenue DESC, order_detail.product_id ASC",
+	model.RankingByRevenue:  "revenue DESC, quantity DESC, order_detail.product_id ASC",
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+// GetDailySales aggregates the orders of a client created from up to but not including to per
+// day in loc and status. The orders are summed per quarter of an hour in the database and the
+// quarters are added to the day they fall on in loc, so the days follow the time zone of the
+// client. The orders and their lines are aggregated separately so the amounts of an order are not
+// repeated for every line.
+func (r *reportRepository) GetDailySales(ctx context.Context, clientID uint, from time.Time, to time.Time, loc *time.Location) ([]model.DailySalesRow, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	type quarterSales struct {
+		Start time.Time
+		model.DailySalesRow
+	}
+
+	var orders []quarterSales
+	if err := r.db.Table("`order`").
+		Select("TIMESTAMP(DATE(created_at), MAKETIME(HOUR(created_at), FLOOR(MINUTE(created_at) / 15) * 15, 0)) AS start, status, COUNT(*) AS order_count, SUM(promo_discount) AS promo_discount, SUM(service_charge) AS service_charge, SUM(tax) AS tax, SUM(total) AS total").
+		Where("client_id = ? AND created_at >= ? AND created_at < ?", clientID, from, to).
+		Group("start, status").
+		Scan(&orders).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetDailySales  %s", err.Error())
+		return nil, err
+	}
+
+	var lines []quarterSales
+	if err := r.db.Table("order_detail").
+		Select("TIMESTAMP(DATE(`order`.created_at), MAKETIME(HOUR(`order`.created_at), FLOOR(MINUTE(`order`.created_at) / 15) * 15, 0)) AS start, `order`.status, SUM(order_detail.price * order_detail.quantity) AS gross_sales, SUM(order_detail.price * order_detail.quantity - order_detail.total) AS line_discounts").
+		Joins("JOIN `order` ON `order`.id = order_detail.order_id").
+		Where("`order`.client_id = ? AND `order`.created_at >= ? AND `order`.created_at < ?", clientID, from, to).
+		Group("start, `order`.status").
+		Scan(&lines).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetDailySales  %s", err.Error())
+		return nil, err
+	}
+
+	type dayStatus struct {
+		date   string
+		status int
+	}
+	var rows []model.DailySalesRow
+	index := make(map[dayStatus]int)
+	row := func(start time.Time, status int) *model.DailySalesRow {
+		key := dayStatus{start.In(loc).Format("2006-01-02"), status}
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, model.DailySalesRow{Date: key.date, Status: status})
+		}
+		return &rows[i]
+	}
+
+	for _, quarter := range orders {
+		day := row(quarter.Start, quarter.Status)
+		day.OrderCount += quarter.OrderCount
+		day.PromoDiscount += quarter.PromoDiscount
+		day.ServiceCharge += quarter.ServiceCharge
+		day.Tax += quarter.Tax
+		day.Total += quarter.Total
+	}
+	for _, quarter := range lines {
+		day := row(quarter.Start, quarter.Status)
+		day.GrossSales += quarter.GrossSales
+		day.LineDiscounts += quarter.LineDiscounts
+	}
+
+	return rows, nil
+}
+
+// GetProductSales returns the best selling products of the orders of a client created from up to
+// but not including to. Cancelled orders are not counted.
+func (r *reportRepository) GetProductSales(ctx context.Context, clientID uint, from time.Time, to time.Time, sortBy string, limit int) ([]model.ProductSales, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	products := []model.ProductSales{}
+
+	order, ok := rankingOrders[sortBy]
+	if !ok {
+		order = rankingOrders[model.RankingByQuantity]
+	}
+
+	if err := r.db.Table("order_detail").
+		Select("order_detail.product_id, SUM(order_detail.quantity) AS quantity, SUM(order_detail.total) AS revenue, COUNT(DISTINCT order_detail.order_id) AS order_count").
+		Joins("JOIN `order` ON `order`.id = order_detail.order_id").
+		Where("`order`.client_id = ? AND `order`.created_at >= ? AND `order`.created_at < ? AND `order`.status <> ?", clientID, from, to, model.OrderStatusCancelled).
+		Group("order_detail.product_id").
+		Order(order).
+		Limit(limit).
+		Scan(&products).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetProductSales  %s", err.Error())
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// GetHeatmapBuckets counts the orders of a client with one of the statuses created from up to but
+// not including to per quarter of an hour.
+func (r *reportRepository) GetHeatmapBuckets(ctx context.Context, clientID uint, from time.Time, to time.Time, statuses []int) ([]model.HeatmapBucket, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var buckets []model.HeatmapBucket
+
+	if err := r.db.Table("`order`").
+		Select("TIMESTAMP(DATE(created_at), MAKETIME(HOUR(created_at), FLOOR(MINUTE(created_at) / 15) * 15, 0)) AS start, COUNT(*) AS order_count, SUM(total) AS sales").
+		Where("client_id = ? AND created_at >= ? AND created_at < ? AND status IN ?", clientID, from, to, statuses).
+		Group("start").
+		Scan(&buckets).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetHeatmapBuckets  %s", err.Error())
+		return nil, err
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrShiftAlreadyOpen is returned when opening a shift while the client has an open shift.
+var ErrShiftAlreadyOpen = errors.New("shift already open")
+
+// ErrShiftNotFound is returned when the client has no shift with the ID or no open shift.
+var ErrShiftNotFound = errors.New("shift not found")
+
+type ShiftRepository interface {
+	OpenShift(ctx context.Context, shift *entity.Shift) (*entity.Shift, error)
+	GetOpenShift(ctx context.Context, clientID uint) (*entity.Shift, error)
+	GetShift(ctx context.Context, clientID uint, shiftID uint) (*entity.Shift, error)
+	GetShifts(ctx context.Context, clientID uint, from string, to string) ([]*entity.Shift, error)
+	CloseShift(ctx context.Context, clientID uint, countedCash float64, cashier string, note string, now time.Time) (*entity.Shift, error)
+	GetShiftPayments(ctx context.Context, shiftID uint) ([]model.PaymentSummary, error)
+}
+
+type shiftRepository struct {
+	db *gorm.DB
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepository{
+		db: db,
+	}
+}
+
+// OpenShift opens a shift of a client. The client row is locked so two registers can not open a
+// shift at the same time.
+func (r *shiftRepository) OpenShift(ctx context.Context, shift *entity.Shift) (*entity.Shift, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity.Client{}, shift.ClientID).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error OpenShift  %s", err.Error())
+		return nil, err
+	}
+
+	if _, err := openShift(tx, shift.ClientID); err == nil {
+		tx.Rollback()
+		return nil, ErrShiftAlreadyOpen
+	} else if !errors.Is(err, ErrShiftNotFound) {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error OpenShift  %s", err.Error())
+		return nil, err
+	}
+
+	shift.Status = model.ShiftStatusOpen
+	model.SettleShift(shift, model.CashTotals{})
+	if err := tx.Create(shift).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error OpenShift  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// GetOpenShift returns the open shift of a client with the cash taken so far.
+func (r *shiftRepository) GetOpenShift(ctx context.Context, clientID uint) (*entity.Shift, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	shift, err := openShift(r.db, clientID)
+	if err != nil {
+		if !errors.Is(err, ErrShiftNotFound) {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOpenShift  %s", err.Error())
+		}
+		return nil, err
+	}
+
+	totals, err := cashTotals(r.db, shift.ID)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetOpenShift  %s", err.Error())
+		return nil, err
+	}
+	model.SettleShift(shift, totals)
+
+	return shift, nil
+}
+
+// GetShift returns a shift of a client. The cash totals of an open shift are those taken so far.
+func (r *shiftRepository) GetShift(ctx context.Context, clientID uint, shiftID uint) (*entity.Shift, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var shift entity.Shift
+
+	if err := r.db.Where("client_id = ?", clientID).First(&shift, shiftID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShiftNotFound
+		}
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetShift  %s", err.Error())
+		return nil, err
+	}
+
+	if shift.Status == model.ShiftStatusOpen {
+		totals, err := cashTotals(r.db, shift.ID)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetShift  %s", err.Error())
+			return nil, err
+		}
+		model.SettleShift(&shift, totals)
+	}
+
+	return &shift, nil
+}
+
+// GetShifts returns the shifts of a client opened on the business days from to, both inclusive
+// YYYY-MM-DD, latest first. The cash totals of an open shift are those stored when it opened.
+func (r *shiftRepository) GetShifts(ctx context.Context, clientID uint, from string, to string) ([]*entity.Shift, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	shifts := []*entity.Shift{}
+
+	if err := r.db.Where("client_id = ? AND business_date >= ? AND business_date <= ?", clientID, from, to).
+		Order("opened_at DESC, id DESC").
+		Find(&shifts).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetShifts  %s", err.Error())
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+// CloseShift closes the open shift of a client with the cash counted in the register and locks
+// the orders created before the closing, except orders scheduled for a later pickup, from
+// further edits.
+func (r *shiftRepository) CloseShift(ctx context.Context, clientID uint, countedCash float64, cashier string, note string, now time.Time) (*entity.Shift, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	tx := r.db.Begin()
+
+	shift, err := openShift(tx.Clauses(clause.Locking{Strength: "UPDATE"}), clientID)
+	if err != nil {
+		tx.Rollback()
+		if !errors.Is(err, ErrShiftNotFound) {
+			logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error CloseShift  %s", err.Error())
+		}
+		return nil, err
+	}
+
+	totals, err := cashTotals(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error CloseShift  %s", err.Error())
+		return nil, err
+	}
+
+	shift.Status = model.ShiftStatusClosed
+	shift.ClosedBy = cashier
+	shift.ClosingNote = note
+	shift.ClosedAt = &now
+	shift.CountedCash = &countedCash
+	model.SettleShift(shift, totals)
+
+	if err := tx.Save(shift).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error CloseShift  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.Order{}).
+		Where("client_id = ? AND locked_at IS NULL AND created_at <= ?", clientID, now).
+		Where("scheduled_at IS NULL OR scheduled_at <= ?", now).
+		Update("locked_at", now).
+		Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error CloseShift  %s", err.Error())
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error committing transaction %s", err.Error())
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// GetShiftPayments returns the number and total of the orders paid during a shift by payment
+// method. Cancelled orders are left out.
+func (r *shiftRepository) GetShiftPayments(ctx context.Context, shiftID uint) ([]model.PaymentSummary, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var payments []model.PaymentSummary
+
+	if err := r.db.Model(&entity.Order{}).
+		Select("payment_method, COUNT(*) AS order_count, SUM(total - points_amount) AS total").
+		Where("shift_id = ? AND status <> ?", shiftID, model.OrderStatusCancelled).
+		Group("payment_method").
+		Order("payment_method ASC").
+		Scan(&payments).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetShiftPayments  %s", err.Error())
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// openShift returns the open shift of a client.
+func openShift(db *gorm.DB, clientID uint) (*entity.Shift, error) {
+	var shift entity.Shift
+	if err := db.Where("client_id = ? AND status = ?", clientID, model.ShiftStatusOpen).First(&shift).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShiftNotFound
+		}
+		return nil, err
+	}
+
+	return &shift, nil
+}
+
+// cashTotals sums the cash movements of a shift by type.
+func cashTotals(db *gorm.DB, shiftID uint) (model.CashTotals, error) {
+	var totals model.CashTotals
+	err := db.Model(&entity.ShiftCashMovement{}).
+		Select("COALESCE(SUM(CASE WHEN type = ? THEN amount END), 0) AS sales, COALESCE(-SUM(CASE WHEN type = ? THEN amount END), 0) AS refunds, COALESCE(SUM(CASE WHEN type = ? THEN amount END), 0) AS adjustments",
+			model.CashMovementSale, model.CashMovementRefund, model.CashMovementAdjustment).
+		Where("shift_id = ?", shiftID).
+		Scan(&totals).
+		Error
+
+	return totals, err
+}
+
+// addCashMovement records cash taken or paid out for an order in the open shift of its client.
+// The shift row is read with a shared lock so the shift can not close until the movement is
+// committed. Cash is not tracked while no shift is open.
+func addCashMovement(tx *gorm.DB, order *entity.Order, movementType string, amount float64, now time.Time) (*entity.Shift, error) {
+	shift, err := openShift(tx.Clauses(clause.Locking{Strength: "SHARE"}), order.ClientID)
+	if errors.Is(err, ErrShiftNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if amount == 0 {
+		return shift, nil
+	}
+
+	return shift, tx.Create(&entity.ShiftCashMovement{
+		ShiftID:   shift.ID,
+		ClientID:  order.ClientID,
+		OrderID:   order.ID,
+		Type:      movementType,
+		Amount:    amount,
+		CreatedAt: now,
+	}).Error
+}
+
+// recordPayment records the payment of an order in the open shift. Only cash payments move cash,
+// orders paid otherwise are listed in the closing report of the shift.
+func recordPayment(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if order.PaymentMethod == "" {
+		order.PaymentMethod = model.PaymentMethodCash
+	}
+
+	var amount float64
+	if order.PaymentMethod == model.PaymentMethodCash {
+		amount = model.OrderAmountDue(order)
+	}
+
+	shift, err := addCashMovement(tx, order, model.CashMovementSale, amount, now)
+	if err != nil {
+		return err
+	}
+
+	order.PaidAt = &now
+	order.ShiftID = nil
+	if shift != nil {
+		order.ShiftID = &shift.ID
+	}
+
+	return tx.Model(&entity.Order{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+		"payment_method": order.PaymentMethod,
+		"paid_at":        order.PaidAt,
+		"shift_id":       order.ShiftID,
+	}).Error
+}
+
+// refundPayment pays back the cash of a cancelled order that was paid in cash from the open shift.
+func refundPayment(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if order.PaidAt == nil || order.PaymentMethod != model.PaymentMethodCash {
+		return nil
+	}
+
+	_, err := addCashMovement(tx, order, model.CashMovementRefund, -model.OrderAmountDue(order), now)
+	return err
+}
+
+// adjustPayment records the change in the cash taken for an order paid in cash that is edited,
+// previous is the stored order before the edit.
+func adjustPayment(tx *gorm.DB, previous *entity.Order, order *entity.Order, now time.Time) error {
+	if previous.PaidAt == nil || previous.PaymentMethod != model.PaymentMethodCash {
+		return nil
+	}
+
+	_, err := addCashMovement(tx, order, model.CashMovementAdjustment, model.OrderAmountDue(order)-model.OrderAmountDue(previous), now)
+	return err
+}
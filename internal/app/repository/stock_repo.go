@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStockNotFound is returned when removing the stock of a product that is not tracked.
+var ErrStockNotFound = errors.New("stock not found")
+
+// OutOfStockError is returned when an order asks for more of a product than is available.
+type OutOfStockError struct {
+	Shortages []model.StockShortage
+}
+
+func (e *OutOfStockError) Error() string {
+	return "out of stock"
+}
+
+type StockRepository interface {
+	GetStocks(ctx context.Context, clientID uint) ([]*entity.ProductStock, error)
+	SetStock(ctx context.Context, clientID uint, productID uint, onHand int) (*entity.ProductStock, error)
+	DeleteStock(ctx context.Context, clientID uint, productID uint) error
+}
+
+type stockRepository struct {
+	db *gorm.DB
+}
+
+func NewStockRepository(db *gorm.DB) StockRepository {
+	return &stockRepository{
+		db: db,
+	}
+}
+
+// GetStocks returns the tracked products of a client ordered by product.
+func (r *stockRepository) GetStocks(ctx context.Context, clientID uint) ([]*entity.ProductStock, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	stocks := []*entity.ProductStock{}
+
+	if err := r.db.Where("client_id = ?", clientID).
+		Order("product_id ASC").
+		Find(&stocks).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetStocks  %s", err.Error())
+		return nil, err
+	}
+
+	for _, stock := range stocks {
+		stock.Available = model.StockAvailable(stock)
+	}
+
+	return stocks, nil
+}
+
+// SetStock sets the quantity on hand of a product and starts tracking it. The quantity reserved by
+// open orders is kept.
+func (r *stockRepository) SetStock(ctx context.Context, clientID uint, productID uint, onHand int) (*entity.ProductStock, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	stock := &entity.ProductStock{ClientID: clientID, ProductID: productID, OnHand: onHand}
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "client_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"on_hand", "updated_at"}),
+	}).Create(stock).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SetStock  %s", err.Error())
+		return nil, err
+	}
+
+	if err := r.db.Where("client_id = ? AND product_id = ?", clientID, productID).First(stock).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error SetStock  %s", err.Error())
+		return nil, err
+	}
+	stock.Available = model.StockAvailable(stock)
+
+	return stock, nil
+}
+
+// DeleteStock stops tracking the stock of a product.
+func (r *stockRepository) DeleteStock(ctx context.Context, clientID uint, productID uint) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	result := r.db.Where("client_id = ? AND product_id = ?", clientID, productID).Delete(&entity.ProductStock{})
+	if result.Error != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeleteStock  %s", result.Error.Error())
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStockNotFound
+	}
+
+	return nil
+}
+
+// reserveStock reserves the products of the order lines within the transaction of the order. The
+// stock rows are locked in product order so concurrent orders can not both take the last item.
+// An OutOfStockError lists the lines that can not be served.
+func reserveStock(tx *gorm.DB, order *entity.Order) error {
+	quantities := model.StockQuantities(order.OrderDetails)
+	if len(quantities) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+
+	var stocks []*entity.ProductStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("client_id = ? AND product_id IN ?", order.ClientID, productIDs).
+		Order("product_id ASC").
+		Find(&stocks).
+		Error; err != nil {
+		return err
+	}
+
+	tracked := make(map[uint]*entity.ProductStock, len(stocks))
+	for _, stock := range stocks {
+		tracked[stock.ProductID] = stock
+	}
+
+	if shortages := model.StockShortages(order.OrderDetails, tracked); len(shortages) > 0 {
+		return &OutOfStockError{Shortages: shortages}
+	}
+
+	for _, stock := range stocks {
+		if err := tx.Model(&entity.ProductStock{}).
+			Where("id = ?", stock.ID).
+			Update("reserved", gorm.Expr("reserved + ?", quantities[stock.ProductID])).
+			Error; err != nil {
+			return err
+		}
+	}
+
+	order.StockReserved = true
+	return nil
+}
+
+// releaseStock returns the stock reserved by a cancelled or edited order.
+func releaseStock(tx *gorm.DB, order *entity.Order) error {
+	return settleStock(tx, order, false)
+}
+
+// commitStock takes the stock reserved by a completed order from the quantity on hand.
+func commitStock(tx *gorm.DB, order *entity.Order) error {
+	return settleStock(tx, order, true)
+}
+
+// settleStock clears the reservation of an order using the stored order lines, taking the
+// quantities from the stock on hand when consumed. Orders without a reservation are skipped, so
+// the stock of an order is settled once.
+func settleStock(tx *gorm.DB, order *entity.Order, consumed bool) error {
+	if !order.StockReserved {
+		return nil
+	}
+
+	var details []entity.OrderDetail
+	if err := tx.Where("order_id = ?", order.ID).Find(&details).Error; err != nil {
+		return err
+	}
+
+	// Rows are updated in product order like they are locked by reserveStock
+	quantities := model.StockQuantities(details)
+	productIDs := make([]uint, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+
+	for _, productID := range productIDs {
+		quantity := quantities[productID]
+		updates := map[string]interface{}{"reserved": gorm.Expr("GREATEST(reserved - ?, 0)", quantity)}
+		if consumed {
+			updates["on_hand"] = gorm.Expr("GREATEST(on_hand - ?, 0)", quantity)
+		}
+
+		if err := tx.Model(&entity.ProductStock{}).
+			Where("client_id = ? AND product_id = ?", order.ClientID, productID).
+			Updates(updates).
+			Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Update("stock_reserved", false).Error; err != nil {
+		return err
+	}
+
+	order.StockReserved = false
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWebhookNotFound is returned when deactivating a subscription the client does not have.
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+type WebhookRepository interface {
+	AddSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, clientID uint) ([]*entity.WebhookSubscription, error)
+	DeactivateSubscription(ctx context.Context, clientID uint, subscriptionID uint) error
+	AddDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, clientID uint, status string) ([]*entity.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, clientID uint, deliveryID uint) (*entity.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) AddSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Create(subscription).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddSubscription  %s", err.Error())
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookRepository) GetSubscriptions(ctx context.Context, clientID uint) ([]*entity.WebhookSubscription, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var subscriptions []*entity.WebhookSubscription
+
+	if err := r.db.Where("client_id = ?", clientID).
+		Order("id ASC").
+		Find(&subscriptions).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetSubscriptions  %s", err.Error())
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeactivateSubscription stops sending events to a subscription. The pending deliveries of the
+// subscription are moved to the dead letter list, the delivery history is kept.
+func (r *webhookRepository) DeactivateSubscription(ctx context.Context, clientID uint, subscriptionID uint) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	tx := r.db.Begin()
+
+	var count int64
+	if err := tx.Model(&entity.WebhookSubscription{}).Where("id = ? AND client_id = ?", subscriptionID, clientID).Count(&count).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeactivateSubscription  %s", err.Error())
+		return err
+	}
+	if count == 0 {
+		tx.Rollback()
+		return ErrWebhookNotFound
+	}
+
+	if err := tx.Model(&entity.WebhookSubscription{}).Where("id = ?", subscriptionID).Update("is_active", false).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeactivateSubscription  %s", err.Error())
+		return err
+	}
+
+	if err := tx.Model(&entity.WebhookDelivery{}).
+		Where("subscription_id = ? AND status = ?", subscriptionID, model.WebhookDeliveryPending).
+		Updates(map[string]interface{}{
+			"status":     model.WebhookDeliveryDead,
+			"last_error": "webhook subscription is not active",
+		}).Error; err != nil {
+		tx.Rollback()
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeactivateSubscription  %s", err.Error())
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error DeactivateSubscription  %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// AddDeliveries stores new deliveries. A delivery that already exists for the same subscription
+// and event is skipped, so an outbox event that is relayed twice is delivered once.
+func (r *webhookRepository) AddDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error AddDeliveries  %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDueDeliveries returns the pending deliveries whose next attempt is due, oldest first.
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var deliveries []*entity.WebhookDelivery
+
+	if err := r.db.Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&deliveries).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetDueDeliveries  %s", err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+
+	if err := r.db.Model(&entity.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"delivered_at":     delivery.DeliveredAt,
+	}).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error UpdateDelivery  %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the latest deliveries of a client, optionally filtered by status.
+func (r *webhookRepository) GetDeliveries(ctx context.Context, clientID uint, status string) ([]*entity.WebhookDelivery, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var deliveries []*entity.WebhookDelivery
+
+	query := r.db.Where("client_id = ?", clientID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("id DESC").
+		Limit(100).
+		Find(&deliveries).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetDeliveries  %s", err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, clientID uint, deliveryID uint) (*entity.WebhookDelivery, error) {
+	requestID, _ := ctx.Value(middleware.RequestIDKey).(string)
+	var delivery entity.WebhookDelivery
+
+	if err := r.db.Where("id = ? AND client_id = ?", deliveryID, clientID).
+		First(&delivery).
+		Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": requestID}).Errorf("Error GetDeliveryByID  %s", err.Error())
+		return nil, err
+	}
+
+	return &delivery, nil
+}
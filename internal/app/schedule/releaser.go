@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+const (
+	DefaultInterval  = 30 * time.Second
+	DefaultBatchSize = 100
+)
+
+// Releaser releases scheduled orders to the kitchen once their pickup time is within the lead
+// time of their client. Released orders get their queue number and are written to the outbox
+// like any other order change.
+type Releaser struct {
+	orderRepo repository.OrderRepository
+	publisher event.Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+func NewReleaser(orderRepo repository.OrderRepository, publisher event.Publisher, interval time.Duration, batchSize int) *Releaser {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Releaser{
+		orderRepo: orderRepo,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run releases the due orders every interval until the context is cancelled.
+func (r *Releaser) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ProcessBatch(ctx, time.Now()); err != nil {
+			logging.Log.Errorf("Error releasing scheduled orders  %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch releases one batch of orders due at now and returns the number of released orders.
+// Orders released or cancelled in the meantime are skipped.
+func (r *Releaser) ProcessBatch(ctx context.Context, now time.Time) (int, error) {
+	orders, err := r.orderRepo.GetOrdersToRelease(ctx, now, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, o := range orders {
+		order, err := r.orderRepo.ReleaseOrder(ctx, o.ID, now)
+		if errors.Is(err, repository.ErrOrderNotScheduled) {
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+
+		r.publisher.Publish(event.Event{Type: event.OrderReleased, ClientID: order.ClientID, OrderID: order.ID, Order: order})
+		released++
+	}
+
+	return released, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scopes of the access tokens. The scope is part of the signature, a token issued for one scope
+// is refused by the others.
+const (
+	TokenScopeBoard  = "board"
+	TokenScopeEvents = "events"
+)
+
+// ErrInvalidAccessToken is returned for an access token that is malformed, not signed by the
+// service for the scope or expired.
+var ErrInvalidAccessToken = errors.New("invalid access token")
+
+// SignAccessToken returns an access token of the client for the scope that is valid until
+// expiresAt. The token is the client ID and the expiry, followed by the HMAC-SHA256 signature of
+// the scope, client ID and expiry.
+func SignAccessToken(secret []byte, scope string, clientID uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", clientID, expiresAt.Unix())
+
+	return payload + "." + accessTokenSignature(secret, scope, payload)
+}
+
+// ParseAccessToken verifies an access token of the scope and returns the client ID it was issued
+// for.
+func ParseAccessToken(secret []byte, scope string, token string, now time.Time) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, ErrInvalidAccessToken
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(accessTokenSignature(secret, scope, payload))) {
+		return 0, ErrInvalidAccessToken
+	}
+
+	clientID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAccessToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !now.Before(time.Unix(expiresAt, 0)) {
+		return 0, ErrInvalidAccessToken
+	}
+
+	return uint(clientID), nil
+}
+
+func accessTokenSignature(secret []byte, scope string, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(scope + "." + payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// accessTokenSecret returns the secret signing the access tokens, a random one when it is not
+// configured. Tokens then do not survive a restart and are only valid on this instance.
+func accessTokenSecret(secret string) []byte {
+	if secret == "" {
+		random := make([]byte, 32)
+		rand.Read(random)
+		return random
+	}
+
+	return []byte(secret)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+var boardStatuses = []int{model.OrderStatusProcessing, model.OrderStatusSuccess}
+
+type BoardService interface {
+	GetBoard(context.Context, string) (*model.QueueBoard, AppError)
+	IssueBoardToken(context.Context, string) (*model.BoardToken, AppError)
+	Subscribe(context.Context, string) (*event.Subscription, *model.QueueBoard, AppError)
+	SubscribeWithBoardToken(context.Context, string) (*event.Subscription, *model.QueueBoard, AppError)
+}
+
+type boardService struct {
+	orderRepo   repository.OrderRepository
+	clientRepo  repository.ClientRepository
+	hub         *event.Hub
+	tokenSecret []byte
+	tokenTTL    time.Duration
+}
+
+// NewBoardService creates the board service. Board tokens are signed with tokenSecret, a random
+// secret is used when it is empty.
+func NewBoardService(orderRepo repository.OrderRepository, clientRepo repository.ClientRepository, hub *event.Hub, tokenSecret string, tokenTTL time.Duration) BoardService {
+	if tokenTTL <= 0 {
+		tokenTTL = model.DefaultBoardTokenTTL
+	}
+
+	return &boardService{
+		orderRepo:   orderRepo,
+		clientRepo:  clientRepo,
+		hub:         hub,
+		tokenSecret: accessTokenSecret(tokenSecret),
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// GetBoard returns the orders in preparation and the latest orders completed today in the time
+// zone of the client.
+func (s *boardService) GetBoard(ctx context.Context, token string) (*model.QueueBoard, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	return s.board(ctx, client)
+}
+
+func (s *boardService) board(ctx context.Context, client *entity.Client) (*model.QueueBoard, AppError) {
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	now := time.Now().In(model.ClientLocation(setting))
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	orders, err := s.orderRepo.GetOrdersUpdatedSince(ctx, client.Token, boardStatuses, startOfDay)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	board := &model.QueueBoard{
+		NowServing: []model.QueueBoardEntry{},
+		Ready:      []model.QueueBoardEntry{},
+	}
+	for _, order := range orders {
+		switch order.Status {
+		case model.OrderStatusProcessing:
+			board.NowServing = append(board.NowServing, model.NewQueueBoardEntry(order))
+		case model.OrderStatusSuccess:
+			if len(board.Ready) < model.BoardReadyLimit {
+				board.Ready = append(board.Ready, model.NewQueueBoardEntry(order))
+			}
+		}
+	}
+
+	return board, *NewSuccessError()
+}
+
+// IssueBoardToken returns a board token of the client owning the token. Display boards open the
+// WebSocket with it, the board token only gives access to the board and expires.
+func (s *boardService) IssueBoardToken(ctx context.Context, token string) (*model.BoardToken, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	expiresAt := time.Now().Add(s.tokenTTL).Truncate(time.Second)
+
+	return &model.BoardToken{
+		Token:     SignAccessToken(s.tokenSecret, TokenScopeBoard, client.ID, expiresAt),
+		ExpiresAt: expiresAt,
+	}, *NewSuccessError()
+}
+
+// Subscribe subscribes to the order events of the client owning the token and returns the
+// current board, which is sent first so a reconnecting board starts from a consistent state.
+func (s *boardService) Subscribe(ctx context.Context, token string) (*event.Subscription, *model.QueueBoard, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	return s.subscribe(ctx, client)
+}
+
+// SubscribeWithBoardToken is Subscribe for a display board that connects with a board token.
+func (s *boardService) SubscribeWithBoardToken(ctx context.Context, boardToken string) (*event.Subscription, *model.QueueBoard, AppError) {
+	clientID, err := ParseAccessToken(s.tokenSecret, TokenScopeBoard, boardToken, time.Now())
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	client, err := s.clientRepo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	return s.subscribe(ctx, client)
+}
+
+func (s *boardService) subscribe(ctx context.Context, client *entity.Client) (*event.Subscription, *model.QueueBoard, AppError) {
+	// Subscribe before reading the board so no change between the two is lost
+	subscription, _ := s.hub.Subscribe(client.ID, "")
+
+	board, appErr := s.board(ctx, client)
+	if appErr.Code != SuccessError {
+		subscription.Close()
+		return nil, nil, appErr
+	}
+
+	return subscription, board, *NewSuccessError()
+}
+
+// BoardMessageFromEvent converts an order event to the message pushed to display boards. Nil is
+// returned for events that do not change the board.
+func BoardMessageFromEvent(e event.Event) *model.BoardMessage {
+	if e.Order == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case event.OrderStatusChanged, event.OrderCancelled:
+		return model.NewBoardMessage(e.Order)
+	default:
+		return nil
+	}
+}
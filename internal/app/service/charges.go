@@ -0,0 +1,101 @@
+package service
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"math"
+)
+
+// OrderCharges is the price breakdown of an order.
+type OrderCharges struct {
+	Subtotal           float64
+	Discount           float64
+	ServiceCharge      float64
+	Tax                float64
+	RoundingAdjustment float64
+	GrandTotal         float64
+}
+
+// CalculateCharges computes the service charge, tax and grand total of an order from the sum of
+// its line totals and the order level discount using the tax rules of the client.
+// With inclusive tax the prices already contain the tax, so the tax is only extracted for
+// reporting and does not change the grand total. Amounts are rounded to the minor units of the
+// currency of the client.
+func CalculateCharges(setting *entity.ClientSetting, subtotal float64, discount float64) OrderCharges {
+	currency := settingCurrency(setting)
+	round := func(amount float64) float64 {
+		return model.RoundAmount(amount, currency)
+	}
+
+	charges := OrderCharges{
+		Subtotal: round(subtotal),
+		Discount: round(discount),
+	}
+
+	net := charges.Subtotal - charges.Discount
+	charges.ServiceCharge = round(net * setting.ServiceChargeRate / 100)
+
+	taxBase := net
+	if setting.TaxOnServiceCharge {
+		taxBase += charges.ServiceCharge
+	}
+
+	if setting.TaxInclusive {
+		charges.Tax = round(taxBase * setting.TaxRate / (100 + setting.TaxRate))
+		charges.GrandTotal = round(net + charges.ServiceCharge)
+	} else {
+		charges.Tax = round(taxBase * setting.TaxRate / 100)
+		charges.GrandTotal = round(net + charges.ServiceCharge + charges.Tax)
+	}
+
+	rounded := round(RoundTotal(charges.GrandTotal, setting.RoundingMode, setting.RoundingIncrement))
+	charges.RoundingAdjustment = round(rounded - charges.GrandTotal)
+	charges.GrandTotal = rounded
+
+	return charges
+}
+
+// OrderSetting returns the setting that prices an order in its currency. Orders keep the currency
+// they were created in, when the client changed its currency since, the amounts are rounded to the
+// minor units of the order currency and the rounding increment, which belongs to the new
+// currency, is not applied.
+func OrderSetting(setting *entity.ClientSetting, currency string) *entity.ClientSetting {
+	if currency == "" || currency == settingCurrency(setting) {
+		return setting
+	}
+
+	orderSetting := *setting
+	orderSetting.CurrencyCode = currency
+	orderSetting.RoundingMode = model.RoundingModeNone
+	orderSetting.RoundingIncrement = 0
+
+	return &orderSetting
+}
+
+// RoundTotal rounds a total to a multiple of increment, e.g. to the nearest 100 IDR when
+// there are no smaller coins. The total is returned unchanged when rounding is disabled.
+func RoundTotal(total float64, mode string, increment float64) float64 {
+	if increment <= 0 {
+		return total
+	}
+
+	steps := total / increment
+	switch mode {
+	case model.RoundingModeNearest:
+		steps = math.Round(steps)
+	case model.RoundingModeUp:
+		steps = math.Ceil(roundAmount(steps))
+	case model.RoundingModeDown:
+		steps = math.Floor(roundAmount(steps))
+	default:
+		return total
+	}
+
+	return roundAmount(steps * increment)
+}
+
+// roundAmount rounds an amount to two decimals, for amounts that are not in the currency of an
+// order such as the steps of RoundTotal.
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
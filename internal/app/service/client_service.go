@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+type ClientService interface {
+	GetSetting(context.Context, string) (*entity.ClientSetting, AppError)
+	UpdateSetting(context.Context, string, *model.ClientSettingRequest) (*entity.ClientSetting, AppError)
+	GetOpeningHours(context.Context, string) ([]*entity.ClientOpeningHour, AppError)
+	UpdateOpeningHours(context.Context, string, *model.OpeningHoursRequest) ([]*entity.ClientOpeningHour, AppError)
+	GetHolidays(context.Context, string) ([]*entity.ClientHoliday, AppError)
+	AddHoliday(context.Context, string, *model.HolidayRequest) (*entity.ClientHoliday, AppError)
+	DeleteHoliday(context.Context, string, uint) AppError
+	PauseOrders(context.Context, string, *model.PauseRequest) (*model.StoreState, AppError)
+	GetStoreState(context.Context, string) (*model.StoreState, AppError)
+}
+
+type clientService struct {
+	clientRepo repository.ClientRepository
+}
+
+func NewClientService(clientRepo repository.ClientRepository) ClientService {
+	return &clientService{
+		clientRepo: clientRepo,
+	}
+}
+
+func (s *clientService) GetSetting(ctx context.Context, token string) (*entity.ClientSetting, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return setting, *NewSuccessError()
+}
+
+func (s *clientService) UpdateSetting(ctx context.Context, token string, request *model.ClientSettingRequest) (*entity.ClientSetting, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	if request.CountryCode != nil && *request.CountryCode != "" && !model.IsPhoneCountry(*request.CountryCode) {
+		return nil, *NewInvalidRequestError("country_code")
+	}
+
+	if request.Timezone != nil && *request.Timezone != "" && !model.IsTimezone(*request.Timezone) {
+		return nil, *NewInvalidRequestError("timezone")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	model.ApplyClientSetting(setting, request)
+
+	setting, err = s.clientRepo.SaveClientSetting(ctx, setting)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return setting, *NewSuccessError()
+}
+
+func (s *clientService) GetOpeningHours(ctx context.Context, token string) ([]*entity.ClientOpeningHour, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	hours, err := s.clientRepo.GetOpeningHours(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return hours, *NewSuccessError()
+}
+
+// UpdateOpeningHours replaces the opening hours of the client. A day can have several opening
+// periods, days without a period are closed and a client without periods is always open.
+func (s *clientService) UpdateOpeningHours(ctx context.Context, token string, request *model.OpeningHoursRequest) ([]*entity.ClientOpeningHour, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	hours := make([]*entity.ClientOpeningHour, 0, len(request.Hours))
+	for i, hour := range request.Hours {
+		if !model.ValidOpeningHour(hour.OpenTime, hour.CloseTime) {
+			return nil, *NewInvalidRequestError(fmt.Sprintf("hours[%d] closes before it opens", i))
+		}
+
+		hours = append(hours, &entity.ClientOpeningHour{
+			Weekday:   hour.Weekday,
+			OpenTime:  hour.OpenTime,
+			CloseTime: hour.CloseTime,
+		})
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	hours, err = s.clientRepo.SaveOpeningHours(ctx, client.ID, hours)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return hours, *NewSuccessError()
+}
+
+// GetHolidays returns the holidays of the client from today on in the time zone of the client.
+func (s *clientService) GetHolidays(ctx context.Context, token string) ([]*entity.ClientHoliday, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	holidays, err := s.clientRepo.GetHolidays(ctx, client.ID, time.Now().In(model.ClientLocation(setting)))
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return holidays, *NewSuccessError()
+}
+
+// AddHoliday adds an exception to the weekly opening hours. Without open and close time the client
+// is closed for the whole date, several holidays on a date are several opening periods.
+func (s *clientService) AddHoliday(ctx context.Context, token string, request *model.HolidayRequest) (*entity.ClientHoliday, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	if (request.OpenTime != "" || request.CloseTime != "") && !model.ValidOpeningHour(request.OpenTime, request.CloseTime) {
+		return nil, *NewInvalidRequestError("close_time")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	holiday, err := s.clientRepo.AddHoliday(ctx, &entity.ClientHoliday{
+		ClientID:  client.ID,
+		Date:      request.Date,
+		OpenTime:  request.OpenTime,
+		CloseTime: request.CloseTime,
+		Note:      request.Note,
+	})
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return holiday, *NewSuccessError()
+}
+
+func (s *clientService) DeleteHoliday(ctx context.Context, token string, holidayID uint) AppError {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return *NewInvalidTokenError()
+	}
+
+	err = s.clientRepo.DeleteHoliday(ctx, client.ID, holidayID)
+	if errors.Is(err, repository.ErrHolidayNotFound) {
+		return *NewHolidayNotFoundError()
+	}
+	if err != nil {
+		return *NewUpdateQueryDBError()
+	}
+
+	return *NewSuccessError()
+}
+
+// PauseOrders stops or resumes accepting new orders, for example when the kitchen is overloaded.
+// A pause with minutes ends by itself, a pause without minutes lasts until orders are resumed.
+func (s *clientService) PauseOrders(ctx context.Context, token string, request *model.PauseRequest) (*model.StoreState, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	now := time.Now()
+	setting.OrdersPaused = request.Paused
+	setting.PausedUntil = nil
+	if request.Paused && request.Minutes > 0 {
+		pausedUntil := now.Add(time.Duration(request.Minutes) * time.Minute)
+		setting.PausedUntil = &pausedUntil
+	}
+
+	setting, err = s.clientRepo.SaveClientSetting(ctx, setting)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return s.storeState(ctx, setting, now)
+}
+
+// GetStoreState returns whether the client accepts orders now and when that changes.
+func (s *clientService) GetStoreState(ctx context.Context, token string) (*model.StoreState, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return s.storeState(ctx, setting, time.Now())
+}
+
+// storeState returns the state of the store of the client at now. Opening hours are wall-clock
+// times in the time zone of the client.
+func (s *clientService) storeState(ctx context.Context, setting *entity.ClientSetting, now time.Time) (*model.StoreState, AppError) {
+	now = now.In(model.ClientLocation(setting))
+	hours, holidays, err := storeSchedule(ctx, s.clientRepo, setting.ClientID, now)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return model.NewStoreState(setting, hours, holidays, now), *NewSuccessError()
+}
+
+// storeSchedule returns the weekly opening hours of a client and its holidays from the date of now.
+func storeSchedule(ctx context.Context, clientRepo repository.ClientRepository, clientID uint, now time.Time) ([]*entity.ClientOpeningHour, []*entity.ClientHoliday, error) {
+	hours, err := clientRepo.GetOpeningHours(ctx, clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holidays, err := clientRepo.GetHolidays(ctx, clientID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hours, holidays, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	exRepo "maqhaa/order_service/external/repository"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"strings"
+	"time"
+)
+
+type CustomerService interface {
+	GetCustomerByPhone(context.Context, string, string) (*model.CustomerProfile, AppError)
+	GetCustomer(context.Context, string, uint) (*model.CustomerProfile, AppError)
+	GetCustomerOrders(context.Context, string, uint, int, int) ([]*entity.Order, AppError)
+	GetCustomerPoints(context.Context, string, uint) (*model.CustomerPoints, AppError)
+}
+
+type customerService struct {
+	customerRepo repository.CustomerRepository
+	loyaltyRepo  repository.LoyaltyRepository
+	clientRepo   repository.ClientRepository
+	productRepo  exRepo.ProductRepository
+}
+
+func NewCustomerService(customerRepo repository.CustomerRepository, loyaltyRepo repository.LoyaltyRepository, clientRepo repository.ClientRepository, productRepo exRepo.ProductRepository) CustomerService {
+	return &customerService{
+		customerRepo: customerRepo,
+		loyaltyRepo:  loyaltyRepo,
+		clientRepo:   clientRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// GetCustomerByPhone looks up a customer by phone number in any format, local numbers are numbers
+// of the country of the client.
+func (s *customerService) GetCustomerByPhone(ctx context.Context, token string, phoneNumber string) (*model.CustomerProfile, AppError) {
+	if strings.TrimSpace(phoneNumber) == "" {
+		return nil, *NewInvalidRequestError("phone")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	phoneNumber, err = model.ParsePhoneNumber(phoneNumber, settingCountry(setting))
+	if err != nil {
+		return nil, *NewInvalidRequestError("phone")
+	}
+
+	customer, err := s.customerRepo.GetCustomerByPhone(ctx, client.ID, phoneNumber)
+	if err != nil {
+		return nil, *NewCustomerNotFoundError()
+	}
+
+	return s.profile(ctx, token, customer)
+}
+
+func (s *customerService) GetCustomer(ctx context.Context, token string, customerID uint) (*model.CustomerProfile, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, client.ID, customerID)
+	if err != nil {
+		return nil, *NewCustomerNotFoundError()
+	}
+
+	return s.profile(ctx, token, customer)
+}
+
+// GetCustomerOrders lists the past orders of a customer, latest first. A limit of zero uses the
+// default page size.
+func (s *customerService) GetCustomerOrders(ctx context.Context, token string, customerID uint, limit int, offset int) ([]*entity.Order, AppError) {
+	if limit == 0 {
+		limit = model.CustomerOrderDefaultLimit
+	}
+	if limit < 0 || limit > model.CustomerOrderMaxLimit {
+		return nil, *NewInvalidRequestError("limit")
+	}
+	if offset < 0 {
+		return nil, *NewInvalidRequestError("offset")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if _, err := s.customerRepo.GetCustomerByID(ctx, client.ID, customerID); err != nil {
+		return nil, *NewCustomerNotFoundError()
+	}
+
+	orders, err := s.customerRepo.GetCustomerOrders(ctx, customerID, limit, offset)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return orders, *NewSuccessError()
+}
+
+// GetCustomerPoints returns the points balance of a customer with the latest entries of their
+// ledger. Expired credits are listed but not part of the balance.
+func (s *customerService) GetCustomerPoints(ctx context.Context, token string, customerID uint) (*model.CustomerPoints, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if _, err := s.customerRepo.GetCustomerByID(ctx, client.ID, customerID); err != nil {
+		return nil, *NewCustomerNotFoundError()
+	}
+
+	balance, err := s.loyaltyRepo.GetBalance(ctx, customerID, time.Now())
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	ledger, err := s.loyaltyRepo.GetLedger(ctx, customerID, model.LoyaltyLedgerLimit)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return &model.CustomerPoints{Balance: balance, Ledger: ledger}, *NewSuccessError()
+}
+
+// profile adds the order statistics, the points balance and the favourite products to a customer. Product names are
+// read from the product service in one lookup, a product that can not be read is listed without its name.
+func (s *customerService) profile(ctx context.Context, token string, customer *entity.Customer) (*model.CustomerProfile, AppError) {
+	stats, err := s.customerRepo.GetCustomerStats(ctx, customer.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	points, err := s.loyaltyRepo.GetBalance(ctx, customer.ID, time.Now())
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	favourites, err := s.customerRepo.GetFavouriteProducts(ctx, customer.ID, model.CustomerFavouriteLimit)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	productIDs := make([]uint, 0, len(favourites))
+	for _, favourite := range favourites {
+		productIDs = append(productIDs, favourite.ProductID)
+	}
+	products, _ := s.productRepo.GetProductsByIDs(ctx, productIDs, token)
+	for i := range favourites {
+		if product, ok := products[favourites[i].ProductID]; ok {
+			favourites[i].ProductName = product.Name
+		}
+	}
+
+	if favourites == nil {
+		favourites = []model.FavouriteProduct{}
+	}
+
+	return &model.CustomerProfile{
+		Customer:          customer,
+		Stats:             *stats,
+		PointsBalance:     points,
+		FavouriteProducts: favourites,
+	}, *NewSuccessError()
+}
@@ -1,6 +1,13 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
 
 const (
 	SuccessError   = 00
@@ -30,9 +37,51 @@ const (
 	InvalidProductPriceMessage = "Invalid Product Price"
 	InvalidTotal               = 206
 	InvalidTotalMessage        = "Invalid Total"
+	PromoNotFound              = 207
+	PromoNotFoundMessage       = "Promo Code Not Found"
+	PromoNotApplicable         = 208
+	PromoNotApplicableMessage  = "Promo Not Applicable"
+	PromoUsageLimit            = 209
+	PromoUsageLimitMessage     = "Promo Usage Limit Reached"
+	CurrencyMismatch           = 210
+	CurrencyMismatchMessage    = "Currency Mismatch"
 
-	OrderNotFound        = 221
-	OrderNotFoundMessage = "Order Not Found"
+	OrderNotFound                  = 221
+	OrderNotFoundMessage           = "Order Not Found"
+	OrderDetailNotFound            = 222
+	OrderDetailNotFoundMessage     = "Order Detail Not Found"
+	InvalidOrderStatus             = 223
+	InvalidOrderStatusMessage      = "Invalid Order Status"
+	KitchenTicketNotFound          = 224
+	KitchenTicketNotFoundMessage   = "Kitchen Ticket Not Found"
+	WebhookDeliveryNotFound        = 225
+	WebhookDeliveryNotFoundMessage = "Webhook Delivery Not Found"
+	CustomerNotFound               = 226
+	CustomerNotFoundMessage        = "Customer Not Found"
+	InsufficientPoints             = 227
+	InsufficientPointsMessage      = "Insufficient Loyalty Points"
+	InvalidScheduledTime           = 228
+	InvalidScheduledTimeMessage    = "Invalid Scheduled Time"
+	StoreClosed                    = 229
+	StoreClosedMessage             = "Store Closed"
+	HolidayNotFound                = 230
+	HolidayNotFoundMessage         = "Holiday Not Found"
+	OutOfStock                     = 231
+	OutOfStockMessage              = "Out of Stock"
+	StockNotFound                  = 232
+	StockNotFoundMessage           = "Stock Not Found"
+	IngredientNotFound             = 233
+	IngredientNotFoundMessage      = "Ingredient Not Found"
+	ShiftAlreadyOpen               = 234
+	ShiftAlreadyOpenMessage        = "Shift Already Open"
+	ShiftNotFound                  = 235
+	ShiftNotFoundMessage           = "Shift Not Found"
+	OrderLocked                    = 236
+	OrderLockedMessage             = "Order Locked by Closed Business Day"
+	OrderNotPaid                   = 237
+	OrderNotPaidMessage            = "Order Not Paid"
+	WebhookNotFound                = 238
+	WebhookNotFoundMessage         = "Webhook Not Found"
 
 	//300 to 399: Database-related errors
 	QueryError              = 301
@@ -46,10 +95,19 @@ const (
 	DateCategoryNotFoundMessage = "Data Not Found"
 )
 
-// AppError represents an application-specific error.
+// AppError represents an application-specific error. Status is the HTTP status of the error and
+// Errors lists the invalid fields of a request that failed validation.
 type AppError struct {
 	Code    int
 	Message string
+	Status  int
+	Errors  []model.FieldError
+
+	// detail is the argument of a formatted message, validation and shortages the errors Errors
+	// is built from, all are kept to localize the error.
+	detail     string
+	validation validator.ValidationErrors
+	shortages  []model.StockShortage
 }
 
 // NewAppError creates a new instance of AppError.
@@ -57,14 +115,57 @@ func NewAppError(code int, message string) *AppError {
 	return &AppError{
 		Code:    code,
 		Message: message,
+		Status:  HTTPStatus(code),
 	}
 }
 
-func NewOrderNotFoundError() *AppError {
-	return &AppError{
-		Code:    OrderNotFound,
-		Message: OrderNotFoundMessage,
+// HTTPStatus returns the HTTP status of an AppError code.
+func HTTPStatus(code int) int {
+	switch code {
+	case SuccessError:
+		return http.StatusOK
+	case InvalidUsername, InvalidPassword, InvalidToken:
+		return http.StatusUnauthorized
+	case ProductNotFound, PromoNotFound, OrderNotFound, OrderDetailNotFound, KitchenTicketNotFound,
+		WebhookDeliveryNotFound, CustomerNotFound, HolidayNotFound, StockNotFound, IngredientNotFound, ShiftNotFound,
+		WebhookNotFound, DateCategoryNotFound:
+		return http.StatusNotFound
+	case InvalidOrderStatus, PromoUsageLimit, InsufficientPoints, StoreClosed, OutOfStock, ShiftAlreadyOpen, OrderLocked,
+		OrderNotPaid:
+		return http.StatusConflict
 	}
+
+	// 200 - 299 are input validation errors
+	if code >= 200 && code < 300 {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Localize returns a copy of the error with its message and field errors in the language. Codes
+// missing from the catalog keep their message.
+func (e AppError) Localize(lang string) AppError {
+	localized := e
+
+	message := i18n.ErrorMessage(lang, e.Code, e.Message)
+	if e.detail != "" {
+		message = fmt.Sprintf(message, e.detail)
+	}
+	localized.Message = message
+
+	if e.validation != nil {
+		localized.Errors = fieldErrors(e.validation, lang)
+	}
+	if e.shortages != nil {
+		localized.Errors = stockErrors(e.shortages, lang)
+	}
+
+	return localized
+}
+
+func NewOrderNotFoundError() *AppError {
+	return NewAppError(OrderNotFound, OrderNotFoundMessage)
 }
 
 func NewSuccessError() *AppError {
@@ -76,7 +177,9 @@ func NewInvalidFormatError() *AppError {
 }
 
 func NewInvalidRequestError(s string) *AppError {
-	return NewAppError(InvalidRequestError, fmt.Sprintf(InvalidRequestMessage, s))
+	appErr := NewAppError(InvalidRequestError, fmt.Sprintf(InvalidRequestMessage, s))
+	appErr.detail = s
+	return appErr
 }
 
 func NewInvalidPasswordError() *AppError {
@@ -117,3 +220,106 @@ func NewInvalidProductPriceError() *AppError {
 func NewInvalidTotalError() *AppError {
 	return NewAppError(InvalidTotal, InvalidTotalMessage)
 }
+
+func NewPromoNotFoundError() *AppError {
+	return NewAppError(PromoNotFound, PromoNotFoundMessage)
+}
+
+func NewPromoNotApplicableError() *AppError {
+	return NewAppError(PromoNotApplicable, PromoNotApplicableMessage)
+}
+
+func NewPromoUsageLimitError() *AppError {
+	return NewAppError(PromoUsageLimit, PromoUsageLimitMessage)
+}
+
+func NewCurrencyMismatchError() *AppError {
+	return NewAppError(CurrencyMismatch, CurrencyMismatchMessage)
+}
+
+func NewOrderDetailNotFoundError() *AppError {
+	return NewAppError(OrderDetailNotFound, OrderDetailNotFoundMessage)
+}
+
+func NewInvalidOrderStatusError() *AppError {
+	return NewAppError(InvalidOrderStatus, InvalidOrderStatusMessage)
+}
+
+func NewKitchenTicketNotFoundError() *AppError {
+	return NewAppError(KitchenTicketNotFound, KitchenTicketNotFoundMessage)
+}
+
+func NewWebhookDeliveryNotFoundError() *AppError {
+	return NewAppError(WebhookDeliveryNotFound, WebhookDeliveryNotFoundMessage)
+}
+
+func NewCustomerNotFoundError() *AppError {
+	return NewAppError(CustomerNotFound, CustomerNotFoundMessage)
+}
+
+func NewInsufficientPointsError() *AppError {
+	return NewAppError(InsufficientPoints, InsufficientPointsMessage)
+}
+
+func NewInvalidScheduledTimeError() *AppError {
+	return NewAppError(InvalidScheduledTime, InvalidScheduledTimeMessage)
+}
+
+func NewStoreClosedError() *AppError {
+	return NewAppError(StoreClosed, StoreClosedMessage)
+}
+
+func NewHolidayNotFoundError() *AppError {
+	return NewAppError(HolidayNotFound, HolidayNotFoundMessage)
+}
+
+// NewOutOfStockError creates the error of an order with lines asking for more than the available
+// stock, with one field error per line.
+func NewOutOfStockError(shortages []model.StockShortage) *AppError {
+	appErr := NewAppError(OutOfStock, OutOfStockMessage)
+	appErr.Errors = stockErrors(shortages, i18n.DefaultLanguage)
+	appErr.shortages = shortages
+	return appErr
+}
+
+func NewStockNotFoundError() *AppError {
+	return NewAppError(StockNotFound, StockNotFoundMessage)
+}
+
+func NewIngredientNotFoundError() *AppError {
+	return NewAppError(IngredientNotFound, IngredientNotFoundMessage)
+}
+
+func NewShiftAlreadyOpenError() *AppError {
+	return NewAppError(ShiftAlreadyOpen, ShiftAlreadyOpenMessage)
+}
+
+func NewShiftNotFoundError() *AppError {
+	return NewAppError(ShiftNotFound, ShiftNotFoundMessage)
+}
+
+func NewOrderLockedError() *AppError {
+	return NewAppError(OrderLocked, OrderLockedMessage)
+}
+
+func NewOrderNotPaidError() *AppError {
+	return NewAppError(OrderNotPaid, OrderNotPaidMessage)
+}
+
+func NewWebhookNotFoundError() *AppError {
+	return NewAppError(WebhookNotFound, WebhookNotFoundMessage)
+}
+
+// stockErrors returns the field errors of the order lines that are out of stock in the language.
+func stockErrors(shortages []model.StockShortage, lang string) []model.FieldError {
+	errors := make([]model.FieldError, 0, len(shortages))
+	for _, shortage := range shortages {
+		errors = append(errors, model.FieldError{
+			Field:   fmt.Sprintf("Orders[%d].quantity", shortage.Line),
+			Rule:    "stock",
+			Message: i18n.StockShortageMessage(lang, shortage.Available),
+		})
+	}
+
+	return errors
+}
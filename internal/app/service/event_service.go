@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+type EventService interface {
+	IssueStreamToken(context.Context, string) (*model.StreamToken, AppError)
+	Subscribe(context.Context, string, string) (*event.Subscription, []event.Event, AppError)
+	SubscribeWithStreamToken(context.Context, string, string) (*event.Subscription, []event.Event, AppError)
+}
+
+type eventService struct {
+	hub         *event.Hub
+	clientRepo  repository.ClientRepository
+	tokenSecret []byte
+	tokenTTL    time.Duration
+}
+
+// NewEventService creates the event service. Stream tokens are signed with tokenSecret, a random
+// secret is used when it is empty.
+func NewEventService(hub *event.Hub, clientRepo repository.ClientRepository, tokenSecret string, tokenTTL time.Duration) EventService {
+	if tokenTTL <= 0 {
+		tokenTTL = model.DefaultStreamTokenTTL
+	}
+
+	return &eventService{
+		hub:         hub,
+		clientRepo:  clientRepo,
+		tokenSecret: accessTokenSecret(tokenSecret),
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// IssueStreamToken returns a stream token of the client owning the token. Browsers open the event
+// stream with it, the stream token only gives access to the order events and expires shortly.
+func (s *eventService) IssueStreamToken(ctx context.Context, token string) (*model.StreamToken, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	expiresAt := time.Now().Add(s.tokenTTL).Truncate(time.Second)
+
+	return &model.StreamToken{
+		Token:     SignAccessToken(s.tokenSecret, TokenScopeEvents, client.ID, expiresAt),
+		ExpiresAt: expiresAt,
+	}, *NewSuccessError()
+}
+
+// Subscribe subscribes to the order events of the client owning the token. The events published
+// after lastEventID that are still in the history are returned for replay.
+func (s *eventService) Subscribe(ctx context.Context, token string, lastEventID string) (*event.Subscription, []event.Event, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	subscription, replay := s.hub.Subscribe(client.ID, lastEventID)
+
+	return subscription, replay, *NewSuccessError()
+}
+
+// SubscribeWithStreamToken is Subscribe for a browser that connects with a stream token.
+func (s *eventService) SubscribeWithStreamToken(ctx context.Context, streamToken string, lastEventID string) (*event.Subscription, []event.Event, AppError) {
+	clientID, err := ParseAccessToken(s.tokenSecret, TokenScopeEvents, streamToken, time.Now())
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	client, err := s.clientRepo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return nil, nil, *NewInvalidTokenError()
+	}
+
+	subscription, replay := s.hub.Subscribe(client.ID, lastEventID)
+
+	return subscription, replay, *NewSuccessError()
+}
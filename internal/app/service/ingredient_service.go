@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	exRepo "maqhaa/order_service/external/repository"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+type IngredientService interface {
+	GetIngredients(context.Context, string) ([]*entity.Ingredient, AppError)
+	AddIngredient(context.Context, string, *model.IngredientRequest) (*entity.Ingredient, AppError)
+	GetRecipe(context.Context, string, uint) ([]*entity.RecipeItem, AppError)
+	UpdateRecipe(context.Context, string, uint, *model.RecipeRequest) ([]*entity.RecipeItem, AppError)
+	GetConsumptionReport(context.Context, string, string, string) (*model.ConsumptionReport, AppError)
+}
+
+type ingredientService struct {
+	ingredientRepo repository.IngredientRepository
+	clientRepo     repository.ClientRepository
+	productRepo    exRepo.ProductRepository
+}
+
+func NewIngredientService(ingredientRepo repository.IngredientRepository, clientRepo repository.ClientRepository, productRepo exRepo.ProductRepository) IngredientService {
+	return &ingredientService{
+		ingredientRepo: ingredientRepo,
+		clientRepo:     clientRepo,
+		productRepo:    productRepo,
+	}
+}
+
+func (s *ingredientService) GetIngredients(ctx context.Context, token string) ([]*entity.Ingredient, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	ingredients, err := s.ingredientRepo.GetIngredients(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return ingredients, *NewSuccessError()
+}
+
+func (s *ingredientService) AddIngredient(ctx context.Context, token string, request *model.IngredientRequest) (*entity.Ingredient, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	ingredient, err := s.ingredientRepo.AddIngredient(ctx, &entity.Ingredient{
+		ClientID: client.ID,
+		Name:     request.Name,
+		Unit:     request.Unit,
+	})
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return ingredient, *NewSuccessError()
+}
+
+func (s *ingredientService) GetRecipe(ctx context.Context, token string, productID uint) ([]*entity.RecipeItem, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	items, err := s.ingredientRepo.GetRecipe(ctx, client.ID, productID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return items, *NewSuccessError()
+}
+
+// UpdateRecipe replaces the ingredients used for one unit of a product. Every ingredient must be
+// an ingredient of the client and may appear once.
+func (s *ingredientService) UpdateRecipe(ctx context.Context, token string, productID uint, request *model.RecipeRequest) ([]*entity.RecipeItem, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if _, err := s.productRepo.GetProductByID(ctx, productID, token); err != nil {
+		return nil, *NewProductNotFoundError()
+	}
+
+	ingredients, err := s.ingredientRepo.GetIngredients(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	known := make(map[uint]bool, len(ingredients))
+	for _, ingredient := range ingredients {
+		known[ingredient.ID] = true
+	}
+
+	items := make([]*entity.RecipeItem, 0, len(request.Items))
+	used := make(map[uint]bool, len(request.Items))
+	for i, item := range request.Items {
+		if !known[item.IngredientID] {
+			return nil, *NewIngredientNotFoundError()
+		}
+		if used[item.IngredientID] {
+			return nil, *NewInvalidRequestError(fmt.Sprintf("items[%d] repeats an ingredient", i))
+		}
+		used[item.IngredientID] = true
+
+		items = append(items, &entity.RecipeItem{
+			IngredientID: item.IngredientID,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	items, err = s.ingredientRepo.SaveRecipe(ctx, client.ID, productID, items)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return items, *NewSuccessError()
+}
+
+// GetConsumptionReport returns the ingredients used by the orders completed on the days from and
+// to, both YYYY-MM-DD in the time zone of the client and today when empty.
+func (s *ingredientService) GetConsumptionReport(ctx context.Context, token string, from string, to string) (*model.ConsumptionReport, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	loc := model.ClientLocation(setting)
+	dateRange, err := model.ParseDateRange(from, to, time.Now().In(loc))
+	if err != nil {
+		return nil, *NewInvalidRequestError("date range")
+	}
+
+	usage, err := s.ingredientRepo.GetConsumption(ctx, client.ID, dateRange.From, dateRange.To, loc)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return model.NewConsumptionReport(dateRange, usage), *NewSuccessError()
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+)
+
+// kitchenStatuses are the order statuses shown on the kitchen display, in display order. Orders
+// are prepared once they are paid, so unpaid orders are not shown.
+var kitchenStatuses = []int{model.OrderStatusPaid, model.OrderStatusProcessing}
+
+type KitchenService interface {
+	GetQueue(context.Context, string) ([]model.KitchenQueueGroup, AppError)
+	StartOrderDetail(context.Context, string, uint) (*entity.Order, AppError)
+	ReadyOrderDetail(context.Context, string, uint) (*entity.Order, AppError)
+	AddStation(context.Context, string, *model.KitchenStationRequest) (*entity.KitchenStation, AppError)
+	GetStations(context.Context, string) ([]*entity.KitchenStation, AppError)
+	GetStationTickets(context.Context, string, uint) ([]*entity.KitchenTicket, AppError)
+	BumpTicket(context.Context, string, uint) (*entity.KitchenTicket, AppError)
+}
+
+type kitchenService struct {
+	orderRepo   repository.OrderRepository
+	kitchenRepo repository.KitchenRepository
+	clientRepo  repository.ClientRepository
+	publisher   event.Publisher
+}
+
+func NewKitchenService(orderRepo repository.OrderRepository, kitchenRepo repository.KitchenRepository, clientRepo repository.ClientRepository, publisher event.Publisher) KitchenService {
+	return &kitchenService{
+		orderRepo:   orderRepo,
+		kitchenRepo: kitchenRepo,
+		clientRepo:  clientRepo,
+		publisher:   publisher,
+	}
+}
+
+func (s *kitchenService) GetQueue(ctx context.Context, token string) ([]model.KitchenQueueGroup, AppError) {
+	orders, err := s.orderRepo.GetOrdersByStatus(ctx, token, kitchenStatuses)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return KitchenQueue(orders), *NewSuccessError()
+}
+
+// KitchenQueue groups the orders the kitchen can prepare by status. Orders that are not paid or
+// not released yet are left out.
+func KitchenQueue(orders []*entity.Order) []model.KitchenQueueGroup {
+	queue := make([]model.KitchenQueueGroup, 0, len(kitchenStatuses))
+	for _, status := range kitchenStatuses {
+		group := model.KitchenQueueGroup{
+			Status:     status,
+			StatusText: model.OrderStatusText(status),
+			Orders:     []*entity.Order{},
+		}
+		for _, order := range orders {
+			// Scheduled orders wait until they are released to the kitchen
+			if order.Status == status && order.ReleasedAt != nil {
+				group.Orders = append(group.Orders, order)
+			}
+		}
+		queue = append(queue, group)
+	}
+
+	return queue
+}
+
+func (s *kitchenService) StartOrderDetail(ctx context.Context, token string, detailID uint) (*entity.Order, AppError) {
+	return s.updatePrepStatus(ctx, token, detailID, model.PrepStatusStarted)
+}
+
+func (s *kitchenService) ReadyOrderDetail(ctx context.Context, token string, detailID uint) (*entity.Order, AppError) {
+	return s.updatePrepStatus(ctx, token, detailID, model.PrepStatusReady)
+}
+
+// updatePrepStatus moves an order line forward. Lines can not go back to an earlier state, lines
+// of finished orders can not be changed and orders are only prepared once they are paid.
+func (s *kitchenService) updatePrepStatus(ctx context.Context, token string, detailID uint, prepStatus int) (*entity.Order, AppError) {
+	detail, err := s.orderRepo.GetOrderDetailByID(ctx, detailID, token)
+	if err != nil {
+		return nil, *NewOrderDetailNotFoundError()
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, detail.OrderID, token)
+	if err != nil {
+		return nil, *NewOrderNotFoundError()
+	}
+
+	if order.Status >= model.OrderStatusSuccess || order.ReleasedAt == nil || detail.PrepStatus >= prepStatus {
+		return nil, *NewInvalidOrderStatusError()
+	}
+
+	if order.Status < model.OrderStatusPaid {
+		return nil, *NewOrderNotPaidError()
+	}
+
+	previousStatus := order.Status
+	order, err = s.orderRepo.UpdatePrepStatus(ctx, detail.ID, prepStatus)
+	if appErr := preparationError(err); appErr != nil {
+		return nil, *appErr
+	}
+
+	if order.Status != previousStatus {
+		s.publisher.Publish(event.Event{Type: event.OrderStatusChanged, ClientID: order.ClientID, OrderID: order.ID, Order: order})
+	}
+
+	return order, *NewSuccessError()
+}
+
+func (s *kitchenService) AddStation(ctx context.Context, token string, request *model.KitchenStationRequest) (*entity.KitchenStation, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	// A category can only be prepared at one station
+	stations, err := s.kitchenRepo.GetStations(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+	for _, station := range stations {
+		for _, category := range station.Categories {
+			for _, categoryID := range request.CategoryIDs {
+				if category.CategoryID == categoryID {
+					return nil, *NewInvalidRequestError(fmt.Sprintf("category %d already routed to station %s", categoryID, station.Name))
+				}
+			}
+		}
+	}
+
+	station := &entity.KitchenStation{
+		ClientID: client.ID,
+		Name:     request.Name,
+		IsActive: true,
+	}
+	for _, categoryID := range request.CategoryIDs {
+		station.Categories = append(station.Categories, entity.KitchenStationCategory{CategoryID: categoryID})
+	}
+
+	station, err = s.kitchenRepo.AddStation(ctx, station)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return station, *NewSuccessError()
+}
+
+func (s *kitchenService) GetStations(ctx context.Context, token string) ([]*entity.KitchenStation, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	stations, err := s.kitchenRepo.GetStations(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return stations, *NewSuccessError()
+}
+
+func (s *kitchenService) GetStationTickets(ctx context.Context, token string, stationID uint) ([]*entity.KitchenTicket, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	tickets, err := s.kitchenRepo.GetStationTickets(ctx, client.ID, stationID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return tickets, *NewSuccessError()
+}
+
+func (s *kitchenService) BumpTicket(ctx context.Context, token string, ticketID uint) (*entity.KitchenTicket, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	ticket, err := s.kitchenRepo.GetTicketByID(ctx, client.ID, ticketID)
+	if err != nil {
+		return nil, *NewKitchenTicketNotFoundError()
+	}
+
+	if ticket.Status == model.PrepStatusReady || ticket.Order == nil || ticket.Order.ReleasedAt == nil || ticket.Order.Status >= model.OrderStatusSuccess {
+		return nil, *NewInvalidOrderStatusError()
+	}
+
+	if ticket.Order.Status < model.OrderStatusPaid {
+		return nil, *NewOrderNotPaidError()
+	}
+
+	previousStatus := ticket.Order.Status
+	ticket, err = s.kitchenRepo.BumpTicket(ctx, ticket.ID)
+	if appErr := preparationError(err); appErr != nil {
+		return nil, *appErr
+	}
+
+	if ticket.Order.Status != previousStatus {
+		s.publisher.Publish(event.Event{Type: event.OrderStatusChanged, ClientID: ticket.Order.ClientID, OrderID: ticket.OrderID, Order: ticket.Order})
+	}
+
+	return ticket, *NewSuccessError()
+}
+
+// preparationError maps the error of preparing an order line, the order may have changed since it
+// was read.
+func preparationError(err error) *AppError {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrOrderNotPaid):
+		return NewOrderNotPaidError()
+	case errors.Is(err, repository.ErrInvalidOrderStatus):
+		return NewInvalidOrderStatusError()
+	default:
+		return NewUpdateQueryDBError()
+	}
+}
+
+// RouteOrderDetails assigns every order line to the station that prepares its product category
+// and returns one kitchen ticket per station in order of first appearance. Lines whose category
+// is not mapped to a station are routed to station zero.
+func RouteOrderDetails(stations []*entity.KitchenStation, details []entity.OrderDetail) []entity.KitchenTicket {
+	categoryStations := make(map[uint]uint)
+	for _, station := range stations {
+		for _, category := range station.Categories {
+			categoryStations[category.CategoryID] = station.ID
+		}
+	}
+
+	var tickets []entity.KitchenTicket
+	routed := make(map[uint]bool)
+	for i := range details {
+		stationID := categoryStations[details[i].CategoryID]
+		details[i].StationID = stationID
+
+		if !routed[stationID] {
+			routed[stationID] = true
+			tickets = append(tickets, entity.KitchenTicket{StationID: stationID, Status: model.PrepStatusPending})
+		}
+	}
+
+	return tickets
+}
@@ -2,40 +2,81 @@ package service
 
 import (
 	"context"
+	"errors"
 	exEntity "maqhaa/order_service/external/entity"
 	exRepo "maqhaa/order_service/external/repository"
 	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
 	"maqhaa/order_service/internal/app/model"
 	"maqhaa/order_service/internal/app/repository"
+	"strings"
 	"sync"
-
-	"github.com/go-playground/validator/v10"
+	"time"
 )
 
 type OrderService interface {
 	AddOrder(context.Context, string, *model.OrderRequest) (*entity.Order, AppError)
 	EditOrder(context.Context, string, *model.OrderRequest) (*entity.Order, AppError)
 	GetOrder(context.Context, string, int) (*entity.Order, AppError)
+	ListOrders(context.Context, string, []int, string) ([]*entity.Order, AppError)
+	UpdateStatus(context.Context, string, int, *model.UpdateStatusRequest) (*entity.Order, AppError)
+	CancelOrder(context.Context, string, int) (*entity.Order, AppError)
+	GetScheduledOrders(context.Context, string) ([]*entity.Order, AppError)
 	// Add more methods as needed
 }
 
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	productRepo exRepo.ProductRepository
+	orderRepo     repository.OrderRepository
+	productRepo   exRepo.ProductRepository
+	promotionRepo repository.PromotionRepository
+	clientRepo    repository.ClientRepository
+	kitchenRepo   repository.KitchenRepository
+	publisher     event.Publisher
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, productRepo exRepo.ProductRepository) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, productRepo exRepo.ProductRepository, promotionRepo repository.PromotionRepository, clientRepo repository.ClientRepository, kitchenRepo repository.KitchenRepository, publisher event.Publisher) OrderService {
 	return &orderService{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:     orderRepo,
+		productRepo:   productRepo,
+		promotionRepo: promotionRepo,
+		clientRepo:    clientRepo,
+		kitchenRepo:   kitchenRepo,
+		publisher:     publisher,
 	}
 }
 
+// AddOrder creates an order for the client of the token. The client ID of the request must be the
+// one of the token, so a token can not use the settings, stock or loyalty points of another
+// client.
 func (s *orderService) AddOrder(ctx context.Context, token string, request *model.OrderRequest) (*entity.Order, AppError) {
 
-	validate := validator.New()
-	if err := validate.Struct(request); err != nil {
-		return nil, *NewInvalidRequestError(err.Error())
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if request.ClientID != client.ID {
+		return nil, *NewInvalidRequestError("client_id")
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+	currency := settingCurrency(setting)
+
+	phoneNumber, appErr := settingPhoneNumber(setting, request.PhoneNumber)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	now := time.Now()
+	if appErr := s.checkOrderAcceptance(ctx, setting, request.ScheduledAt, now); appErr != nil {
+		return nil, *appErr
 	}
 
 	var orderDetails []entity.OrderDetail
@@ -56,10 +97,15 @@ func (s *orderService) AddOrder(ctx context.Context, token string, request *mode
 
 	wg.Wait()
 	close(resultChan)
+	products := make(map[uint]*exEntity.Product)
 	for result := range resultChan {
 		if result == nil {
 			return nil, *NewProductNotFoundError()
 		}
+		products[result.ID] = result
+		if result.Currency != "" && result.Currency != currency {
+			return nil, *NewCurrencyMismatchError()
+		}
 		for _, reqDetail := range request.Orders {
 			if result.ID != reqDetail.ProductID {
 				continue
@@ -75,44 +121,84 @@ func (s *orderService) AddOrder(ctx context.Context, token string, request *mode
 
 		// Convert the request detail to order detail entity
 		orderDetail := entity.OrderDetail{
-			ProductID: reqDetail.ProductID,
-			Price:     reqDetail.Price,
-			Quantity:  reqDetail.Quantity,
-			Discount:  reqDetail.Discount,
-			Total:     reqDetail.Total,
+			ProductID:  reqDetail.ProductID,
+			CategoryID: products[reqDetail.ProductID].CategoryID,
+			Price:      reqDetail.Price,
+			Quantity:   reqDetail.Quantity,
+			Discount:   reqDetail.Discount,
+			Total:      reqDetail.Total,
 		}
 
 		orderDetails = append(orderDetails, orderDetail)
 	}
 
-	if totalPrice != request.Total {
+	redemptions, promoDiscount, appErr := s.applyPromotion(ctx, client.ID, setting, phoneNumber, request, orderDetails, 0)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	charges := CalculateCharges(setting, totalPrice, promoDiscount)
+	if charges.GrandTotal != request.Total {
 		return nil, *NewInvalidTotalError()
 	}
 
+	pointsAmount, appErr := redeemPointsAmount(setting, phoneNumber, request, charges.GrandTotal)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	stations, err := s.kitchenRepo.GetStations(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
 	// Convert the request to the Order entity
 	order := &entity.Order{
-		ClientID:     uint(request.ClientID),
-		CustomerName: request.CustomerName,
-		PhoneNumber:  request.PhoneNumber,
-		Total:        totalPrice,
-		Status:       model.OrderStatusIncoming,
-		OrderDetails: orderDetails,
+		ClientID:             client.ID,
+		CustomerName:         request.CustomerName,
+		PhoneNumber:          phoneNumber,
+		CurrencyCode:         currency,
+		PromoCode:            strings.ToUpper(request.PromoCode),
+		Status:               model.OrderStatusIncoming,
+		OrderDetails:         orderDetails,
+		PromotionRedemptions: redemptions,
+		KitchenTickets:       RouteOrderDetails(stations, orderDetails),
+		PointsRedeemed:       request.RedeemPoints,
+		PointsAmount:         pointsAmount,
+		ScheduledAt:          request.ScheduledAt,
 		// Add other fields as needed
 	}
+	setOrderCharges(order, setting, charges)
+
+	// Orders for now and orders due within the lead time go to the kitchen right away
+	if order.ScheduledAt == nil || model.IsScheduleDue(setting, *order.ScheduledAt, now) {
+		order.ReleasedAt = &now
+	}
 
 	// Call the repository to add the order
-	order, err := s.orderRepo.AddOrder(ctx, order)
+	order, err = s.orderRepo.AddOrder(ctx, order)
+	var outOfStock *repository.OutOfStockError
+	if errors.As(err, &outOfStock) {
+		return nil, *NewOutOfStockError(outOfStock.Shortages)
+	}
+	if errors.Is(err, repository.ErrInsufficientPoints) {
+		return nil, *NewInsufficientPointsError()
+	}
+	if errors.Is(err, repository.ErrPromoUsageLimit) {
+		return nil, *NewPromoUsageLimitError()
+	}
 	if err != nil {
 		return nil, *NewUpdateQueryDBError()
 	}
 
+	s.publisher.Publish(event.Event{Type: event.OrderCreated, ClientID: order.ClientID, OrderID: order.ID, Order: order})
+
 	return order, *NewSuccessError()
 }
 
 func (s *orderService) EditOrder(ctx context.Context, token string, request *model.OrderRequest) (*entity.Order, AppError) {
-	validate := validator.New()
-	if err := validate.Struct(request); err != nil {
-		return nil, *NewInvalidRequestError(err.Error())
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
 	}
 
 	order, err := s.orderRepo.GetOrderByID(ctx, uint(request.ID), token)
@@ -124,6 +210,41 @@ func (s *orderService) EditOrder(ctx context.Context, token string, request *mod
 		return nil, *NewOrderNotFoundError()
 	}
 
+	if order.Status >= model.OrderStatusSuccess {
+		return nil, *NewInvalidOrderStatusError()
+	}
+
+	if order.LockedAt != nil {
+		return nil, *NewOrderLockedError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, order.ClientID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	// Orders keep the currency they were created in
+	if order.CurrencyCode == "" {
+		order.CurrencyCode = settingCurrency(setting)
+	}
+	setting = OrderSetting(setting, order.CurrencyCode)
+
+	phoneNumber, appErr := settingPhoneNumber(setting, request.PhoneNumber)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	// The pickup time can only be changed while the order waits for release
+	now := time.Now()
+	if !sameScheduledTime(request.ScheduledAt, order.ScheduledAt) {
+		if order.ReleasedAt != nil {
+			return nil, *NewInvalidScheduledTimeError()
+		}
+		if appErr := s.checkScheduledTime(ctx, setting, request.ScheduledAt, now); appErr != nil {
+			return nil, *appErr
+		}
+	}
+
 	var totalPrice float64
 	var orderDetails []entity.OrderDetail
 
@@ -137,32 +258,76 @@ func (s *orderService) EditOrder(ctx context.Context, token string, request *mod
 			return nil, *NewInvalidProductPriceError()
 		}
 
+		if product.Currency != "" && product.Currency != order.CurrencyCode {
+			return nil, *NewCurrencyMismatchError()
+		}
+
 		totalPrice += reqDetail.Total
 
 		orderDetail := entity.OrderDetail{
-			ProductID: reqDetail.ProductID,
-			Price:     reqDetail.Price,
-			Quantity:  reqDetail.Quantity,
-			Discount:  reqDetail.Discount,
-			Total:     reqDetail.Total,
+			ProductID:  reqDetail.ProductID,
+			CategoryID: product.CategoryID,
+			Price:      reqDetail.Price,
+			Quantity:   reqDetail.Quantity,
+			Discount:   reqDetail.Discount,
+			Total:      reqDetail.Total,
 		}
 
 		orderDetails = append(orderDetails, orderDetail)
 	}
 
-	if totalPrice != request.Total {
+	redemptions, promoDiscount, appErr := s.applyPromotion(ctx, order.ClientID, setting, phoneNumber, request, orderDetails, order.ID)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	charges := CalculateCharges(setting, totalPrice, promoDiscount)
+	if charges.GrandTotal != request.Total {
 		return nil, *NewInvalidTotalError()
 	}
 
-	order.Total = totalPrice
+	// The points redeemed when the order was created can not be changed by an edit
+	if request.RedeemPoints != order.PointsRedeemed || charges.GrandTotal < order.PointsAmount {
+		return nil, *NewInvalidRequestError("redeem_points")
+	}
+
+	stations, err := s.kitchenRepo.GetStations(ctx, order.ClientID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	setOrderCharges(order, setting, charges)
+	order.KitchenTickets = RouteOrderDetails(stations, orderDetails)
+	order.PromoCode = strings.ToUpper(request.PromoCode)
+	order.PromotionRedemptions = redemptions
 	order.OrderDetails = orderDetails
 	order.CustomerName = request.CustomerName
-	order.PhoneNumber = request.PhoneNumber
-	order.Status = model.OrderStatusIncoming
+	order.PhoneNumber = phoneNumber
+	order.ScheduledAt = request.ScheduledAt
 
 	updatedOrder, err := s.orderRepo.EditOrder(ctx, order)
-	if err != nil {
-		return nil, *NewUpdateQueryDBError()
+	var outOfStock *repository.OutOfStockError
+	if errors.As(err, &outOfStock) {
+		return nil, *NewOutOfStockError(outOfStock.Shortages)
+	}
+	if errors.Is(err, repository.ErrPromoUsageLimit) {
+		return nil, *NewPromoUsageLimitError()
+	}
+	if appErr := orderChangeError(err); appErr != nil {
+		return nil, *appErr
+	}
+
+	s.publisher.Publish(event.Event{Type: event.OrderEdited, ClientID: updatedOrder.ClientID, OrderID: updatedOrder.ID, Order: updatedOrder})
+
+	// A pickup time moved into the lead time releases the order right away
+	if updatedOrder.ReleasedAt == nil && (updatedOrder.ScheduledAt == nil || model.IsScheduleDue(setting, *updatedOrder.ScheduledAt, now)) {
+		releasedOrder, err := s.orderRepo.ReleaseOrder(ctx, updatedOrder.ID, now)
+		if err != nil {
+			return nil, *NewUpdateQueryDBError()
+		}
+
+		s.publisher.Publish(event.Event{Type: event.OrderReleased, ClientID: releasedOrder.ClientID, OrderID: releasedOrder.ID, Order: releasedOrder})
+		updatedOrder = releasedOrder
 	}
 
 	return updatedOrder, *NewSuccessError()
@@ -179,3 +344,348 @@ func (s orderService) GetOrder(ctx context.Context, token string, orderID int) (
 	}
 	return product, *NewSuccessError()
 }
+
+// ListOrders returns the orders of the client with one of the statuses, oldest first. Without
+// statuses the open orders are returned, or all orders when searching by phone number.
+// The phone number can be written in any format, it is normalised like the number of an order.
+func (s *orderService) ListOrders(ctx context.Context, token string, statuses []int, phoneNumber string) ([]*entity.Order, AppError) {
+	if len(statuses) == 0 && phoneNumber != "" {
+		statuses = []int{model.OrderStatusIncoming, model.OrderStatusPaid, model.OrderStatusProcessing, model.OrderStatusSuccess, model.OrderStatusCancelled}
+	}
+	if len(statuses) == 0 {
+		statuses = []int{model.OrderStatusIncoming, model.OrderStatusPaid, model.OrderStatusProcessing}
+	}
+
+	for _, status := range statuses {
+		if status < model.OrderStatusIncoming || status > model.OrderStatusCancelled {
+			return nil, *NewInvalidOrderStatusError()
+		}
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if phoneNumber == "" {
+		orders, err := s.orderRepo.GetOrdersByStatus(ctx, token, statuses)
+		if err != nil {
+			return nil, *NewQueryDBError()
+		}
+
+		return orders, *NewSuccessError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	phoneNumber, appErr := settingPhoneNumber(setting, phoneNumber)
+	if appErr != nil {
+		return nil, *appErr
+	}
+
+	orders, err := s.orderRepo.GetOrdersByPhone(ctx, client.ID, statuses, phoneNumber)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return orders, *NewSuccessError()
+}
+
+// UpdateStatus moves an order forward through Paid, Processing and Success. Orders can not go
+// back to an earlier status and finished or cancelled orders can not be changed. Scheduled orders
+// can be paid in advance but are only prepared after they are released to the kitchen. The
+// payment method, cash when empty, is recorded with the first status from Paid.
+func (s *orderService) UpdateStatus(ctx context.Context, token string, orderID int, request *model.UpdateStatusRequest) (*entity.Order, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, uint(orderID), token)
+	if err != nil {
+		return nil, *NewOrderNotFoundError()
+	}
+
+	if !model.CanChangeOrderStatus(order, request.Status) {
+		return nil, *NewInvalidOrderStatusError()
+	}
+
+	if order.PaidAt == nil {
+		order.PaymentMethod = request.PaymentMethod
+	}
+
+	order, err = s.orderRepo.UpdateOrderStatus(ctx, order, request.Status)
+	if appErr := orderChangeError(err); appErr != nil {
+		return nil, *appErr
+	}
+
+	s.publisher.Publish(event.Event{Type: event.OrderStatusChanged, ClientID: order.ClientID, OrderID: order.ID, Order: order})
+
+	return order, *NewSuccessError()
+}
+
+// CancelOrder cancels an order that has not been completed yet. Orders of a closed business day
+// can not be cancelled.
+func (s *orderService) CancelOrder(ctx context.Context, token string, orderID int) (*entity.Order, AppError) {
+	order, err := s.orderRepo.GetOrderByID(ctx, uint(orderID), token)
+	if err != nil {
+		return nil, *NewOrderNotFoundError()
+	}
+
+	if !model.CanChangeOrderStatus(order, model.OrderStatusCancelled) {
+		return nil, *NewInvalidOrderStatusError()
+	}
+
+	if order.LockedAt != nil {
+		return nil, *NewOrderLockedError()
+	}
+
+	order, err = s.orderRepo.UpdateOrderStatus(ctx, order, model.OrderStatusCancelled)
+	if appErr := orderChangeError(err); appErr != nil {
+		return nil, *appErr
+	}
+
+	s.publisher.Publish(event.Event{Type: event.OrderCancelled, ClientID: order.ClientID, OrderID: order.ID, Order: order})
+
+	return order, *NewSuccessError()
+}
+
+// GetScheduledOrders returns the scheduled orders that wait for release to the kitchen, earliest
+// pickup first.
+func (s *orderService) GetScheduledOrders(ctx context.Context, token string) ([]*entity.Order, AppError) {
+	orders, err := s.orderRepo.GetScheduledOrders(ctx, token)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return orders, *NewSuccessError()
+}
+
+// checkOrderAcceptance enforces when a client accepts new orders: never while orders are paused,
+// for right away only while the client is open and for pickup only at a time the client is open.
+// Opening hours are wall-clock times in the time zone of the client.
+func (s *orderService) checkOrderAcceptance(ctx context.Context, setting *entity.ClientSetting, scheduledAt *time.Time, now time.Time) *AppError {
+	if model.IsOrdersPaused(setting, now) {
+		return NewStoreClosedError()
+	}
+
+	if scheduledAt != nil {
+		return s.checkScheduledTime(ctx, setting, scheduledAt, now)
+	}
+
+	now = now.In(model.ClientLocation(setting))
+	hours, holidays, err := storeSchedule(ctx, s.clientRepo, setting.ClientID, now)
+	if err != nil {
+		return NewQueryDBError()
+	}
+
+	if !model.IsWithinOpeningHours(hours, holidays, now) {
+		return NewStoreClosedError()
+	}
+
+	return nil
+}
+
+// checkScheduledTime validates the pickup time of an order against the opening hours and holidays
+// of the client. Orders without a pickup time are prepared right away.
+func (s *orderService) checkScheduledTime(ctx context.Context, setting *entity.ClientSetting, scheduledAt *time.Time, now time.Time) *AppError {
+	if scheduledAt == nil {
+		return nil
+	}
+
+	now = now.In(model.ClientLocation(setting))
+	hours, holidays, err := storeSchedule(ctx, s.clientRepo, setting.ClientID, now)
+	if err != nil {
+		return NewQueryDBError()
+	}
+
+	if !model.ValidScheduledTime(hours, holidays, *scheduledAt, now) {
+		return NewInvalidScheduledTimeError()
+	}
+
+	return nil
+}
+
+// sameScheduledTime reports whether two optional pickup times are equal.
+func sameScheduledTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// orderChangeError maps the error of changing an order, the order may have been finished,
+// cancelled or locked since it was read.
+func orderChangeError(err error) *AppError {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrInvalidOrderStatus):
+		return NewInvalidOrderStatusError()
+	case errors.Is(err, repository.ErrOrderLocked):
+		return NewOrderLockedError()
+	default:
+		return NewUpdateQueryDBError()
+	}
+}
+
+// redeemPointsAmount returns the amount paid by the points redeemed in the request. Points can only
+// be redeemed by a customer, when the client has loyalty enabled and up to the total of the order.
+// phoneNumber is the E.164 number of the customer. The balance of the customer is checked when the
+// order is stored.
+func redeemPointsAmount(setting *entity.ClientSetting, phoneNumber string, request *model.OrderRequest, total float64) (float64, *AppError) {
+	if request.RedeemPoints == 0 {
+		return 0, nil
+	}
+
+	if phoneNumber == "" {
+		return 0, NewInvalidRequestError("phone_number")
+	}
+
+	amount := model.LoyaltyPointsAmount(setting, request.RedeemPoints)
+	if amount <= 0 || amount > total {
+		return 0, NewInvalidRequestError("redeem_points")
+	}
+
+	return amount, nil
+}
+
+// settingCurrency returns the currency configured for a client or the default currency.
+func settingCurrency(setting *entity.ClientSetting) string {
+	if setting.CurrencyCode == "" {
+		return model.DefaultCurrency
+	}
+	return setting.CurrencyCode
+}
+
+// settingCountry returns the country of local phone numbers of a client or the default country.
+func settingCountry(setting *entity.ClientSetting) string {
+	if setting.CountryCode == "" {
+		return model.DefaultCountry
+	}
+	return setting.CountryCode
+}
+
+// settingPhoneNumber normalises a phone number to E.164 with the country of the client. An empty
+// phone number stays empty.
+func settingPhoneNumber(setting *entity.ClientSetting, phoneNumber string) (string, *AppError) {
+	if strings.TrimSpace(phoneNumber) == "" {
+		return "", nil
+	}
+
+	normalized, err := model.ParsePhoneNumber(phoneNumber, settingCountry(setting))
+	if err != nil {
+		return "", NewInvalidRequestError("phone_number")
+	}
+
+	return normalized, nil
+}
+
+// setOrderCharges stores the price breakdown on the order together with the rates it was
+// calculated with, so later changes to the client setting do not alter existing orders.
+func setOrderCharges(order *entity.Order, setting *entity.ClientSetting, charges OrderCharges) {
+	order.Subtotal = charges.Subtotal
+	order.PromoDiscount = charges.Discount
+	order.ServiceChargeRate = setting.ServiceChargeRate
+	order.ServiceCharge = charges.ServiceCharge
+	order.TaxRate = setting.TaxRate
+	order.TaxInclusive = setting.TaxInclusive
+	order.Tax = charges.Tax
+	order.RoundingAdjustment = charges.RoundingAdjustment
+	order.Total = charges.GrandTotal
+}
+
+// applyPromotion evaluates the promo code of the request, or the best automatic promotion when no
+// code is given, and returns the redemption to record together with the order discount.
+// clientID is the client of the order, never the one of the request, phoneNumber is the E.164
+// number of the customer and orderID is the order being edited and is zero for new orders. Hour
+// windows are evaluated in the time zone of the client.
+func (s *orderService) applyPromotion(ctx context.Context, clientID uint, setting *entity.ClientSetting, phoneNumber string, request *model.OrderRequest, details []entity.OrderDetail, orderID uint) ([]entity.PromotionRedemption, float64, *AppError) {
+	now := time.Now().In(model.ClientLocation(setting))
+	var promotion *entity.Promotion
+	var discount float64
+
+	if request.PromoCode != "" {
+		result, err := s.promotionRepo.GetPromotionByCode(ctx, clientID, strings.ToUpper(request.PromoCode))
+		if err != nil {
+			return nil, 0, NewPromoNotFoundError()
+		}
+
+		value, appErr := CalculatePromotionDiscount(result, details, now)
+		if appErr != nil {
+			return nil, 0, appErr
+		}
+
+		if appErr := s.checkPromotionUsage(ctx, result, phoneNumber, orderID); appErr != nil {
+			return nil, 0, appErr
+		}
+
+		promotion = result
+		discount = value
+	} else {
+		candidates, err := s.promotionRepo.GetAutoApplyPromotions(ctx, clientID, now)
+		if err != nil {
+			return nil, 0, NewQueryDBError()
+		}
+
+		for _, candidate := range candidates {
+			value, appErr := CalculatePromotionDiscount(candidate, details, now)
+			if appErr != nil || value <= discount {
+				continue
+			}
+
+			if appErr := s.checkPromotionUsage(ctx, candidate, phoneNumber, orderID); appErr != nil {
+				continue
+			}
+
+			promotion = candidate
+			discount = value
+		}
+	}
+
+	if promotion == nil {
+		return nil, 0, nil
+	}
+
+	redemptions := []entity.PromotionRedemption{
+		{
+			PromotionID: promotion.ID,
+			ClientID:    clientID,
+			PhoneNumber: phoneNumber,
+			Discount:    discount,
+		},
+	}
+
+	return redemptions, discount, nil
+}
+
+// checkPromotionUsage enforces the overall and per customer usage limits of a promotion.
+// Customers are identified by phone number, so a per customer limit requires one.
+func (s *orderService) checkPromotionUsage(ctx context.Context, promotion *entity.Promotion, phoneNumber string, orderID uint) *AppError {
+	if promotion.UsageLimit > 0 {
+		count, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, "", orderID)
+		if err != nil {
+			return NewQueryDBError()
+		}
+		if count >= int64(promotion.UsageLimit) {
+			return NewPromoUsageLimitError()
+		}
+	}
+
+	if promotion.PerCustomerLimit > 0 {
+		if phoneNumber == "" {
+			return NewPromoNotApplicableError()
+		}
+		count, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, phoneNumber, orderID)
+		if err != nil {
+			return NewQueryDBError()
+		}
+		if count >= int64(promotion.PerCustomerLimit) {
+			return NewPromoUsageLimitError()
+		}
+	}
+
+	return nil
+}
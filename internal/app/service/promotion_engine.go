@@ -0,0 +1,136 @@
+package service
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"math"
+	"time"
+)
+
+// CalculatePromotionDiscount evaluates a promotion against the order lines and returns the
+// discount it grants. An AppError is returned when the promotion is outside its time window,
+// the minimum spend is not reached or none of the lines qualify.
+func CalculatePromotionDiscount(promotion *entity.Promotion, details []entity.OrderDetail, now time.Time) (float64, *AppError) {
+	if !promotionActiveAt(promotion, now) {
+		return 0, NewPromoNotApplicableError()
+	}
+
+	var subtotal float64
+	for _, detail := range details {
+		subtotal += detail.Total
+	}
+
+	if subtotal < promotion.MinSpend {
+		return 0, NewPromoNotApplicableError()
+	}
+
+	eligible := make(map[uint]bool)
+	for _, product := range promotion.PromotionProducts {
+		eligible[product.ProductID] = true
+	}
+	isEligible := func(productID uint) bool {
+		return len(eligible) == 0 || eligible[productID]
+	}
+
+	var discount float64
+	switch promotion.Type {
+	case model.PromotionTypePercentage:
+		var base float64
+		for _, detail := range details {
+			if isEligible(detail.ProductID) {
+				base += detail.Total
+			}
+		}
+		discount = base * promotion.Value / 100
+		if promotion.MaxDiscount > 0 && discount > promotion.MaxDiscount {
+			discount = promotion.MaxDiscount
+		}
+
+	case model.PromotionTypeFixed:
+		var base float64
+		for _, detail := range details {
+			if isEligible(detail.ProductID) {
+				base += detail.Total
+			}
+		}
+		discount = math.Min(promotion.Value, base)
+
+	case model.PromotionTypeBOGO:
+		buy := promotion.BuyQuantity
+		if buy < 1 {
+			buy = 1
+		}
+		get := promotion.GetQuantity
+		if get < 1 {
+			get = 1
+		}
+		for _, detail := range details {
+			if !isEligible(detail.ProductID) {
+				continue
+			}
+			free := detail.Quantity / (buy + get) * get
+			discount += math.Min(float64(free)*detail.Price, detail.Total)
+		}
+
+	case model.PromotionTypeBundle:
+		// Every bundle product has to be ordered, the number of complete bundles is
+		// limited by the product with the lowest quantity
+		if len(eligible) == 0 {
+			return 0, NewPromoNotApplicableError()
+		}
+		bundles := -1
+		var bundlePrice float64
+		for productID := range eligible {
+			quantity := 0
+			var price float64
+			for _, detail := range details {
+				if detail.ProductID == productID {
+					quantity += detail.Quantity
+					price = detail.Price
+				}
+			}
+			if bundles == -1 || quantity < bundles {
+				bundles = quantity
+			}
+			bundlePrice += price
+		}
+		if bundles > 0 && bundlePrice > promotion.Value {
+			discount = float64(bundles) * (bundlePrice - promotion.Value)
+		}
+	}
+
+	discount = math.Min(math.Round(discount*100)/100, subtotal)
+	if discount <= 0 {
+		return 0, NewPromoNotApplicableError()
+	}
+
+	return discount, nil
+}
+
+// promotionActiveAt checks the date range and the optional daily hour window of a promotion.
+// A window where HourStart is greater than HourEnd wraps around midnight. The hour is the one of
+// now in its location, callers pass now in the time zone of the client.
+func promotionActiveAt(promotion *entity.Promotion, now time.Time) bool {
+	if !promotion.IsActive {
+		return false
+	}
+
+	if promotion.StartAt != nil && now.Before(*promotion.StartAt) {
+		return false
+	}
+
+	if promotion.EndAt != nil && now.After(*promotion.EndAt) {
+		return false
+	}
+
+	if promotion.HourStart == promotion.HourEnd {
+		return true
+	}
+
+	hour := now.Hour()
+	if promotion.HourStart < promotion.HourEnd {
+		return hour >= promotion.HourStart && hour < promotion.HourEnd
+	}
+
+	return hour >= promotion.HourStart || hour < promotion.HourEnd
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"strings"
+	"time"
+)
+
+type PromotionService interface {
+	AddPromotion(context.Context, string, *model.PromotionRequest) (*entity.Promotion, AppError)
+	GetPromotions(context.Context, string) ([]*entity.Promotion, AppError)
+}
+
+type promotionService struct {
+	promotionRepo repository.PromotionRepository
+	clientRepo    repository.ClientRepository
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository, clientRepo repository.ClientRepository) PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+		clientRepo:    clientRepo,
+	}
+}
+
+func (s *promotionService) AddPromotion(ctx context.Context, token string, request *model.PromotionRequest) (*entity.Promotion, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	if request.Type == model.PromotionTypeBundle && len(request.ProductIDs) == 0 {
+		return nil, *NewInvalidRequestError("bundle promotion requires product_ids")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	promotion := &entity.Promotion{
+		ClientID:         client.ID,
+		Code:             strings.ToUpper(request.Code),
+		Name:             request.Name,
+		Type:             request.Type,
+		Value:            request.Value,
+		MaxDiscount:      request.MaxDiscount,
+		BuyQuantity:      request.BuyQuantity,
+		GetQuantity:      request.GetQuantity,
+		MinSpend:         request.MinSpend,
+		HourStart:        request.HourStart,
+		HourEnd:          request.HourEnd,
+		UsageLimit:       request.UsageLimit,
+		PerCustomerLimit: request.PerCustomerLimit,
+		AutoApply:        request.AutoApply,
+		IsActive:         true,
+	}
+
+	layoutFormat := "2006-01-02 15:04:05"
+	if request.StartAt != "" {
+		startAt, _ := time.ParseInLocation(layoutFormat, request.StartAt, time.Local)
+		promotion.StartAt = &startAt
+	}
+	if request.EndAt != "" {
+		endAt, _ := time.ParseInLocation(layoutFormat, request.EndAt, time.Local)
+		promotion.EndAt = &endAt
+	}
+
+	for _, productID := range request.ProductIDs {
+		promotion.PromotionProducts = append(promotion.PromotionProducts, entity.PromotionProduct{ProductID: productID})
+	}
+
+	promotion, err = s.promotionRepo.AddPromotion(ctx, promotion)
+	if errors.Is(err, repository.ErrPromoCodeExists) {
+		return nil, *NewInvalidRequestError("code already exists")
+	}
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return promotion, *NewSuccessError()
+}
+
+func (s *promotionService) GetPromotions(ctx context.Context, token string) ([]*entity.Promotion, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	promotions, err := s.promotionRepo.GetPromotions(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return promotions, *NewSuccessError()
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	exRepo "maqhaa/order_service/external/repository"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+type ReportService interface {
+	GetDailyReport(context.Context, string, string, string) (*model.DailyReport, AppError)
+	GetProductRanking(context.Context, string, string, string, string, int) (*model.ProductRanking, AppError)
+	GetHourlyHeatmap(context.Context, string, string, string, []int) (*model.HourlyHeatmap, AppError)
+}
+
+type reportService struct {
+	reportRepo  repository.ReportRepository
+	clientRepo  repository.ClientRepository
+	productRepo exRepo.ProductRepository
+}
+
+func NewReportService(reportRepo repository.ReportRepository, clientRepo repository.ClientRepository, productRepo exRepo.ProductRepository) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		clientRepo:  clientRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetDailyReport returns the sales summary of the client per business day from and to, both
+// YYYY-MM-DD in the time zone of the client and today when empty.
+func (s *reportService) GetDailyReport(ctx context.Context, token string, from string, to string) (*model.DailyReport, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	loc := model.ClientLocation(setting)
+	dateRange, err := model.ParseDateRange(from, to, time.Now().In(loc))
+	if err != nil {
+		return nil, *NewInvalidRequestError("date range")
+	}
+
+	rows, err := s.reportRepo.GetDailySales(ctx, client.ID, dateRange.From, dateRange.To, loc)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return model.NewDailyReport(client.ID, settingCurrency(setting), dateRange, rows), *NewSuccessError()
+}
+
+// GetProductRanking returns the best selling products of the client from and to, days in the time
+// zone of the client, ranked by quantity or revenue. A limit of zero uses the default limit.
+func (s *reportService) GetProductRanking(ctx context.Context, token string, from string, to string, sortBy string, limit int) (*model.ProductRanking, AppError) {
+	if sortBy == "" {
+		sortBy = model.RankingByQuantity
+	}
+	if !model.IsRankingSort(sortBy) {
+		return nil, *NewInvalidRequestError("sort")
+	}
+
+	if limit == 0 {
+		limit = model.RankingDefaultLimit
+	}
+	if limit < 0 || limit > model.RankingMaxLimit {
+		return nil, *NewInvalidRequestError("limit")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	dateRange, err := model.ParseDateRange(from, to, time.Now().In(model.ClientLocation(setting)))
+	if err != nil {
+		return nil, *NewInvalidRequestError("date range")
+	}
+
+	products, err := s.reportRepo.GetProductSales(ctx, client.ID, dateRange.From, dateRange.To, sortBy, limit)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ProductID)
+	}
+
+	// Products the product service can not find are ranked without a name
+	names, _ := s.productRepo.GetProductsByIDs(ctx, productIDs, token)
+
+	for i := range products {
+		products[i].Rank = i + 1
+		products[i].Revenue = model.RoundAmount(products[i].Revenue, settingCurrency(setting))
+		if product, ok := names[products[i].ProductID]; ok {
+			products[i].ProductName = product.Name
+		}
+	}
+
+	return &model.ProductRanking{
+		ClientID:     client.ID,
+		CurrencyCode: settingCurrency(setting),
+		From:         dateRange.FirstDay(),
+		To:           dateRange.LastDay(),
+		SortBy:       sortBy,
+		Products:     products,
+	}, *NewSuccessError()
+}
+
+// GetHourlyHeatmap returns the orders and sales of the client by weekday and hour in the time zone
+// of the client. The days from and to are days in that time zone. Without statuses the orders
+// that were not cancelled are counted.
+func (s *reportService) GetHourlyHeatmap(ctx context.Context, token string, from string, to string, statuses []int) (*model.HourlyHeatmap, AppError) {
+	if len(statuses) == 0 {
+		statuses = []int{model.OrderStatusIncoming, model.OrderStatusPaid, model.OrderStatusProcessing, model.OrderStatusSuccess}
+	}
+
+	for _, status := range statuses {
+		if status < model.OrderStatusIncoming || status > model.OrderStatusCancelled {
+			return nil, *NewInvalidOrderStatusError()
+		}
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	loc := model.ClientLocation(setting)
+	dateRange, err := model.ParseDateRange(from, to, time.Now().In(loc))
+	if err != nil {
+		return nil, *NewInvalidRequestError("date range")
+	}
+
+	buckets, err := s.reportRepo.GetHeatmapBuckets(ctx, client.ID, dateRange.From, dateRange.To, statuses)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return model.NewHourlyHeatmap(client.ID, settingCurrency(setting), loc, dateRange, statuses, buckets), *NewSuccessError()
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"time"
+)
+
+type ShiftService interface {
+	OpenShift(context.Context, string, *model.OpenShiftRequest) (*model.ShiftReport, AppError)
+	GetCurrentShift(context.Context, string) (*model.ShiftReport, AppError)
+	CloseShift(context.Context, string, *model.CloseShiftRequest) (*model.ShiftReport, AppError)
+	GetShift(context.Context, string, uint) (*model.ShiftReport, AppError)
+	GetShifts(context.Context, string, string, string) ([]*entity.Shift, AppError)
+}
+
+type shiftService struct {
+	shiftRepo  repository.ShiftRepository
+	clientRepo repository.ClientRepository
+}
+
+func NewShiftService(shiftRepo repository.ShiftRepository, clientRepo repository.ClientRepository) ShiftService {
+	return &shiftService{
+		shiftRepo:  shiftRepo,
+		clientRepo: clientRepo,
+	}
+}
+
+// OpenShift opens the register of the client with the opening cash float. The business day of the
+// shift is the day it opens in the time zone of the client. A client has one open shift at a time.
+func (s *shiftService) OpenShift(ctx context.Context, token string, request *model.OpenShiftRequest) (*model.ShiftReport, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	now := time.Now()
+	shift, err := s.shiftRepo.OpenShift(ctx, &entity.Shift{
+		ClientID:     client.ID,
+		BusinessDate: now.In(model.ClientLocation(setting)).Format("2006-01-02"),
+		OpenedBy:     request.Cashier,
+		OpeningCash:  model.RoundAmount(request.OpeningCash, settingCurrency(setting)),
+		OpeningNote:  request.Note,
+		OpenedAt:     now,
+	})
+	if errors.Is(err, repository.ErrShiftAlreadyOpen) {
+		return nil, *NewShiftAlreadyOpenError()
+	}
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return model.NewShiftReport(shift, settingCurrency(setting), nil), *NewSuccessError()
+}
+
+// GetCurrentShift returns the report of the open shift of the client so far.
+func (s *shiftService) GetCurrentShift(ctx context.Context, token string) (*model.ShiftReport, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	shift, err := s.shiftRepo.GetOpenShift(ctx, client.ID)
+	if errors.Is(err, repository.ErrShiftNotFound) {
+		return nil, *NewShiftNotFoundError()
+	}
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return s.report(ctx, client.ID, shift)
+}
+
+// CloseShift closes the open shift of the client with the cash counted in the register and
+// returns its closing report. The orders of the business day can no longer be edited or
+// cancelled once the shift is closed.
+func (s *shiftService) CloseShift(ctx context.Context, token string, request *model.CloseShiftRequest) (*model.ShiftReport, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	shift, err := s.shiftRepo.CloseShift(ctx, client.ID, roundAmount(*request.CountedCash), request.Cashier, request.Note, time.Now())
+	if errors.Is(err, repository.ErrShiftNotFound) {
+		return nil, *NewShiftNotFoundError()
+	}
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return s.report(ctx, client.ID, shift)
+}
+
+// GetShift returns the report of a shift of the client.
+func (s *shiftService) GetShift(ctx context.Context, token string, shiftID uint) (*model.ShiftReport, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	shift, err := s.shiftRepo.GetShift(ctx, client.ID, shiftID)
+	if errors.Is(err, repository.ErrShiftNotFound) {
+		return nil, *NewShiftNotFoundError()
+	}
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return s.report(ctx, client.ID, shift)
+}
+
+// GetShifts returns the shifts of the client opened on the business days from and to, both
+// YYYY-MM-DD in the time zone of the client and today when empty.
+func (s *shiftService) GetShifts(ctx context.Context, token string, from string, to string) ([]*entity.Shift, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	setting, err := s.clientRepo.GetClientSetting(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	dateRange, err := model.ParseDateRange(from, to, time.Now().In(model.ClientLocation(setting)))
+	if err != nil {
+		return nil, *NewInvalidRequestError("date range")
+	}
+
+	shifts, err := s.shiftRepo.GetShifts(ctx, client.ID, dateRange.FirstDay(), dateRange.LastDay())
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return shifts, *NewSuccessError()
+}
+
+// report adds the payments of the orders of a shift by payment method to the shift.
+func (s *shiftService) report(ctx context.Context, clientID uint, shift *entity.Shift) (*model.ShiftReport, AppError) {
+	setting, err := s.clientRepo.GetClientSetting(ctx, clientID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	payments, err := s.shiftRepo.GetShiftPayments(ctx, shift.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return model.NewShiftReport(shift, settingCurrency(setting), payments), *NewSuccessError()
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	exRepo "maqhaa/order_service/external/repository"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+)
+
+type StockService interface {
+	GetStocks(context.Context, string) ([]*entity.ProductStock, AppError)
+	SetStock(context.Context, string, uint, *model.StockRequest) (*entity.ProductStock, AppError)
+	DeleteStock(context.Context, string, uint) AppError
+}
+
+type stockService struct {
+	stockRepo   repository.StockRepository
+	clientRepo  repository.ClientRepository
+	productRepo exRepo.ProductRepository
+}
+
+func NewStockService(stockRepo repository.StockRepository, clientRepo repository.ClientRepository, productRepo exRepo.ProductRepository) StockService {
+	return &stockService{
+		stockRepo:   stockRepo,
+		clientRepo:  clientRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetStocks returns the products of the client whose stock is tracked.
+func (s *stockService) GetStocks(ctx context.Context, token string) ([]*entity.ProductStock, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	stocks, err := s.stockRepo.GetStocks(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return stocks, *NewSuccessError()
+}
+
+// SetStock sets the quantity on hand of a product of the client, orders for the product are then
+// limited to the stock that is not reserved.
+func (s *stockService) SetStock(ctx context.Context, token string, productID uint, request *model.StockRequest) (*entity.ProductStock, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if _, err := s.productRepo.GetProductByID(ctx, productID, token); err != nil {
+		return nil, *NewProductNotFoundError()
+	}
+
+	stock, err := s.stockRepo.SetStock(ctx, client.ID, productID, request.OnHand)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return stock, *NewSuccessError()
+}
+
+// DeleteStock stops tracking the stock of a product, orders for the product are no longer limited.
+func (s *stockService) DeleteStock(ctx context.Context, token string, productID uint) AppError {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return *NewInvalidTokenError()
+	}
+
+	err = s.stockRepo.DeleteStock(ctx, client.ID, productID)
+	if errors.Is(err, repository.ErrStockNotFound) {
+		return *NewStockNotFoundError()
+	}
+	if err != nil {
+		return *NewUpdateQueryDBError()
+	}
+
+	return *NewSuccessError()
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared by all services, it caches the struct rules and is safe for concurrent use.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Report fields by the name they have in the JSON request
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// The validator is configured once at start up, a failure is a programming error
+	if err := v.RegisterValidation("phone", validatePhoneNumber); err != nil {
+		panic(err)
+	}
+	if err := i18n.RegisterValidator(v); err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// validatePhoneNumber is the phone rule, the number is normalised with the country of the client
+// by the service.
+func validatePhoneNumber(field validator.FieldLevel) bool {
+	return model.ValidPhoneNumber(field.Field().String())
+}
+
+// validateRequest checks a request against its validate tags.
+func validateRequest(request interface{}) *AppError {
+	if err := validate.Struct(request); err != nil {
+		return NewValidationError(err)
+	}
+
+	return nil
+}
+
+// NewValidationError converts the errors of the validator to an InvalidRequest error that lists
+// every invalid field.
+func NewValidationError(err error) *AppError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return NewInvalidRequestError(err.Error())
+	}
+
+	fields := make([]string, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fields = append(fields, fieldPath(fieldError.Namespace()))
+	}
+
+	appErr := NewInvalidRequestError(strings.Join(fields, ", "))
+	appErr.Errors = fieldErrors(validationErrors, i18n.DefaultLanguage)
+	appErr.validation = validationErrors
+	return appErr
+}
+
+// fieldErrors translates the errors of the validator to the language.
+func fieldErrors(validationErrors validator.ValidationErrors, lang string) []model.FieldError {
+	trans := i18n.Translator(lang)
+
+	fieldErrors := make([]model.FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fieldErrors = append(fieldErrors, model.FieldError{
+			Field:   fieldPath(fieldError.Namespace()),
+			Rule:    fieldError.Tag(),
+			Message: fieldError.Translate(trans),
+		})
+	}
+
+	return fieldErrors
+}
+
+// fieldPath removes the name of the request struct from the namespace of a field.
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
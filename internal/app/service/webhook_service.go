@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"maqhaa/order_service/internal/app/webhook"
+	"strings"
+	"time"
+)
+
+type WebhookService interface {
+	AddWebhook(context.Context, string, *model.WebhookRequest) (*entity.WebhookSubscription, AppError)
+	GetWebhooks(context.Context, string) ([]*entity.WebhookSubscription, AppError)
+	DeactivateWebhook(context.Context, string, uint) AppError
+	GetDeliveries(context.Context, string, string) ([]*entity.WebhookDelivery, AppError)
+	Redeliver(context.Context, string, uint) (*entity.WebhookDelivery, AppError)
+}
+
+type webhookService struct {
+	webhookRepo  repository.WebhookRepository
+	clientRepo   repository.ClientRepository
+	allowPrivate bool
+}
+
+// NewWebhookService creates the webhook service. Subscriptions to loopback and private addresses
+// are refused unless allowPrivate is set.
+func NewWebhookService(webhookRepo repository.WebhookRepository, clientRepo repository.ClientRepository, allowPrivate bool) WebhookService {
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		clientRepo:   clientRepo,
+		allowPrivate: allowPrivate,
+	}
+}
+
+// AddWebhook subscribes a partner URL to order events. A secret is generated when the request has
+// none, it is only returned in this response. The URL must resolve to public addresses, the
+// dispatcher checks the address again when sending.
+func (s *webhookService) AddWebhook(ctx context.Context, token string, request *model.WebhookRequest) (*entity.WebhookSubscription, AppError) {
+	if appErr := validateRequest(request); appErr != nil {
+		return nil, *appErr
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	if !s.allowPrivate {
+		if err := webhook.CheckURL(ctx, request.URL); err != nil {
+			return nil, *NewInvalidRequestError("url")
+		}
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		key := make([]byte, 24)
+		if _, err := rand.Read(key); err != nil {
+			return nil, *NewGeneralSystemError()
+		}
+		secret = hex.EncodeToString(key)
+	}
+
+	subscription := &entity.WebhookSubscription{
+		ClientID:   client.ID,
+		URL:        request.URL,
+		EventTypes: strings.Join(request.EventTypes, ","),
+		Secret:     secret,
+		IsActive:   true,
+	}
+
+	subscription, err = s.webhookRepo.AddSubscription(ctx, subscription)
+	if err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return subscription, *NewSuccessError()
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, token string) ([]*entity.WebhookSubscription, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	subscriptions, err := s.webhookRepo.GetSubscriptions(ctx, client.ID)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+
+	return subscriptions, *NewSuccessError()
+}
+
+// DeactivateWebhook stops sending events to a subscription, its pending deliveries are dropped.
+func (s *webhookService) DeactivateWebhook(ctx context.Context, token string, subscriptionID uint) AppError {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return *NewInvalidTokenError()
+	}
+
+	err = s.webhookRepo.DeactivateSubscription(ctx, client.ID, subscriptionID)
+	if errors.Is(err, repository.ErrWebhookNotFound) {
+		return *NewWebhookNotFoundError()
+	}
+	if err != nil {
+		return *NewUpdateQueryDBError()
+	}
+
+	return *NewSuccessError()
+}
+
+// GetDeliveries lists the latest deliveries of the client. The dead letter list is the list of
+// deliveries with the dead status.
+func (s *webhookService) GetDeliveries(ctx context.Context, token string, status string) ([]*entity.WebhookDelivery, AppError) {
+	switch status {
+	case "", model.WebhookDeliveryPending, model.WebhookDeliveryDelivered, model.WebhookDeliveryDead:
+	default:
+		return nil, *NewInvalidRequestError("status must be pending, delivered or dead")
+	}
+
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveries(ctx, client.ID, status)
+	if err != nil {
+		return nil, *NewQueryDBError()
+	}
+
+	return deliveries, *NewSuccessError()
+}
+
+// Redeliver queues a delivery to be sent again with a fresh number of attempts, whatever its
+// current status.
+func (s *webhookService) Redeliver(ctx context.Context, token string, deliveryID uint) (*entity.WebhookDelivery, AppError) {
+	client, err := s.clientRepo.GetClientByToken(ctx, token)
+	if err != nil {
+		return nil, *NewInvalidTokenError()
+	}
+
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, client.ID, deliveryID)
+	if err != nil {
+		return nil, *NewWebhookDeliveryNotFoundError()
+	}
+
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.DeliveredAt = nil
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, *NewUpdateQueryDBError()
+	}
+
+	return delivery, *NewSuccessError()
+}
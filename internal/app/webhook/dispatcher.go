@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/outbox"
+	"maqhaa/order_service/internal/app/repository"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultInterval    = 5 * time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 8
+	DefaultBackoff     = 30 * time.Second
+	DefaultTimeout     = 10 * time.Second
+
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff = outbox.MaxBackoff
+
+	maxErrorLength = 500
+)
+
+// ErrInactiveSubscription is the error of a delivery whose subscription was deactivated.
+var ErrInactiveSubscription = errors.New("webhook subscription is not active")
+
+// Dispatcher sends the due webhook deliveries. A failed delivery is retried with exponential
+// backoff and moved to the dead letter list after maxAttempts attempts. Deliveries are only sent
+// to public addresses unless allowPrivate is set, which is meant for local development.
+type Dispatcher struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func NewDispatcher(webhookRepo repository.WebhookRepository, interval time.Duration, maxAttempts int, backoff time.Duration, timeout time.Duration, allowPrivate bool) *Dispatcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	client := &http.Client{Timeout: timeout}
+	if !allowPrivate {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{Timeout: timeout, Control: dialControl}).DialContext
+		client.Transport = transport
+	}
+
+	return &Dispatcher{
+		webhookRepo: webhookRepo,
+		client:      client,
+		interval:    interval,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// Run sends due deliveries every interval until the context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessBatch(ctx, time.Now()); err != nil {
+			logging.Log.Errorf("Error dispatching webhooks  %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch sends the deliveries that are due at now and returns the number of successful ones.
+func (d *Dispatcher) ProcessBatch(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := d.webhookRepo.GetDueDeliveries(ctx, now, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		// A delivery of a deactivated subscription is not sent and not retried
+		if delivery.Subscription != nil && !delivery.Subscription.IsActive {
+			delivery.Status = model.WebhookDeliveryDead
+			delivery.LastError = ErrInactiveSubscription.Error()
+			if err := d.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+
+		statusCode, err := d.send(ctx, delivery, now)
+
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		if err == nil {
+			delivery.Status = model.WebhookDeliveryDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			delivered++
+		} else {
+			delivery.LastError = truncateError(err)
+			if delivery.Attempts >= d.maxAttempts {
+				delivery.Status = model.WebhookDeliveryDead
+			} else {
+				delivery.NextAttemptAt = now.Add(Backoff(d.backoff, delivery.Attempts))
+			}
+		}
+
+		if err := d.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *entity.WebhookDelivery, now time.Time) (int, error) {
+	if delivery.Subscription == nil {
+		return 0, fmt.Errorf("subscription %d not found", delivery.SubscriptionID)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts,
+// deliveries back off like outbox events.
+func Backoff(base time.Duration, attempts int) time.Duration {
+	return outbox.Backoff(base, attempts)
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	return message
+}
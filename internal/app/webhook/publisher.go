@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/outbox"
+	"maqhaa/order_service/internal/app/repository"
+	"strings"
+	"time"
+)
+
+// Publisher is the outbox publisher that turns an order event into one delivery per matching
+// subscription of the client. The deliveries are sent by the Dispatcher.
+type Publisher struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewPublisher(webhookRepo repository.WebhookRepository) *Publisher {
+	return &Publisher{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, message outbox.Message) error {
+	subscriptions, err := p.webhookRepo.GetSubscriptions(ctx, message.ClientID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []*entity.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.IsActive || !Subscribed(subscription, message.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			ClientID:       message.ClientID,
+			EventID:        message.ID,
+			EventType:      message.Type,
+			Payload:        string(payload),
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	return p.webhookRepo.AddDeliveries(ctx, deliveries)
+}
+
+// Subscribed reports whether the subscription receives events of the type.
+func Subscribed(subscription *entity.WebhookSubscription, eventType string) bool {
+	for _, subscribed := range strings.Split(subscription.EventTypes, ",") {
+		if strings.TrimSpace(subscribed) == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -18,6 +18,48 @@ type DatabaseConfig struct {
 	Debug    bool
 }
 
+// OutboxConfig holds the configuration of the order event relay.
+type OutboxConfig struct {
+	Publisher       string
+	WebhookURL      string
+	IntervalSeconds int
+	BatchSize       int
+	MaxAttempts     int
+	BackoffSeconds  int
+}
+
+// WebhookConfig holds the configuration of the webhook dispatcher. AllowPrivateNetworks lets
+// subscriptions target loopback and private addresses, it is meant for local development only.
+type WebhookConfig struct {
+	IntervalSeconds      int
+	MaxAttempts          int
+	BackoffSeconds       int
+	TimeoutSeconds       int
+	AllowPrivateNetworks bool
+}
+
+// BoardConfig holds the configuration of the display boards. AllowedOrigins are the origins,
+// e.g. https://board.example.com, that may open the board WebSocket from a browser.
+// TokenSecret signs the board tokens, all instances must share it.
+type BoardConfig struct {
+	AllowedOrigins  []string
+	TokenSecret     string
+	TokenTTLSeconds int
+}
+
+// EventsConfig holds the configuration of the order event stream. TokenSecret signs the stream
+// tokens, all instances must share it.
+type EventsConfig struct {
+	TokenSecret     string
+	TokenTTLSeconds int
+}
+
+// ScheduleConfig holds the configuration of the scheduled order releaser.
+type ScheduleConfig struct {
+	IntervalSeconds int
+	BatchSize       int
+}
+
 // Config holds the application configuration.
 type Config struct {
 	Database           DatabaseConfig
@@ -26,7 +68,13 @@ type Config struct {
 			Host string
 		}
 	}
-	AppPort string
+	Outbox   OutboxConfig
+	Webhook  WebhookConfig
+	Board    BoardConfig
+	Events   EventsConfig
+	Schedule ScheduleConfig
+	AppPort  string
+	GRPCPort string
 }
 
 // LoadConfig loads configuration from a specified file path, environment variables, and/or config files.
@@ -0,0 +1,247 @@
+// internal/interface/grpc/handler/order_handler.go
+
+package handler
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"maqhaa/order_service/internal/interface/grpc/pb"
+	"net/http"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeLayout is the format of the times in requests and responses, RFC 3339 carries the offset so
+// the time zone of the service does not matter.
+const timeLayout = time.RFC3339
+
+// OrderHandler serves the order API over gRPC using the same service as the REST handlers.
+type OrderHandler struct {
+	pb.UnimplementedOrderServiceServer
+	orderService service.OrderService
+}
+
+// NewOrderHandler creates a new OrderHandler instance.
+func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+func (h *OrderHandler) CreateOrder(ctx context.Context, req *pb.OrderRequest) (*pb.OrderResponse, error) {
+	if req.Token == "" {
+		return nil, StatusError(*service.NewInvalidTokenError())
+	}
+
+	request, appErr := newOrderRequest(req)
+	if appErr != nil {
+		return nil, StatusError(*appErr)
+	}
+
+	order, result := h.orderService.AddOrder(ctx, req.Token, request)
+	return newOrderResponse(order, result)
+}
+
+func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.OrderResponse, error) {
+	if req.Token == "" {
+		return nil, StatusError(*service.NewInvalidTokenError())
+	}
+
+	order, appErr := h.orderService.GetOrder(ctx, req.Token, int(req.OrderId))
+	return newOrderResponse(order, appErr)
+}
+
+func (h *OrderHandler) EditOrder(ctx context.Context, req *pb.OrderRequest) (*pb.OrderResponse, error) {
+	if req.Token == "" {
+		return nil, StatusError(*service.NewInvalidTokenError())
+	}
+
+	request, appErr := newOrderRequest(req)
+	if appErr != nil {
+		return nil, StatusError(*appErr)
+	}
+
+	order, result := h.orderService.EditOrder(ctx, req.Token, request)
+	return newOrderResponse(order, result)
+}
+
+func (h *OrderHandler) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	if req.Token == "" {
+		return nil, StatusError(*service.NewInvalidTokenError())
+	}
+
+	statuses := make([]int, 0, len(req.Statuses))
+	for _, s := range req.Statuses {
+		statuses = append(statuses, int(s))
+	}
+
+	orders, appErr := h.orderService.ListOrders(ctx, req.Token, statuses, req.PhoneNumber)
+	if appErr.Code != service.SuccessError {
+		return nil, StatusError(appErr)
+	}
+
+	response := &pb.ListOrdersResponse{
+		Code:    int32(appErr.Code),
+		Message: appErr.Message,
+		Data:    make([]*pb.OrderData, 0, len(orders)),
+	}
+	for _, order := range orders {
+		response.Data = append(response.Data, newOrderData(order))
+	}
+
+	return response, nil
+}
+
+func (h *OrderHandler) UpdateStatus(ctx context.Context, req *pb.UpdateStatusRequest) (*pb.OrderResponse, error) {
+	if req.Token == "" {
+		return nil, StatusError(*service.NewInvalidTokenError())
+	}
+
+	order, appErr := h.orderService.UpdateStatus(ctx, req.Token, int(req.OrderId), &model.UpdateStatusRequest{
+		Status:        int(req.Status),
+		PaymentMethod: req.PaymentMethod,
+	})
+	return newOrderResponse(order, appErr)
+}
+
+// StatusError converts an AppError to a gRPC status error. The AppError message is kept as the
+// status message and invalid fields are attached as BadRequest details.
+func StatusError(appErr service.AppError) error {
+	st := status.New(StatusCode(appErr.Code), appErr.Message)
+	if len(appErr.Errors) == 0 {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldError := range appErr.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldError.Field,
+			Description: fieldError.Message,
+		})
+	}
+
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// StatusCode maps an AppError code to the gRPC status code of its HTTP status, so both APIs
+// report an error the same way.
+func StatusCode(code int) codes.Code {
+	switch service.HTTPStatus(code) {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
+
+func newOrderRequest(req *pb.OrderRequest) (*model.OrderRequest, *service.AppError) {
+	request := &model.OrderRequest{
+		ID:           uint(req.OrderId),
+		ClientID:     uint(req.ClientId),
+		CustomerName: req.CustomerName,
+		PhoneNumber:  req.PhoneNumber,
+		PromoCode:    req.PromoCode,
+		RedeemPoints: int(req.RedeemPoints),
+		Total:        req.Total,
+	}
+
+	for _, detail := range req.Orders {
+		request.Orders = append(request.Orders, model.OrderDetail{
+			ProductID: uint(detail.ProductId),
+			Price:     detail.Price,
+			Quantity:  int(detail.Quantity),
+			Discount:  detail.Discount,
+			Total:     detail.Total,
+		})
+	}
+
+	if req.ScheduledAt != "" {
+		scheduledAt, err := time.Parse(timeLayout, req.ScheduledAt)
+		if err != nil {
+			return nil, service.NewInvalidRequestError("scheduled_at")
+		}
+		request.ScheduledAt = &scheduledAt
+	}
+
+	return request, nil
+}
+
+func newOrderResponse(order *entity.Order, appErr service.AppError) (*pb.OrderResponse, error) {
+	if appErr.Code != service.SuccessError {
+		return nil, StatusError(appErr)
+	}
+
+	return &pb.OrderResponse{
+		Code:    int32(appErr.Code),
+		Message: appErr.Message,
+		Data:    newOrderData(order),
+	}, nil
+}
+
+func newOrderData(order *entity.Order) *pb.OrderData {
+	data := &pb.OrderData{
+		Id:                 uint32(order.ID),
+		OrderNumber:        order.OrderNumber,
+		ClientId:           uint32(order.ClientID),
+		QueueNumber:        int32(order.QueueNumber),
+		CustomerName:       order.CustomerName,
+		PhoneNumber:        order.PhoneNumber,
+		Currency:           order.CurrencyCode,
+		PromoCode:          order.PromoCode,
+		Subtotal:           order.Subtotal,
+		PromoDiscount:      order.PromoDiscount,
+		ServiceCharge:      order.ServiceCharge,
+		Tax:                order.Tax,
+		RoundingAdjustment: order.RoundingAdjustment,
+		Total:              order.Total,
+		PointsRedeemed:     int32(order.PointsRedeemed),
+		PointsAmount:       order.PointsAmount,
+		Status:             int32(order.Status),
+		StatusText:         model.OrderStatusText(order.Status),
+	}
+
+	if !order.CreatedAt.IsZero() {
+		data.CreatedAt = order.CreatedAt.Format(timeLayout)
+	}
+	if !order.UpdatedAt.IsZero() {
+		data.UpdatedAt = order.UpdatedAt.Format(timeLayout)
+	}
+	if order.ScheduledAt != nil {
+		data.ScheduledAt = order.ScheduledAt.Format(timeLayout)
+	}
+	if order.ReleasedAt != nil {
+		data.ReleasedAt = order.ReleasedAt.Format(timeLayout)
+	}
+
+	for _, detail := range order.OrderDetails {
+		data.Details = append(data.Details, &pb.OrderDetailData{
+			Id:         uint32(detail.ID),
+			ProductId:  uint32(detail.ProductID),
+			Price:      detail.Price,
+			Quantity:   int32(detail.Quantity),
+			Discount:   detail.Discount,
+			Total:      detail.Total,
+			PrepStatus: int32(detail.PrepStatus),
+		})
+	}
+
+	return data
+}
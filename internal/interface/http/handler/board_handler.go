@@ -0,0 +1,219 @@
+// internal/handler/board_handler.go
+
+package handler
+
+import (
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	boardWriteWait  = 10 * time.Second
+	boardPongWait   = 60 * time.Second
+	boardPingPeriod = boardPongWait * 9 / 10
+)
+
+// BoardHandler serves the queue number display boards.
+type BoardHandler struct {
+	boardService service.BoardService
+	upgrader     websocket.Upgrader
+}
+
+// NewBoardHandler creates a new BoardHandler instance. Display boards may be served from other
+// origins, the WebSocket accepts browsers on the same origin and on the allowed origins.
+func NewBoardHandler(boardService service.BoardService, allowedOrigins []string) *BoardHandler {
+	return &BoardHandler{
+		boardService: boardService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				return AllowedOrigin(r, allowedOrigins)
+			},
+		},
+	}
+}
+
+// AllowedOrigin reports whether a WebSocket request may be upgraded. Requests without an Origin
+// header do not come from a browser and are allowed, the token authorizes them.
+func AllowedOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBoardHandler handles the HTTP request for the current state of the display board.
+func (h *BoardHandler) GetBoardHandler(w http.ResponseWriter, r *http.Request) {
+	var boardResponse model.QueueBoardResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	board, appErr := h.boardService.GetBoard(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	boardResponse = model.QueueBoardResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	boardResponse.Data = &struct {
+		Board *model.QueueBoard `json:"board,omitempty"`
+	}{
+		Board: board,
+	}
+
+	sendJSONResponse(w, boardResponse, http.StatusOK)
+}
+
+// IssueBoardTokenHandler handles the HTTP request for a board token, which display boards use to
+// open the WebSocket without the client token.
+func (h *BoardHandler) IssueBoardTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var tokenResponse model.BoardTokenResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	boardToken, appErr := h.boardService.IssueBoardToken(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	tokenResponse = model.BoardTokenResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	tokenResponse.Data = &struct {
+		BoardToken *model.BoardToken `json:"board_token,omitempty"`
+	}{
+		BoardToken: boardToken,
+	}
+
+	sendJSONResponse(w, tokenResponse, http.StatusOK)
+}
+
+// BoardSocketHandler upgrades the request to a WebSocket and pushes the queue numbers that are
+// now served or ready for pickup. A snapshot of the board is sent first on every connection.
+// Browsers can not set headers on a WebSocket, they pass a board token as query parameter
+// instead of the client token.
+func (h *BoardHandler) BoardSocketHandler(w http.ResponseWriter, r *http.Request) {
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+	boardToken := r.URL.Query().Get("board_token")
+
+	if token == "" && boardToken == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	subscribe := h.boardService.Subscribe
+	if token == "" {
+		token = boardToken
+		subscribe = h.boardService.SubscribeWithBoardToken
+	}
+
+	subscription, board, appErr := subscribe(r.Context(), token)
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+	defer subscription.Close()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Errorf("Error upgrade board connection  %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	// Boards only listen, reading is needed to process pongs and notice closed connections
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(boardPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(boardPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := writeBoardMessage(conn, &model.BoardMessage{Type: model.BoardMessageSnapshot, Board: board}); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(boardPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(boardWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case e, ok := <-subscription.Events:
+			if !ok {
+				// The subscriber fell behind, the board reconnects and receives a new snapshot
+				logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Board subscriber dropped")
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, ""))
+				return
+			}
+			message := service.BoardMessageFromEvent(e)
+			if message == nil {
+				continue
+			}
+			if err := writeBoardMessage(conn, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeBoardMessage(conn *websocket.Conn, message *model.BoardMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(boardWriteWait))
+	return conn.WriteJSON(message)
+}
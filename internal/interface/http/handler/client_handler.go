@@ -0,0 +1,323 @@
+// internal/handler/client_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ClientHandler handles HTTP requests related to client settings.
+type ClientHandler struct {
+	clientService service.ClientService
+}
+
+// NewClientHandler creates a new ClientHandler instance.
+func NewClientHandler(clientService service.ClientService) *ClientHandler {
+	return &ClientHandler{
+		clientService: clientService,
+	}
+}
+
+// GetSettingHandler handles the HTTP request for reading the client setting.
+func (h *ClientHandler) GetSettingHandler(w http.ResponseWriter, r *http.Request) {
+	var settingResponse model.ClientSettingResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	setting, appErr := h.clientService.GetSetting(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	settingResponse = model.ClientSettingResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	settingResponse.Data = &struct {
+		Setting *entity.ClientSetting `json:"setting,omitempty"`
+	}{
+		Setting: setting,
+	}
+
+	sendJSONResponse(w, settingResponse, http.StatusOK)
+}
+
+// UpdateSettingHandler handles the HTTP request for updating the client setting.
+func (h *ClientHandler) UpdateSettingHandler(w http.ResponseWriter, r *http.Request) {
+	var settingRequest model.ClientSettingRequest
+	var settingResponse model.ClientSettingResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&settingRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	setting, appErr := h.clientService.UpdateSetting(r.Context(), token, &settingRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	settingResponse = model.ClientSettingResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	settingResponse.Data = &struct {
+		Setting *entity.ClientSetting `json:"setting,omitempty"`
+	}{
+		Setting: setting,
+	}
+
+	sendJSONResponse(w, settingResponse, http.StatusOK)
+}
+
+// GetOpeningHoursHandler handles the HTTP request for reading the opening hours of the client.
+func (h *ClientHandler) GetOpeningHoursHandler(w http.ResponseWriter, r *http.Request) {
+	var hoursResponse model.OpeningHoursResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	hours, appErr := h.clientService.GetOpeningHours(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	hoursResponse = model.OpeningHoursResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	hoursResponse.Data = &struct {
+		Hours []*entity.ClientOpeningHour `json:"hours"`
+	}{
+		Hours: hours,
+	}
+
+	sendJSONResponse(w, hoursResponse, http.StatusOK)
+}
+
+// UpdateOpeningHoursHandler handles the HTTP request for replacing the opening hours of the client.
+func (h *ClientHandler) UpdateOpeningHoursHandler(w http.ResponseWriter, r *http.Request) {
+	var hoursRequest model.OpeningHoursRequest
+	var hoursResponse model.OpeningHoursResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&hoursRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	hours, appErr := h.clientService.UpdateOpeningHours(r.Context(), token, &hoursRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	hoursResponse = model.OpeningHoursResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	hoursResponse.Data = &struct {
+		Hours []*entity.ClientOpeningHour `json:"hours"`
+	}{
+		Hours: hours,
+	}
+
+	sendJSONResponse(w, hoursResponse, http.StatusOK)
+}
+
+// GetHolidaysHandler handles the HTTP request for listing the upcoming holidays of the client.
+func (h *ClientHandler) GetHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	var holidaysResponse model.ListHolidayResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	holidays, appErr := h.clientService.GetHolidays(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	holidaysResponse = model.ListHolidayResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	holidaysResponse.Data = &struct {
+		Holidays []*entity.ClientHoliday `json:"holidays"`
+	}{
+		Holidays: holidays,
+	}
+
+	sendJSONResponse(w, holidaysResponse, http.StatusOK)
+}
+
+// AddHolidayHandler handles the HTTP request for adding a holiday exception to the opening hours.
+func (h *ClientHandler) AddHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	var holidayRequest model.HolidayRequest
+	var holidayResponse model.HolidayResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&holidayRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	holiday, appErr := h.clientService.AddHoliday(r.Context(), token, &holidayRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	holidayResponse = model.HolidayResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	holidayResponse.Data = &struct {
+		Holiday *entity.ClientHoliday `json:"holiday,omitempty"`
+	}{
+		Holiday: holiday,
+	}
+
+	sendJSONResponse(w, holidayResponse, http.StatusOK)
+}
+
+// DeleteHolidayHandler handles the HTTP request for removing a holiday exception.
+func (h *ClientHandler) DeleteHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	holidayID, err := strconv.Atoi(vars["holidayID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewHolidayNotFoundError())
+		return
+	}
+
+	appErr := h.clientService.DeleteHoliday(r.Context(), token, uint(holidayID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	sendJSONResponse(w, model.NewHTTPResponse(appErr.Code, appErr.Message, nil), http.StatusOK)
+}
+
+// PauseOrdersHandler handles the HTTP request for pausing or resuming new orders.
+func (h *ClientHandler) PauseOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	var pauseRequest model.PauseRequest
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&pauseRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	state, appErr := h.clientService.PauseOrders(r.Context(), token, &pauseRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	sendJSONResponse(w, newStoreStateResponse(state, appErr), http.StatusOK)
+}
+
+// GetStoreStateHandler handles the HTTP request for reading whether the client accepts orders now.
+func (h *ClientHandler) GetStoreStateHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	state, appErr := h.clientService.GetStoreState(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	sendJSONResponse(w, newStoreStateResponse(state, appErr), http.StatusOK)
+}
+
+func newStoreStateResponse(state *model.StoreState, appErr service.AppError) model.StoreStateResponse {
+	stateResponse := model.StoreStateResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	stateResponse.Data = &struct {
+		State *model.StoreState `json:"state,omitempty"`
+	}{
+		State: state,
+	}
+
+	return stateResponse
+}
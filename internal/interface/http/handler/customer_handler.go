@@ -0,0 +1,168 @@
+// internal/handler/customer_handler.go
+
+package handler
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// CustomerHandler handles HTTP requests of the customer directory.
+type CustomerHandler struct {
+	customerService service.CustomerService
+}
+
+// NewCustomerHandler creates a new CustomerHandler instance.
+func NewCustomerHandler(customerService service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+// LookupCustomerHandler handles the HTTP request for looking up a customer by phone number.
+func (h *CustomerHandler) LookupCustomerHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	profile, appErr := h.customerService.GetCustomerByPhone(r.Context(), token, r.URL.Query().Get("phone"))
+	sendCustomerResponse(w, r, profile, appErr)
+}
+
+// GetCustomerHandler handles the HTTP request for a customer with their lifetime spend and
+// favourite products.
+func (h *CustomerHandler) GetCustomerHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	customerID, err := strconv.Atoi(vars["customerID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewCustomerNotFoundError())
+		return
+	}
+
+	profile, appErr := h.customerService.GetCustomer(r.Context(), token, uint(customerID))
+	sendCustomerResponse(w, r, profile, appErr)
+}
+
+// GetCustomerOrdersHandler handles the HTTP request for the past orders of a customer.
+func (h *CustomerHandler) GetCustomerOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	var ordersResponse model.CustomerOrdersResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	customerID, err := strconv.Atoi(vars["customerID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewCustomerNotFoundError())
+		return
+	}
+
+	limit, offset := 0, 0
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			sendErrorResponse(w, r, *service.NewInvalidRequestError("limit"))
+			return
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil {
+			sendErrorResponse(w, r, *service.NewInvalidRequestError("offset"))
+			return
+		}
+	}
+
+	orders, appErr := h.customerService.GetCustomerOrders(r.Context(), token, uint(customerID), limit, offset)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeOrders(r, orders...)
+
+	ordersResponse = model.CustomerOrdersResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	ordersResponse.Data = &struct {
+		Orders []*entity.Order `json:"orders"`
+	}{
+		Orders: orders,
+	}
+
+	sendJSONResponse(w, ordersResponse, http.StatusOK)
+}
+
+// GetCustomerPointsHandler handles the HTTP request for the loyalty points of a customer.
+func (h *CustomerHandler) GetCustomerPointsHandler(w http.ResponseWriter, r *http.Request) {
+	var pointsResponse model.CustomerPointsResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	customerID, err := strconv.Atoi(vars["customerID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewCustomerNotFoundError())
+		return
+	}
+
+	points, appErr := h.customerService.GetCustomerPoints(r.Context(), token, uint(customerID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	pointsResponse = model.CustomerPointsResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	pointsResponse.Data = &struct {
+		Points *model.CustomerPoints `json:"points,omitempty"`
+	}{
+		Points: points,
+	}
+
+	sendJSONResponse(w, pointsResponse, http.StatusOK)
+}
+
+func sendCustomerResponse(w http.ResponseWriter, r *http.Request, profile *model.CustomerProfile, appErr service.AppError) {
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	customerResponse := model.CustomerResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	customerResponse.Data = &struct {
+		Profile *model.CustomerProfile `json:"profile,omitempty"`
+	}{
+		Profile: profile,
+	}
+
+	sendJSONResponse(w, customerResponse, http.StatusOK)
+}
@@ -0,0 +1,148 @@
+// internal/handler/event_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatInterval keeps idle connections open through proxies.
+const heartbeatInterval = 15 * time.Second
+
+// EventHandler streams order events to front-of-house screens using Server-Sent Events.
+type EventHandler struct {
+	eventService service.EventService
+}
+
+// NewEventHandler creates a new EventHandler instance.
+func NewEventHandler(eventService service.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// IssueStreamTokenHandler handles the HTTP request for a stream token, which browsers use to open
+// the event stream without the client token.
+func (h *EventHandler) IssueStreamTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var tokenResponse model.StreamTokenResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	streamToken, appErr := h.eventService.IssueStreamToken(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	tokenResponse = model.StreamTokenResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	tokenResponse.Data = &struct {
+		StreamToken *model.StreamToken `json:"stream_token,omitempty"`
+	}{
+		StreamToken: streamToken,
+	}
+
+	sendJSONResponse(w, tokenResponse, http.StatusOK)
+}
+
+// StreamOrderEventsHandler handles the SSE stream of order events. Browsers can not set headers
+// on an EventSource, they pass a stream token as query parameter instead of the client token.
+// Reconnecting clients send Last-Event-ID and receive the events they missed first, or a resync
+// event when they can not be replayed.
+func (h *EventHandler) StreamOrderEventsHandler(w http.ResponseWriter, r *http.Request) {
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+	streamToken := r.URL.Query().Get("stream_token")
+
+	if token == "" && streamToken == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	subscribe := h.eventService.Subscribe
+	if token == "" {
+		token = streamToken
+		subscribe = h.eventService.SubscribeWithStreamToken
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendErrorResponse(w, r, *service.NewGeneralSystemError())
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+
+	subscription, replay, appErr := subscribe(r.Context(), token, lastEventID)
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for _, e := range replay {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-subscription.Events:
+			if !ok {
+				// The subscriber fell behind, the client reconnects and replays from its last event
+				logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Order event subscriber dropped")
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
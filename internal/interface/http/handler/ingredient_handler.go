@@ -0,0 +1,197 @@
+// internal/handler/ingredient_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// IngredientHandler handles HTTP requests of ingredients and product recipes.
+type IngredientHandler struct {
+	ingredientService service.IngredientService
+}
+
+// NewIngredientHandler creates a new IngredientHandler instance.
+func NewIngredientHandler(ingredientService service.IngredientService) *IngredientHandler {
+	return &IngredientHandler{
+		ingredientService: ingredientService,
+	}
+}
+
+// GetIngredientsHandler handles the HTTP request for listing the ingredients of the client.
+func (h *IngredientHandler) GetIngredientsHandler(w http.ResponseWriter, r *http.Request) {
+	var ingredientsResponse model.ListIngredientResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	ingredients, appErr := h.ingredientService.GetIngredients(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	ingredientsResponse = model.ListIngredientResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	ingredientsResponse.Data = &struct {
+		Ingredients []*entity.Ingredient `json:"ingredients"`
+	}{
+		Ingredients: ingredients,
+	}
+
+	sendJSONResponse(w, ingredientsResponse, http.StatusOK)
+}
+
+// CreateIngredientHandler handles the HTTP request for adding an ingredient.
+func (h *IngredientHandler) CreateIngredientHandler(w http.ResponseWriter, r *http.Request) {
+	var ingredientRequest model.IngredientRequest
+	var ingredientResponse model.IngredientResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&ingredientRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	ingredient, appErr := h.ingredientService.AddIngredient(r.Context(), token, &ingredientRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	ingredientResponse = model.IngredientResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	ingredientResponse.Data = &struct {
+		Ingredient *entity.Ingredient `json:"ingredient,omitempty"`
+	}{
+		Ingredient: ingredient,
+	}
+
+	sendJSONResponse(w, ingredientResponse, http.StatusOK)
+}
+
+// GetRecipeHandler handles the HTTP request for the recipe of a product.
+func (h *IngredientHandler) GetRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	productID, err := strconv.Atoi(vars["productID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewProductNotFoundError())
+		return
+	}
+
+	items, appErr := h.ingredientService.GetRecipe(r.Context(), token, uint(productID))
+	sendRecipeResponse(w, r, items, appErr)
+}
+
+// UpdateRecipeHandler handles the HTTP request for replacing the recipe of a product.
+func (h *IngredientHandler) UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	var recipeRequest model.RecipeRequest
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	productID, err := strconv.Atoi(vars["productID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewProductNotFoundError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&recipeRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	items, appErr := h.ingredientService.UpdateRecipe(r.Context(), token, uint(productID), &recipeRequest)
+	sendRecipeResponse(w, r, items, appErr)
+}
+
+// GetConsumptionReportHandler handles the HTTP request for the ingredients used per day.
+func (h *IngredientHandler) GetConsumptionReportHandler(w http.ResponseWriter, r *http.Request) {
+	var reportResponse model.ConsumptionReportResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+	report, appErr := h.ingredientService.GetConsumptionReport(r.Context(), token, query.Get("from"), query.Get("to"))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	reportResponse = model.ConsumptionReportResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	reportResponse.Data = &struct {
+		Report *model.ConsumptionReport `json:"report,omitempty"`
+	}{
+		Report: report,
+	}
+
+	sendJSONResponse(w, reportResponse, http.StatusOK)
+}
+
+func sendRecipeResponse(w http.ResponseWriter, r *http.Request, items []*entity.RecipeItem, appErr service.AppError) {
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	recipeResponse := model.RecipeResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	recipeResponse.Data = &struct {
+		Items []*entity.RecipeItem `json:"items"`
+	}{
+		Items: items,
+	}
+
+	sendJSONResponse(w, recipeResponse, http.StatusOK)
+}
@@ -0,0 +1,246 @@
+// internal/handler/kitchen_handler.go
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// KitchenHandler handles HTTP requests of the kitchen display.
+type KitchenHandler struct {
+	kitchenService service.KitchenService
+}
+
+// NewKitchenHandler creates a new KitchenHandler instance.
+func NewKitchenHandler(kitchenService service.KitchenService) *KitchenHandler {
+	return &KitchenHandler{
+		kitchenService: kitchenService,
+	}
+}
+
+// GetQueueHandler handles the HTTP request for the active orders grouped by status.
+func (h *KitchenHandler) GetQueueHandler(w http.ResponseWriter, r *http.Request) {
+	var queueResponse model.KitchenQueueResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	queue, appErr := h.kitchenService.GetQueue(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	lang := i18n.FromContext(r.Context())
+	for i := range queue {
+		queue[i].StatusText = i18n.StatusText(lang, queue[i].Status)
+		localizeOrders(r, queue[i].Orders...)
+	}
+
+	queueResponse = model.KitchenQueueResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	queueResponse.Data = &struct {
+		Queue []model.KitchenQueueGroup `json:"queue"`
+	}{
+		Queue: queue,
+	}
+
+	sendJSONResponse(w, queueResponse, http.StatusOK)
+}
+
+// StartOrderDetailHandler handles the HTTP request for marking an order line as started.
+func (h *KitchenHandler) StartOrderDetailHandler(w http.ResponseWriter, r *http.Request) {
+	h.updatePrepStatus(w, r, h.kitchenService.StartOrderDetail)
+}
+
+// ReadyOrderDetailHandler handles the HTTP request for marking an order line as ready.
+func (h *KitchenHandler) ReadyOrderDetailHandler(w http.ResponseWriter, r *http.Request) {
+	h.updatePrepStatus(w, r, h.kitchenService.ReadyOrderDetail)
+}
+
+func (h *KitchenHandler) updatePrepStatus(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, token string, detailID uint) (*entity.Order, service.AppError)) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	detailID, err := strconv.Atoi(vars["detailID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewOrderDetailNotFoundError())
+		return
+	}
+
+	order, appErr := update(r.Context(), token, uint(detailID))
+	sendOrderResponse(w, r, order, appErr)
+}
+
+// CreateStationHandler handles the HTTP request for creating a kitchen station.
+func (h *KitchenHandler) CreateStationHandler(w http.ResponseWriter, r *http.Request) {
+	var stationRequest model.KitchenStationRequest
+	var stationResponse model.KitchenStationResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&stationRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	station, appErr := h.kitchenService.AddStation(r.Context(), token, &stationRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	stationResponse = model.KitchenStationResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	stationResponse.Data = &struct {
+		Station *entity.KitchenStation `json:"station,omitempty"`
+	}{
+		Station: station,
+	}
+
+	sendJSONResponse(w, stationResponse, http.StatusOK)
+}
+
+// GetStationsHandler handles the HTTP request for listing the kitchen stations.
+func (h *KitchenHandler) GetStationsHandler(w http.ResponseWriter, r *http.Request) {
+	var stationResponse model.ListKitchenStationResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	stations, appErr := h.kitchenService.GetStations(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	stationResponse = model.ListKitchenStationResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	stationResponse.Data = &struct {
+		Stations []*entity.KitchenStation `json:"stations"`
+	}{
+		Stations: stations,
+	}
+
+	sendJSONResponse(w, stationResponse, http.StatusOK)
+}
+
+// GetStationQueueHandler handles the HTTP request for the open tickets of a station.
+func (h *KitchenHandler) GetStationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	var ticketResponse model.KitchenTicketResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	stationID, err := strconv.Atoi(vars["stationID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewInvalidRequestError("station id"))
+		return
+	}
+
+	tickets, appErr := h.kitchenService.GetStationTickets(r.Context(), token, uint(stationID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	for _, ticket := range tickets {
+		localizeOrders(r, ticket.Order)
+	}
+
+	ticketResponse = model.KitchenTicketResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	ticketResponse.Data = &struct {
+		Tickets []*entity.KitchenTicket `json:"tickets"`
+	}{
+		Tickets: tickets,
+	}
+
+	sendJSONResponse(w, ticketResponse, http.StatusOK)
+}
+
+// BumpTicketHandler handles the HTTP request for bumping a station ticket, which marks all
+// of its lines as ready.
+func (h *KitchenHandler) BumpTicketHandler(w http.ResponseWriter, r *http.Request) {
+	var ticketResponse model.KitchenTicketResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	ticketID, err := strconv.Atoi(vars["ticketID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewKitchenTicketNotFoundError())
+		return
+	}
+
+	ticket, appErr := h.kitchenService.BumpTicket(r.Context(), token, uint(ticketID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeOrders(r, ticket.Order)
+
+	ticketResponse = model.KitchenTicketResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	ticketResponse.Data = &struct {
+		Tickets []*entity.KitchenTicket `json:"tickets"`
+	}{
+		Tickets: []*entity.KitchenTicket{ticket},
+	}
+
+	sendJSONResponse(w, ticketResponse, http.StatusOK)
+}
@@ -0,0 +1,90 @@
+// internal/handler/language_middleware.go
+
+package handler
+
+import (
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// languageCacheTTL is how long the language of a client setting is reused before it is read again.
+const languageCacheTTL = time.Minute
+
+type cachedLanguage struct {
+	lang      string
+	expiresAt time.Time
+}
+
+// LanguageMiddleware selects the language of the messages of a request. A supported
+// Accept-Language wins, otherwise the language of the client setting is used and the default
+// language when the client has none.
+type LanguageMiddleware struct {
+	clientService service.ClientService
+
+	mu    sync.Mutex
+	cache map[string]cachedLanguage
+}
+
+// NewLanguageMiddleware creates a new LanguageMiddleware instance.
+func NewLanguageMiddleware(clientService service.ClientService) *LanguageMiddleware {
+	return &LanguageMiddleware{
+		clientService: clientService,
+		cache:         make(map[string]cachedLanguage),
+	}
+}
+
+// Middleware stores the language of the request in its context.
+func (m *LanguageMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		if lang == "" {
+			lang = m.clientLanguage(r)
+		}
+
+		if lang != "" {
+			r = r.WithContext(i18n.WithLanguage(r.Context(), lang))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *LanguageMiddleware) clientLanguage(r *http.Request) string {
+	token := r.Header.Get("Token")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		return ""
+	}
+
+	now := time.Now()
+
+	m.mu.Lock()
+	cached, ok := m.cache[token]
+	m.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.lang
+	}
+
+	setting, appErr := m.clientService.GetSetting(r.Context(), token)
+	if appErr.Code != service.SuccessError {
+		return ""
+	}
+
+	lang := i18n.Normalize(setting.Language)
+
+	m.mu.Lock()
+	for key, entry := range m.cache {
+		if now.After(entry.expiresAt) {
+			delete(m.cache, key)
+		}
+	}
+	m.cache[token] = cachedLanguage{lang: lang, expiresAt: now.Add(languageCacheTTL)}
+	m.mu.Unlock()
+
+	return lang
+}
@@ -0,0 +1,30 @@
+// internal/handler/openapi_handler.go
+
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec documents every route registered in cmd/main.go. Keep it in sync when routes,
+// request or response models or AppError codes change, the OpenAPI unit test checks the routes
+// and the error codes.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPIHandler serves the OpenAPI document of the service.
+type OpenAPIHandler struct{}
+
+// NewOpenAPIHandler creates a new OpenAPIHandler instance.
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// GetSpecHandler handles the HTTP request for the OpenAPI document.
+func (h *OpenAPIHandler) GetSpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
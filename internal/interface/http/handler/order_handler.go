@@ -32,42 +32,33 @@ func NewOrderHandler(orderService service.OrderService) *OrderHandler {
 func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
 	var orderRequest model.OrderRequest
 	var orderResponse model.OrderResponse
-	var appError service.AppError
 
 	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
 	token := r.Header.Get("Token")
 
 	if token == "" {
-		appError = *service.NewInvalidTokenError()
-		response := model.NewHTTPResponse(appError.Code, appError.Message, nil)
-		sendJSONResponse(w, response, appError.Code)
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
 		return
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&orderRequest); err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
 
-		appError = *service.NewInvalidFormatError()
-		orderResponse = model.OrderResponse{
-			HTTPResponse: *model.NewHTTPResponse(appError.Code, appError.Message, nil),
-		}
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
 		return
 	}
 
 	// Call the order service to add the order
 	order, appErr := h.orderService.AddOrder(r.Context(), token, &orderRequest)
 
-	orderResponse = model.OrderResponse{
-		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
-	}
 	if appErr.Code != service.SuccessError {
-		// Handle application-specific errors
-		orderResponse.Data = nil
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, appErr)
 		return
 	}
 
+	orderResponse = model.OrderResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
 	orderResponse.Data = &struct {
 		OrderID     uint   `json:"order_id"`
 		OrderNumber string `json:"order_number"`
@@ -76,66 +67,134 @@ func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request
 		OrderNumber: order.OrderNumber,
 	}
 
-	sendJSONResponse(w, orderResponse, appError.Code)
+	sendJSONResponse(w, orderResponse, http.StatusOK)
 }
 
 func (h *OrderHandler) GetOrderHandler(w http.ResponseWriter, r *http.Request) {
 	var orderResponse model.GetOrderResponse
-	var appError service.AppError
 
 	token := r.Header.Get("Token")
 
 	if token == "" {
-		appError = *service.NewInvalidTokenError()
-		response := model.NewHTTPResponse(appError.Code, appError.Message, nil)
-		sendJSONResponse(w, response, appError.Code)
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
 		return
 	}
 	vars := mux.Vars(r)
 	orderID, err := strconv.Atoi(vars["orderID"])
 
 	if err != nil {
-		appError = *service.NewOrderNotFoundError()
-		response := model.NewHTTPResponse(appError.Code, appError.Message, nil)
-		sendJSONResponse(w, response, appError.Code)
+		sendErrorResponse(w, r, *service.NewOrderNotFoundError())
 		return
 	}
 
 	// Call the order service to add the order
 	order, appErr := h.orderService.GetOrder(r.Context(), token, orderID)
 
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeOrders(r, order)
+
 	orderResponse = model.GetOrderResponse{
 		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
 	}
+	orderResponse.Data = &struct {
+		Order   *entity.Order       `json:"order,omitempty"`
+		Amounts *model.OrderAmounts `json:"amounts,omitempty"`
+	}{
+		Order:   order,
+		Amounts: model.NewOrderAmounts(order),
+	}
+
+	sendJSONResponse(w, orderResponse, http.StatusOK)
+}
+
+// ListOrdersHandler handles the HTTP request for listing orders by status and phone number. The
+// status parameter is a comma separated list of statuses.
+func (h *OrderHandler) ListOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	var orderResponse model.ListOrderResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+
+	statuses, ok := parseStatuses(query.Get("status"))
+	if !ok {
+		sendErrorResponse(w, r, *service.NewInvalidOrderStatusError())
+		return
+	}
+
+	orders, appErr := h.orderService.ListOrders(r.Context(), token, statuses, query.Get("phone"))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeOrders(r, orders...)
+
+	orderResponse = model.ListOrderResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	orderResponse.Data = &struct {
+		Orders []*entity.Order `json:"orders"`
+	}{
+		Orders: orders,
+	}
+
+	sendJSONResponse(w, orderResponse, http.StatusOK)
+}
+
+// GetScheduledOrdersHandler handles the HTTP request for listing the scheduled orders that are not
+// released to the kitchen yet.
+func (h *OrderHandler) GetScheduledOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	var orderResponse model.ListOrderResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	orders, appErr := h.orderService.GetScheduledOrders(r.Context(), token)
+
 	if appErr.Code != service.SuccessError {
-		// Handle application-specific errors
-		orderResponse.Data = nil
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, appErr)
 		return
 	}
 
+	localizeOrders(r, orders...)
+
+	orderResponse = model.ListOrderResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
 	orderResponse.Data = &struct {
-		Order *entity.Order `json:"order,omitempty"`
+		Orders []*entity.Order `json:"orders"`
 	}{
-		Order: order,
+		Orders: orders,
 	}
 
-	sendJSONResponse(w, orderResponse, appError.Code)
+	sendJSONResponse(w, orderResponse, http.StatusOK)
 }
 
 // EditOrderHandler handles the HTTP request for editing an order.
 func (h *OrderHandler) EditOrderHandler(w http.ResponseWriter, r *http.Request) {
 	var orderRequest model.OrderRequest
 	var orderResponse model.OrderResponse
-	var appError service.AppError
 
 	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
 	token := r.Header.Get("Token")
 
 	if token == "" {
-		appError = *service.NewInvalidTokenError()
-		response := model.NewHTTPResponse(appError.Code, appError.Message, nil)
-		sendJSONResponse(w, response, appError.Code)
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
 		return
 	}
 
@@ -143,21 +202,13 @@ func (h *OrderHandler) EditOrderHandler(w http.ResponseWriter, r *http.Request)
 	orderID, err := strconv.Atoi(vars["orderID"])
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid order ID format")
-		appError = *service.NewOrderNotFoundError()
-		orderResponse = model.OrderResponse{
-			HTTPResponse: *model.NewHTTPResponse(appError.Code, appError.Message, nil),
-		}
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, *service.NewOrderNotFoundError())
 		return
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&orderRequest); err != nil {
 		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
-		appError = *service.NewInvalidFormatError()
-		orderResponse = model.OrderResponse{
-			HTTPResponse: *model.NewHTTPResponse(appError.Code, appError.Message, nil),
-		}
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
 		return
 	}
 
@@ -166,16 +217,14 @@ func (h *OrderHandler) EditOrderHandler(w http.ResponseWriter, r *http.Request)
 	// Call the order service to update the order
 	order, appErr := h.orderService.EditOrder(r.Context(), token, &orderRequest)
 
-	orderResponse = model.OrderResponse{
-		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
-	}
 	if appErr.Code != service.SuccessError {
-		// Handle application-specific errors
-		orderResponse.Data = nil
-		sendJSONResponse(w, orderResponse, appError.Code)
+		sendErrorResponse(w, r, appErr)
 		return
 	}
 
+	orderResponse = model.OrderResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
 	orderResponse.Data = &struct {
 		OrderID     uint   `json:"order_id"`
 		OrderNumber string `json:"order_number"`
@@ -184,5 +233,78 @@ func (h *OrderHandler) EditOrderHandler(w http.ResponseWriter, r *http.Request)
 		OrderNumber: order.OrderNumber,
 	}
 
-	sendJSONResponse(w, orderResponse, appErr.Code)
+	sendJSONResponse(w, orderResponse, http.StatusOK)
+}
+
+// UpdateStatusHandler handles the HTTP request for moving an order to the next status.
+func (h *OrderHandler) UpdateStatusHandler(w http.ResponseWriter, r *http.Request) {
+	var statusRequest model.UpdateStatusRequest
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	orderID, err := strconv.Atoi(vars["orderID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewOrderNotFoundError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&statusRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	order, appErr := h.orderService.UpdateStatus(r.Context(), token, orderID, &statusRequest)
+	sendOrderResponse(w, r, order, appErr)
+}
+
+// CancelOrderHandler handles the HTTP request for cancelling an order.
+func (h *OrderHandler) CancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	orderID, err := strconv.Atoi(vars["orderID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewOrderNotFoundError())
+		return
+	}
+
+	order, appErr := h.orderService.CancelOrder(r.Context(), token, orderID)
+	sendOrderResponse(w, r, order, appErr)
+}
+
+// sendOrderResponse sends an order with its formatted amounts, or the error when appErr is not
+// a success.
+func sendOrderResponse(w http.ResponseWriter, r *http.Request, order *entity.Order, appErr service.AppError) {
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeOrders(r, order)
+
+	orderResponse := model.GetOrderResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	orderResponse.Data = &struct {
+		Order   *entity.Order       `json:"order,omitempty"`
+		Amounts *model.OrderAmounts `json:"amounts,omitempty"`
+	}{
+		Order:   order,
+		Amounts: model.NewOrderAmounts(order),
+	}
+
+	sendJSONResponse(w, orderResponse, http.StatusOK)
 }
@@ -0,0 +1,96 @@
+// internal/handler/promotion_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PromotionHandler handles HTTP requests related to promotions and promo codes.
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+// NewPromotionHandler creates a new PromotionHandler instance.
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// CreatePromotionHandler handles the HTTP request for creating a promotion.
+func (h *PromotionHandler) CreatePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	var promotionRequest model.PromotionRequest
+	var promotionResponse model.PromotionResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&promotionRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	promotion, appErr := h.promotionService.AddPromotion(r.Context(), token, &promotionRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	promotionResponse = model.PromotionResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	promotionResponse.Data = &struct {
+		Promotion *entity.Promotion `json:"promotion,omitempty"`
+	}{
+		Promotion: promotion,
+	}
+
+	sendJSONResponse(w, promotionResponse, http.StatusOK)
+}
+
+// GetPromotionsHandler handles the HTTP request for listing the promotions of a client.
+func (h *PromotionHandler) GetPromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	var promotionResponse model.ListPromotionResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	promotions, appErr := h.promotionService.GetPromotions(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	promotionResponse = model.ListPromotionResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	promotionResponse.Data = &struct {
+		Promotions []*entity.Promotion `json:"promotions"`
+	}{
+		Promotions: promotions,
+	}
+
+	sendJSONResponse(w, promotionResponse, http.StatusOK)
+}
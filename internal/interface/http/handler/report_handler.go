@@ -0,0 +1,164 @@
+// internal/handler/report_handler.go
+
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+)
+
+// ReportHandler handles HTTP requests of the sales reports.
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+// NewReportHandler creates a new ReportHandler instance.
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetDailyReportHandler handles the HTTP request for the sales summary per business day.
+func (h *ReportHandler) GetDailyReportHandler(w http.ResponseWriter, r *http.Request) {
+	var reportResponse model.DailyReportResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+	report, appErr := h.reportService.GetDailyReport(r.Context(), token, query.Get("from"), query.Get("to"))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	localizeDailySales(r, report.Days...)
+	localizeDailySales(r, report.Summary)
+
+	reportResponse = model.DailyReportResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	reportResponse.Data = &struct {
+		Report *model.DailyReport `json:"report,omitempty"`
+	}{
+		Report: report,
+	}
+
+	sendJSONResponse(w, reportResponse, http.StatusOK)
+}
+
+// GetProductRankingHandler handles the HTTP request for the best selling products. The ranking is
+// sent as a CSV file when the format query parameter is csv.
+func (h *ReportHandler) GetProductRankingHandler(w http.ResponseWriter, r *http.Request) {
+	var rankingResponse model.ProductRankingResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		sendErrorResponse(w, r, *service.NewInvalidRequestError("format"))
+		return
+	}
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			sendErrorResponse(w, r, *service.NewInvalidRequestError("limit"))
+			return
+		}
+	}
+
+	ranking, appErr := h.reportService.GetProductRanking(r.Context(), token, query.Get("from"), query.Get("to"), query.Get("sort"), limit)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"product-ranking-%s-%s.csv\"", ranking.From, ranking.To))
+		w.WriteHeader(http.StatusOK)
+		csv.NewWriter(w).WriteAll(ranking.CSVRecords())
+		return
+	}
+
+	rankingResponse = model.ProductRankingResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	rankingResponse.Data = &struct {
+		Ranking *model.ProductRanking `json:"ranking,omitempty"`
+	}{
+		Ranking: ranking,
+	}
+
+	sendJSONResponse(w, rankingResponse, http.StatusOK)
+}
+
+// GetHourlyHeatmapHandler handles the HTTP request for the orders and sales by weekday and hour.
+// The status parameter is a comma separated list of statuses.
+func (h *ReportHandler) GetHourlyHeatmapHandler(w http.ResponseWriter, r *http.Request) {
+	var heatmapResponse model.HourlyHeatmapResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+
+	statuses, ok := parseStatuses(query.Get("status"))
+	if !ok {
+		sendErrorResponse(w, r, *service.NewInvalidOrderStatusError())
+		return
+	}
+
+	heatmap, appErr := h.reportService.GetHourlyHeatmap(r.Context(), token, query.Get("from"), query.Get("to"), statuses)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	heatmapResponse = model.HourlyHeatmapResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	heatmapResponse.Data = &struct {
+		Heatmap *model.HourlyHeatmap `json:"heatmap,omitempty"`
+	}{
+		Heatmap: heatmap,
+	}
+
+	sendJSONResponse(w, heatmapResponse, http.StatusOK)
+}
+
+// localizeDailySales sets the status texts of the breakdown by status in the language of the request.
+func localizeDailySales(r *http.Request, days ...*model.DailySales) {
+	lang := i18n.FromContext(r.Context())
+	for _, sales := range days {
+		for i := range sales.Statuses {
+			sales.Statuses[i].StatusText = i18n.StatusText(lang, sales.Statuses[i].Status)
+		}
+	}
+}
@@ -4,33 +4,74 @@ package handler
 
 import (
 	"encoding/json"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // sendJSONResponse sends a JSON-encoded HTTP response.
-func sendJSONResponse(w http.ResponseWriter, response interface{}, errorCode int) {
-	statusCode := http.StatusOK
-	if errorCode > 0 && errorCode < 100 {
-		statusCode = http.StatusInternalServerError
+func sendJSONResponse(w http.ResponseWriter, response interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// sendErrorResponse sends an AppError as RFC 7807 problem details with the HTTP status of the error.
+// The messages are in the language of the request.
+func sendErrorResponse(w http.ResponseWriter, r *http.Request, appErr service.AppError) {
+	lang := i18n.FromContext(r.Context())
+	appErr = appErr.Localize(lang)
+
+	status := appErr.Status
+	if status == 0 {
+		status = service.HTTPStatus(appErr.Code)
 	}
 
-	if errorCode > 99 && errorCode < 200 {
-		statusCode = http.StatusUnauthorized
+	problem := model.ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: r.URL.Path,
+		Code:     appErr.Code,
+		Message:  appErr.Message,
+		Errors:   appErr.Errors,
 	}
 
-	if errorCode > 199 && errorCode < 300 {
-		statusCode = http.StatusBadRequest
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// localizeOrders sets the status text of the orders in the language of the request.
+func localizeOrders(r *http.Request, orders ...*entity.Order) {
+	lang := i18n.FromContext(r.Context())
+	for _, order := range orders {
+		if order != nil {
+			order.StatusText = i18n.StatusText(lang, order.Status)
+		}
 	}
+}
 
-	if errorCode > 299 && errorCode < 400 {
-		statusCode = http.StatusServiceUnavailable
+// parseStatuses parses a comma separated list of order statuses, an empty value has no statuses.
+func parseStatuses(value string) ([]int, bool) {
+	var statuses []int
+	if value == "" {
+		return statuses, true
 	}
 
-	if errorCode > 399 && errorCode < 500 {
-		statusCode = http.StatusInternalServerError
+	for _, part := range strings.Split(value, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, false
+		}
+		statuses = append(statuses, status)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	return statuses, true
 }
@@ -0,0 +1,157 @@
+// internal/handler/shift_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ShiftHandler handles HTTP requests of the cash register shifts.
+type ShiftHandler struct {
+	shiftService service.ShiftService
+}
+
+// NewShiftHandler creates a new ShiftHandler instance.
+func NewShiftHandler(shiftService service.ShiftService) *ShiftHandler {
+	return &ShiftHandler{
+		shiftService: shiftService,
+	}
+}
+
+// OpenShiftHandler handles the HTTP request for opening a shift with the opening cash float.
+func (h *ShiftHandler) OpenShiftHandler(w http.ResponseWriter, r *http.Request) {
+	var shiftRequest model.OpenShiftRequest
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&shiftRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	report, appErr := h.shiftService.OpenShift(r.Context(), token, &shiftRequest)
+	sendShiftReport(w, r, report, appErr)
+}
+
+// GetCurrentShiftHandler handles the HTTP request for the report of the open shift so far.
+func (h *ShiftHandler) GetCurrentShiftHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	report, appErr := h.shiftService.GetCurrentShift(r.Context(), token)
+	sendShiftReport(w, r, report, appErr)
+}
+
+// CloseShiftHandler handles the HTTP request for closing the open shift with the counted cash.
+func (h *ShiftHandler) CloseShiftHandler(w http.ResponseWriter, r *http.Request) {
+	var shiftRequest model.CloseShiftRequest
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&shiftRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	report, appErr := h.shiftService.CloseShift(r.Context(), token, &shiftRequest)
+	sendShiftReport(w, r, report, appErr)
+}
+
+// GetShiftHandler handles the HTTP request for the report of a shift.
+func (h *ShiftHandler) GetShiftHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	shiftID, err := strconv.Atoi(vars["shiftID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewShiftNotFoundError())
+		return
+	}
+
+	report, appErr := h.shiftService.GetShift(r.Context(), token, uint(shiftID))
+	sendShiftReport(w, r, report, appErr)
+}
+
+// GetShiftsHandler handles the HTTP request for listing the shifts of a range of business days.
+func (h *ShiftHandler) GetShiftsHandler(w http.ResponseWriter, r *http.Request) {
+	var shiftsResponse model.ListShiftResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	query := r.URL.Query()
+	shifts, appErr := h.shiftService.GetShifts(r.Context(), token, query.Get("from"), query.Get("to"))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	shiftsResponse = model.ListShiftResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	shiftsResponse.Data = &struct {
+		Shifts []*entity.Shift `json:"shifts"`
+	}{
+		Shifts: shifts,
+	}
+
+	sendJSONResponse(w, shiftsResponse, http.StatusOK)
+}
+
+func sendShiftReport(w http.ResponseWriter, r *http.Request, report *model.ShiftReport, appErr service.AppError) {
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	shiftResponse := model.ShiftResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	shiftResponse.Data = &struct {
+		Report *model.ShiftReport `json:"report,omitempty"`
+	}{
+		Report: report,
+	}
+
+	sendJSONResponse(w, shiftResponse, http.StatusOK)
+}
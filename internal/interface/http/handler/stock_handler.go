@@ -0,0 +1,131 @@
+// internal/handler/stock_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// StockHandler handles HTTP requests of the product stock.
+type StockHandler struct {
+	stockService service.StockService
+}
+
+// NewStockHandler creates a new StockHandler instance.
+func NewStockHandler(stockService service.StockService) *StockHandler {
+	return &StockHandler{
+		stockService: stockService,
+	}
+}
+
+// GetStocksHandler handles the HTTP request for listing the stock of the products of the client.
+func (h *StockHandler) GetStocksHandler(w http.ResponseWriter, r *http.Request) {
+	var stocksResponse model.ListStockResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	stocks, appErr := h.stockService.GetStocks(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	stocksResponse = model.ListStockResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	stocksResponse.Data = &struct {
+		Stocks []*entity.ProductStock `json:"stocks"`
+	}{
+		Stocks: stocks,
+	}
+
+	sendJSONResponse(w, stocksResponse, http.StatusOK)
+}
+
+// SetStockHandler handles the HTTP request for setting the quantity on hand of a product.
+func (h *StockHandler) SetStockHandler(w http.ResponseWriter, r *http.Request) {
+	var stockRequest model.StockRequest
+	var stockResponse model.StockResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	productID, err := strconv.Atoi(vars["productID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewProductNotFoundError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&stockRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	stock, appErr := h.stockService.SetStock(r.Context(), token, uint(productID), &stockRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	stockResponse = model.StockResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	stockResponse.Data = &struct {
+		Stock *entity.ProductStock `json:"stock,omitempty"`
+	}{
+		Stock: stock,
+	}
+
+	sendJSONResponse(w, stockResponse, http.StatusOK)
+}
+
+// DeleteStockHandler handles the HTTP request for no longer tracking the stock of a product.
+func (h *StockHandler) DeleteStockHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	productID, err := strconv.Atoi(vars["productID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewStockNotFoundError())
+		return
+	}
+
+	appErr := h.stockService.DeleteStock(r.Context(), token, uint(productID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	sendJSONResponse(w, model.NewHTTPResponse(appErr.Code, appErr.Message, nil), http.StatusOK)
+}
@@ -0,0 +1,192 @@
+// internal/handler/webhook_handler.go
+
+package handler
+
+import (
+	"encoding/json"
+	"maqhaa/library/logging"
+	"maqhaa/library/middleware"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookHandler handles HTTP requests related to outbound webhooks.
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance.
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhookHandler handles the HTTP request for subscribing a URL to order events.
+func (h *WebhookHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var webhookRequest model.WebhookRequest
+	var webhookResponse model.WebhookResponse
+
+	logID, _ := r.Context().Value(middleware.RequestIDKey).(string)
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&webhookRequest); err != nil {
+		logging.Log.WithFields(logrus.Fields{"request_id": logID}).Info("Invalid request payload")
+
+		sendErrorResponse(w, r, *service.NewInvalidFormatError())
+		return
+	}
+
+	webhook, appErr := h.webhookService.AddWebhook(r.Context(), token, &webhookRequest)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	webhookResponse = model.WebhookResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	webhookResponse.Data = &struct {
+		Webhook *entity.WebhookSubscription `json:"webhook,omitempty"`
+	}{
+		Webhook: webhook,
+	}
+
+	sendJSONResponse(w, webhookResponse, http.StatusOK)
+}
+
+// GetWebhooksHandler handles the HTTP request for listing the webhook subscriptions of a client.
+func (h *WebhookHandler) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	var webhookResponse model.ListWebhookResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	webhooks, appErr := h.webhookService.GetWebhooks(r.Context(), token)
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	webhookResponse = model.ListWebhookResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	webhookResponse.Data = &struct {
+		Webhooks []*entity.WebhookSubscription `json:"webhooks"`
+	}{
+		Webhooks: webhooks,
+	}
+
+	sendJSONResponse(w, webhookResponse, http.StatusOK)
+}
+
+// DeactivateWebhookHandler handles the HTTP request for deactivating a webhook subscription.
+func (h *WebhookHandler) DeactivateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	webhookID, err := strconv.Atoi(vars["webhookID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewWebhookNotFoundError())
+		return
+	}
+
+	appErr := h.webhookService.DeactivateWebhook(r.Context(), token, uint(webhookID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	sendJSONResponse(w, model.NewHTTPResponse(appErr.Code, appErr.Message, nil), http.StatusOK)
+}
+
+// GetDeliveriesHandler handles the HTTP request for listing webhook deliveries. The optional
+// status query parameter filters the list, status=dead returns the dead letter list.
+func (h *WebhookHandler) GetDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	var deliveryResponse model.ListWebhookDeliveryResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	deliveries, appErr := h.webhookService.GetDeliveries(r.Context(), token, r.URL.Query().Get("status"))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	deliveryResponse = model.ListWebhookDeliveryResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	deliveryResponse.Data = &struct {
+		Deliveries []*entity.WebhookDelivery `json:"deliveries"`
+	}{
+		Deliveries: deliveries,
+	}
+
+	sendJSONResponse(w, deliveryResponse, http.StatusOK)
+}
+
+// RedeliverHandler handles the HTTP request for sending a webhook delivery again.
+func (h *WebhookHandler) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	var deliveryResponse model.WebhookDeliveryResponse
+
+	token := r.Header.Get("Token")
+
+	if token == "" {
+		sendErrorResponse(w, r, *service.NewInvalidTokenError())
+		return
+	}
+
+	vars := mux.Vars(r)
+	deliveryID, err := strconv.Atoi(vars["deliveryID"])
+	if err != nil {
+		sendErrorResponse(w, r, *service.NewWebhookDeliveryNotFoundError())
+		return
+	}
+
+	delivery, appErr := h.webhookService.Redeliver(r.Context(), token, uint(deliveryID))
+
+	if appErr.Code != service.SuccessError {
+		sendErrorResponse(w, r, appErr)
+		return
+	}
+
+	deliveryResponse = model.WebhookDeliveryResponse{
+		HTTPResponse: *model.NewHTTPResponse(appErr.Code, appErr.Message, nil),
+	}
+	deliveryResponse.Data = &struct {
+		Delivery *entity.WebhookDelivery `json:"delivery,omitempty"`
+	}{
+		Delivery: delivery,
+	}
+
+	sendJSONResponse(w, deliveryResponse, http.StatusOK)
+}
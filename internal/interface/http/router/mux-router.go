@@ -31,6 +31,9 @@ func (*muxRouter) PUT(uri string, f func(w http.ResponseWriter, r *http.Request)
 func (*muxRouter) DELETE(uri string, f func(w http.ResponseWriter, r *http.Request)) {
 	muxDispatcher.HandleFunc(uri, f).Methods("DELETE")
 }
+func (*muxRouter) USE(middleware func(http.Handler) http.Handler) {
+	muxDispatcher.Use(middleware)
+}
 func (*muxRouter) SERVE(port string) {
 	logging.Log.Infof("Http server listen in port %s", port)
 	//muxDispatcher.Use(apmgorilla.Middleware())
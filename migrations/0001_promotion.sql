@@ -0,0 +1,50 @@
+-- Promotions, promo codes and redemption tracking
+
+CREATE TABLE IF NOT EXISTS `promotion` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `code` varchar(50) NOT NULL DEFAULT '',
+  `name` varchar(100) NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `value` double NOT NULL DEFAULT 0,
+  `max_discount` double NOT NULL DEFAULT 0,
+  `buy_quantity` int NOT NULL DEFAULT 0,
+  `get_quantity` int NOT NULL DEFAULT 0,
+  `min_spend` double NOT NULL DEFAULT 0,
+  `start_at` datetime NULL,
+  `end_at` datetime NULL,
+  `hour_start` tinyint NOT NULL DEFAULT 0,
+  `hour_end` tinyint NOT NULL DEFAULT 0,
+  `usage_limit` int NOT NULL DEFAULT 0,
+  `per_customer_limit` int NOT NULL DEFAULT 0,
+  `auto_apply` tinyint(1) NOT NULL DEFAULT 0,
+  `is_active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_promotion_client_code` (`client_id`, `code`)
+);
+
+CREATE TABLE IF NOT EXISTS `promotion_product` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `promotion_id` int unsigned NOT NULL,
+  `product_id` int unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_promotion_product_promotion` (`promotion_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `promotion_redemption` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `promotion_id` int unsigned NOT NULL,
+  `order_id` int unsigned NOT NULL,
+  `client_id` int unsigned NOT NULL,
+  `phone_number` varchar(30) NOT NULL DEFAULT '',
+  `discount` double NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_promotion_redemption_promotion` (`promotion_id`, `phone_number`),
+  KEY `idx_promotion_redemption_order` (`order_id`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `promo_code` varchar(50) NOT NULL DEFAULT '' AFTER `phone_number`,
+  ADD COLUMN `promo_discount` double NOT NULL DEFAULT 0 AFTER `promo_code`;
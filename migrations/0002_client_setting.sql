@@ -0,0 +1,23 @@
+-- Per client tax and service charge rules, and the price breakdown of orders
+
+CREATE TABLE IF NOT EXISTS `client_setting` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `tax_name` varchar(50) NOT NULL DEFAULT '',
+  `tax_rate` double NOT NULL DEFAULT 0,
+  `tax_inclusive` tinyint(1) NOT NULL DEFAULT 0,
+  `tax_on_service_charge` tinyint(1) NOT NULL DEFAULT 0,
+  `service_charge_rate` double NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_client_setting_client` (`client_id`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `subtotal` double NOT NULL DEFAULT 0 AFTER `promo_code`,
+  ADD COLUMN `service_charge_rate` double NOT NULL DEFAULT 0 AFTER `promo_discount`,
+  ADD COLUMN `service_charge` double NOT NULL DEFAULT 0 AFTER `service_charge_rate`,
+  ADD COLUMN `tax_rate` double NOT NULL DEFAULT 0 AFTER `service_charge`,
+  ADD COLUMN `tax_inclusive` tinyint(1) NOT NULL DEFAULT 0 AFTER `tax_rate`,
+  ADD COLUMN `tax` double NOT NULL DEFAULT 0 AFTER `tax_inclusive`;
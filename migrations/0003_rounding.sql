@@ -0,0 +1,8 @@
+-- Per client rounding of cash totals
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `rounding_mode` varchar(10) NOT NULL DEFAULT 'none' AFTER `service_charge_rate`,
+  ADD COLUMN `rounding_increment` double NOT NULL DEFAULT 0 AFTER `rounding_mode`;
+
+ALTER TABLE `order`
+  ADD COLUMN `rounding_adjustment` double NOT NULL DEFAULT 0 AFTER `tax`;
@@ -0,0 +1,7 @@
+-- Currency of clients and orders
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `currency_code` char(3) NOT NULL DEFAULT 'IDR' AFTER `client_id`;
+
+ALTER TABLE `order`
+  ADD COLUMN `currency_code` char(3) NOT NULL DEFAULT 'IDR' AFTER `phone_number`;
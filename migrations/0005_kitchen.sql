@@ -0,0 +1,12 @@
+-- Preparation tracking for the kitchen display
+
+ALTER TABLE `order_detail`
+  ADD COLUMN `prep_status` tinyint NOT NULL DEFAULT 0,
+  ADD COLUMN `started_at` datetime NULL,
+  ADD COLUMN `ready_at` datetime NULL;
+
+ALTER TABLE `order`
+  ADD COLUMN `processing_at` datetime NULL AFTER `updated_by`,
+  ADD COLUMN `completed_at` datetime NULL AFTER `processing_at`;
+
+CREATE INDEX `idx_order_client_status` ON `order` (`client_id`, `status`);
@@ -0,0 +1,35 @@
+-- Kitchen stations and per station tickets
+
+CREATE TABLE IF NOT EXISTS `kitchen_station` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `is_active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_kitchen_station_client` (`client_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kitchen_station_category` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `station_id` int unsigned NOT NULL,
+  `category_id` int unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_kitchen_station_category_station` (`station_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kitchen_ticket` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` int unsigned NOT NULL,
+  `station_id` int unsigned NOT NULL DEFAULT 0,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `bumped_at` datetime NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_kitchen_ticket_order` (`order_id`),
+  KEY `idx_kitchen_ticket_station_status` (`station_id`, `status`)
+);
+
+ALTER TABLE `order_detail`
+  ADD COLUMN `category_id` int unsigned NOT NULL DEFAULT 0 AFTER `product_id`,
+  ADD COLUMN `station_id` int unsigned NOT NULL DEFAULT 0 AFTER `category_id`;
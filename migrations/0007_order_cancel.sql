@@ -0,0 +1,4 @@
+-- Cancelled orders
+
+ALTER TABLE `order`
+  ADD COLUMN `cancelled_at` datetime NULL AFTER `completed_at`;
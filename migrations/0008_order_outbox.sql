@@ -0,0 +1,15 @@
+-- Transactional outbox of order domain events
+
+CREATE TABLE IF NOT EXISTS `order_outbox` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `order_id` int unsigned NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `attempts` int NOT NULL DEFAULT 0,
+  `last_error` varchar(500) NOT NULL DEFAULT '',
+  `published_at` datetime NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_order_outbox_pending` (`published_at`, `id`)
+);
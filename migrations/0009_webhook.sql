@@ -0,0 +1,34 @@
+-- Outbound webhooks
+
+CREATE TABLE IF NOT EXISTS `webhook_subscription` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `url` varchar(500) NOT NULL,
+  `event_types` varchar(255) NOT NULL,
+  `secret` varchar(100) NOT NULL,
+  `is_active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_webhook_subscription_client` (`client_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_delivery` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `subscription_id` int unsigned NOT NULL,
+  `client_id` int unsigned NOT NULL,
+  `event_id` int unsigned NOT NULL,
+  `event_type` varchar(50) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `attempts` int NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL,
+  `last_status_code` int NOT NULL DEFAULT 0,
+  `last_error` varchar(500) NOT NULL DEFAULT '',
+  `delivered_at` datetime NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_webhook_delivery_event` (`subscription_id`, `event_id`),
+  KEY `idx_webhook_delivery_due` (`status`, `next_attempt_at`),
+  KEY `idx_webhook_delivery_client` (`client_id`, `status`)
+);
@@ -0,0 +1,4 @@
+-- Per client language of messages, used when a request has no supported Accept-Language
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `language` varchar(5) NOT NULL DEFAULT '' AFTER `rounding_increment`;
@@ -0,0 +1,16 @@
+-- Customer directory, orders are linked to a customer by their normalised phone number
+
+CREATE TABLE IF NOT EXISTS `customer` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `phone_number` varchar(32) NOT NULL,
+  `name` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_customer_client_phone` (`client_id`, `phone_number`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `customer_id` int unsigned NULL AFTER `phone_number`,
+  ADD KEY `idx_order_customer` (`customer_id`, `created_at`);
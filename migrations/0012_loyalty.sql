@@ -0,0 +1,26 @@
+-- Loyalty points, earned per spend ratio of the client and redeemed as a payment of an order
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `loyalty_spend_per_point` double NOT NULL DEFAULT 0 AFTER `language`,
+  ADD COLUMN `loyalty_point_value` double NOT NULL DEFAULT 0 AFTER `loyalty_spend_per_point`,
+  ADD COLUMN `loyalty_expiry_days` int NOT NULL DEFAULT 0 AFTER `loyalty_point_value`;
+
+ALTER TABLE `order`
+  ADD COLUMN `points_redeemed` int NOT NULL DEFAULT 0 AFTER `total`,
+  ADD COLUMN `points_amount` double NOT NULL DEFAULT 0 AFTER `points_redeemed`,
+  ADD COLUMN `points_earned` int NOT NULL DEFAULT 0 AFTER `points_amount`;
+
+CREATE TABLE IF NOT EXISTS `loyalty_ledger` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `customer_id` int unsigned NOT NULL,
+  `order_id` int unsigned NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `points` int NOT NULL,
+  `remaining` int NOT NULL DEFAULT 0,
+  `expires_at` datetime NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_loyalty_ledger_customer` (`customer_id`, `remaining`, `expires_at`),
+  KEY `idx_loyalty_ledger_order` (`order_id`)
+);
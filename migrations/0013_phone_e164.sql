@@ -0,0 +1,7 @@
+-- Phone numbers are stored in E.164, local numbers use the country of the client
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `country_code` varchar(2) NOT NULL DEFAULT '' AFTER `language`;
+
+-- Stored numbers are normalised by running the service once with -backfill.phone. It applies the
+-- rules of new orders and merges the customers whose numbers become the same.
@@ -0,0 +1,22 @@
+-- Scheduled orders, released to the kitchen a lead time before pickup
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `schedule_lead_minutes` int NOT NULL DEFAULT 0 AFTER `loyalty_expiry_days`;
+
+CREATE TABLE IF NOT EXISTS `client_opening_hour` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `weekday` tinyint NOT NULL,
+  `open_time` char(5) NOT NULL,
+  `close_time` char(5) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_client_opening_hour_client` (`client_id`, `weekday`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `scheduled_at` datetime NULL AFTER `status`,
+  ADD COLUMN `released_at` datetime NULL AFTER `scheduled_at`,
+  ADD KEY `idx_order_release` (`released_at`, `scheduled_at`);
+
+-- Orders placed before scheduling existed were released when they were created
+UPDATE `order` SET `released_at` = `created_at` WHERE `released_at` IS NULL;
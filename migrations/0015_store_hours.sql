@@ -0,0 +1,17 @@
+-- Holiday exceptions to the opening hours and pausing new orders
+
+CREATE TABLE IF NOT EXISTS `client_holiday` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `date` char(10) NOT NULL,
+  `open_time` char(5) NOT NULL DEFAULT '',
+  `close_time` char(5) NOT NULL DEFAULT '',
+  `note` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_client_holiday_client` (`client_id`, `date`)
+);
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `orders_paused` tinyint(1) NOT NULL DEFAULT 0 AFTER `schedule_lead_minutes`,
+  ADD COLUMN `paused_until` datetime NULL AFTER `orders_paused`;
@@ -0,0 +1,15 @@
+-- Stock of products reserved by open orders
+
+CREATE TABLE IF NOT EXISTS `product_stock` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `product_id` int unsigned NOT NULL,
+  `on_hand` int NOT NULL DEFAULT 0,
+  `reserved` int NOT NULL DEFAULT 0,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_product_stock_product` (`client_id`, `product_id`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `stock_reserved` tinyint(1) NOT NULL DEFAULT 0 AFTER `points_earned`;
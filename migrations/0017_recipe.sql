@@ -0,0 +1,33 @@
+-- Recipes of products and the ingredients used by completed orders
+
+CREATE TABLE IF NOT EXISTS `ingredient` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `unit` varchar(20) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_ingredient_client` (`client_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `recipe_item` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `product_id` int unsigned NOT NULL,
+  `ingredient_id` int unsigned NOT NULL,
+  `quantity` double NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_recipe_item_ingredient` (`client_id`, `product_id`, `ingredient_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `ingredient_consumption` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `order_id` int unsigned NOT NULL,
+  `ingredient_id` int unsigned NOT NULL,
+  `quantity` double NOT NULL,
+  `consumed_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_ingredient_consumption_client` (`client_id`, `consumed_at`),
+  KEY `idx_ingredient_consumption_order` (`order_id`)
+);
@@ -0,0 +1,3 @@
+-- Reports aggregate the orders of a client by creation time
+
+CREATE INDEX `idx_order_client_created` ON `order` (`client_id`, `created_at`);
@@ -0,0 +1,4 @@
+-- Time zone of the client for the hourly traffic report, empty uses the time zone of the service
+
+ALTER TABLE `client_setting`
+  ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT '' AFTER `schedule_lead_minutes`;
@@ -0,0 +1,43 @@
+-- Cash register shifts, the payments of orders and the closing of the business day
+
+CREATE TABLE IF NOT EXISTS `shift` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` int unsigned NOT NULL,
+  `business_date` date NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `opened_by` varchar(100) NOT NULL DEFAULT '',
+  `closed_by` varchar(100) NOT NULL DEFAULT '',
+  `opening_cash` double NOT NULL DEFAULT 0,
+  `cash_sales` double NOT NULL DEFAULT 0,
+  `cash_refunds` double NOT NULL DEFAULT 0,
+  `cash_adjustments` double NOT NULL DEFAULT 0,
+  `expected_cash` double NOT NULL DEFAULT 0,
+  `counted_cash` double NULL,
+  `variance` double NULL,
+  `opening_note` varchar(255) NOT NULL DEFAULT '',
+  `closing_note` varchar(255) NOT NULL DEFAULT '',
+  `opened_at` datetime NOT NULL,
+  `closed_at` datetime NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_shift_client` (`client_id`, `status`),
+  KEY `idx_shift_business_date` (`client_id`, `business_date`)
+);
+
+CREATE TABLE IF NOT EXISTS `shift_cash_movement` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `shift_id` int unsigned NOT NULL,
+  `client_id` int unsigned NOT NULL,
+  `order_id` int unsigned NOT NULL DEFAULT 0,
+  `type` varchar(20) NOT NULL,
+  `amount` double NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_shift_cash_movement_shift` (`shift_id`)
+);
+
+ALTER TABLE `order`
+  ADD COLUMN `payment_method` varchar(20) NOT NULL DEFAULT '' AFTER `stock_reserved`,
+  ADD COLUMN `paid_at` datetime NULL AFTER `payment_method`,
+  ADD COLUMN `shift_id` int unsigned NULL AFTER `paid_at`,
+  ADD COLUMN `locked_at` datetime NULL AFTER `shift_id`,
+  ADD KEY `idx_order_shift` (`shift_id`);
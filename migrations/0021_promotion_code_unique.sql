@@ -0,0 +1,18 @@
+-- Promo codes are unique per client. Automatic promotions may have no code, so the unique key is
+-- on a generated column that is NULL for an empty code.
+
+-- Codes used more than once keep the oldest promotion, the later ones get the promotion ID appended
+UPDATE `promotion` p
+  JOIN (
+    SELECT `client_id`, `code`, MIN(`id`) AS `id`
+    FROM `promotion`
+    WHERE `code` <> ''
+    GROUP BY `client_id`, `code`
+    HAVING COUNT(*) > 1
+  ) d ON d.`client_id` = p.`client_id` AND d.`code` = p.`code` AND p.`id` <> d.`id`
+  SET p.`code` = CONCAT(LEFT(p.`code`, 39), '-', p.`id`);
+
+ALTER TABLE `promotion`
+  ADD COLUMN `code_key` varchar(50) GENERATED ALWAYS AS (NULLIF(`code`, '')) STORED AFTER `code`,
+  DROP INDEX `idx_promotion_client_code`,
+  ADD UNIQUE KEY `uk_promotion_client_code` (`client_id`, `code_key`);
@@ -0,0 +1,8 @@
+-- Outbox events back off after a failure and die after the last attempt
+
+ALTER TABLE `order_outbox`
+  ADD COLUMN `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `last_error`,
+  ADD COLUMN `dead_at` datetime NULL AFTER `published_at`,
+  DROP INDEX `idx_order_outbox_pending`,
+  ADD KEY `idx_order_outbox_due` (`published_at`, `dead_at`, `next_attempt_at`),
+  ADD KEY `idx_order_outbox_order` (`order_id`, `id`);
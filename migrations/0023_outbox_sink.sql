@@ -0,0 +1,15 @@
+-- Order events are written once per sink so every sink is relayed and retried on its own
+
+ALTER TABLE `order_outbox`
+  ADD COLUMN `sink` varchar(20) NOT NULL DEFAULT 'publisher' AFTER `id`,
+  DROP INDEX `idx_order_outbox_due`,
+  DROP INDEX `idx_order_outbox_order`,
+  ADD KEY `idx_order_outbox_due` (`sink`, `published_at`, `dead_at`, `next_attempt_at`),
+  ADD KEY `idx_order_outbox_order` (`sink`, `order_id`, `id`);
+
+-- Pending events were relayed to both sinks, the webhook sink gets its own copy
+INSERT INTO `order_outbox` (`sink`, `client_id`, `order_id`, `type`, `payload`, `next_attempt_at`, `created_at`)
+SELECT 'webhook', `client_id`, `order_id`, `type`, `payload`, `next_attempt_at`, `created_at`
+FROM `order_outbox`
+WHERE `sink` = 'publisher' AND `published_at` IS NULL AND `dead_at` IS NULL
+ORDER BY `id`;
@@ -127,7 +127,7 @@ func TestOrderProductHandler_ProductNotFound(t *testing.T) {
 	}).Info("Outgoing response CreateOrderHandler")
 
 	// Check the response status code
-	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 
 	var response model.OrderResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
@@ -186,7 +186,7 @@ func TestOrderProductHandler_InvalidProductPrice(t *testing.T) {
 	}).Info("Outgoing response CreateOrderHandler")
 
 	// Check the response status code
-	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
 	var response model.OrderResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
@@ -245,7 +245,7 @@ func TestOrderProductHandler_InvalidTotal(t *testing.T) {
 	}).Info("Outgoing response CreateOrderHandler")
 
 	// Check the response status code
-	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
 	var response model.OrderResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
@@ -259,6 +259,51 @@ func TestOrderProductHandler_InvalidTotal(t *testing.T) {
 
 }
 
+func TestOrderProductHandler_ClientMismatch(t *testing.T) {
+	tables := []string{"product", "product_category", "client", "user", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+
+	categories := SampleCategories(client.ID)
+
+	producRepo.SetProductResponse(categories[0].Products[0].ID, &categories[0].Products[0])
+	request := model.OrderRequest{
+		ClientID:     uint(client.ID) + 1,
+		CustomerName: "John Doe",
+		PhoneNumber:  "123456789",
+		Total:        categories[0].Products[0].Price,
+		Orders: []model.OrderDetail{
+			{ProductID: categories[0].Products[0].ID, Price: categories[0].Products[0].Price, Quantity: 1, Discount: 0.0, Total: categories[0].Products[0].Price},
+		},
+	}
+
+	orderRequestJSON, _ := json.Marshal(request)
+	req, err := http.NewRequest("POST", "/orders", bytes.NewBuffer(orderRequestJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Token", client.Token)
+	requestID := uuid.New().String()
+	ctx := context.WithValue(req.Context(), middleware.RequestIDKey, requestID)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(orderHandler.CreateOrderHandler).ServeHTTP(rr, req)
+
+	// The client of the request must be the client of the token
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var response model.OrderResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, service.InvalidRequestError, response.Code)
+	assert.Nil(t, response.Data)
+}
+
 func TestGetOrderHandler_Positive(t *testing.T) {
 	tables := []string{"product", "product_category", "client", "user", "order_detail", "`order`"}
 	defer clearDB(tables)
@@ -347,7 +392,7 @@ func TestGetOrderHandler_InvalidToken(t *testing.T) {
 	}).Info("Outgoing response GetOrderHandler")
 
 	// Check the response status code
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 
 	var response model.GetOrderResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
@@ -397,7 +442,7 @@ func TestGetOrderHandler_OrderNotFound(t *testing.T) {
 	}).Info("Outgoing response GetOrderHandler")
 
 	// Check the response status code
-	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 
 	var response model.GetOrderResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
@@ -14,6 +14,7 @@ import (
 	"maqhaa/library/logging"
 	exEntity "maqhaa/order_service/external/entity"
 	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
 	"maqhaa/order_service/internal/app/model"
 	"maqhaa/order_service/internal/app/repository"
 	"maqhaa/order_service/internal/app/repository/mock"
@@ -75,7 +76,10 @@ func setup() {
 	// Create a product service and handler
 	producRepo = mock.NewMockProductRepository()
 	orderRepository := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepository, producRepo)
+	promotionRepository := repository.NewPromotionRepository(db)
+	clientRepository := repository.NewClientRepository(db)
+	kitchenRepository := repository.NewKitchenRepository(db)
+	orderService := service.NewOrderService(orderRepository, producRepo, promotionRepository, clientRepository, kitchenRepository, event.NewHub(event.DefaultHistorySize))
 	orderHandler = handler.NewOrderHandler(orderService)
 
 }
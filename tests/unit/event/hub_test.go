@@ -0,0 +1,120 @@
+package event_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := event.NewHub(event.DefaultHistorySize)
+
+	subscription, replay := hub.Subscribe(1, "")
+	defer subscription.Close()
+	assert.Empty(t, replay)
+
+	hub.Publish(event.Event{Type: event.OrderCreated, ClientID: 1, OrderID: 10})
+	hub.Publish(event.Event{Type: event.OrderCreated, ClientID: 2, OrderID: 20})
+
+	received := <-subscription.Events
+	assert.NotEmpty(t, received.ID)
+	assert.Equal(t, uint(10), received.OrderID)
+	assert.False(t, received.CreatedAt.IsZero())
+
+	// Events of other clients are not delivered
+	assert.Len(t, subscription.Events, 0)
+}
+
+func TestHub_Replay(t *testing.T) {
+	hub := event.NewHub(2)
+
+	subscription, _ := hub.Subscribe(1, "")
+	defer subscription.Close()
+	hub.Publish(event.Event{Type: event.OrderCreated, ClientID: 1, OrderID: 1})
+	hub.Publish(event.Event{Type: event.OrderEdited, ClientID: 1, OrderID: 1})
+	hub.Publish(event.Event{Type: event.OrderCancelled, ClientID: 1, OrderID: 1})
+	first := <-subscription.Events
+	<-subscription.Events
+	last := <-subscription.Events
+
+	// Only the last two events are kept, they are all the events after the first
+	subscription2, replay := hub.Subscribe(1, first.ID)
+	defer subscription2.Close()
+	assert.Len(t, replay, 2)
+	assert.Equal(t, event.OrderEdited, replay[0].Type)
+	assert.Equal(t, event.OrderCancelled, replay[1].Type)
+
+	subscription3, replay := hub.Subscribe(1, last.ID)
+	defer subscription3.Close()
+	assert.Empty(t, replay)
+}
+
+func TestHub_Resync(t *testing.T) {
+	previous := event.NewHub(2)
+	subscription, _ := previous.Subscribe(1, "")
+	previous.Publish(event.Event{Type: event.OrderCreated, ClientID: 1, OrderID: 1})
+	beforeRestart := <-subscription.Events
+	subscription.Close()
+
+	time.Sleep(2 * time.Millisecond)
+	hub := event.NewHub(2)
+	subscription, _ = hub.Subscribe(1, "")
+	defer subscription.Close()
+	for i := 0; i < 4; i++ {
+		hub.Publish(event.Event{Type: event.OrderCreated, ClientID: 1, OrderID: uint(i)})
+	}
+	trimmed := <-subscription.Events
+	<-subscription.Events
+	<-subscription.Events
+	last := <-subscription.Events
+
+	// An ID issued before the restart, one older than the history and a malformed one can not be
+	// replayed, a resync event with the latest ID is sent instead
+	for _, lastEventID := range []string{beforeRestart.ID, trimmed.ID, "42"} {
+		resync, replay := hub.Subscribe(1, lastEventID)
+		if assert.Len(t, replay, 1, lastEventID) {
+			assert.Equal(t, event.Resync, replay[0].Type)
+			assert.Equal(t, last.ID, replay[0].ID)
+		}
+		resync.Close()
+	}
+}
+
+func TestHub_SlowSubscriberIsDropped(t *testing.T) {
+	hub := event.NewHub(event.DefaultHistorySize)
+	subscription, _ := hub.Subscribe(1, "")
+
+	for i := 0; i < 100; i++ {
+		hub.Publish(event.Event{Type: event.OrderCreated, ClientID: 1, OrderID: uint(i)})
+	}
+
+	count := 0
+	for range subscription.Events {
+		count++
+	}
+	assert.Less(t, count, 100)
+
+	// Closing a dropped subscription is a no-op
+	subscription.Close()
+}
+
+func TestHub_PublishSnapshot(t *testing.T) {
+	hub := event.NewHub(event.DefaultHistorySize)
+
+	subscription, _ := hub.Subscribe(1, "")
+	defer subscription.Close()
+
+	order := &entity.Order{ID: 1, Status: 2, StatusText: "Paid", OrderDetails: []entity.OrderDetail{{ID: 1, Quantity: 1}}}
+	hub.Publish(event.Event{Type: event.OrderStatusChanged, ClientID: 1, OrderID: 1, Order: order})
+
+	// Handlers localize and change the order after it is published
+	order.StatusText = "Dibayar"
+	order.OrderDetails[0].Quantity = 5
+
+	received := <-subscription.Events
+	assert.Equal(t, "Paid", received.Order.StatusText)
+	assert.Equal(t, 1, received.Order.OrderDetails[0].Quantity)
+}
@@ -0,0 +1,187 @@
+package grpc_test
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"maqhaa/order_service/internal/interface/grpc/handler"
+	"maqhaa/order_service/internal/interface/grpc/pb"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeOrderService serves a single order for the token "valid".
+type fakeOrderService struct {
+	request       *model.OrderRequest
+	statusRequest *model.UpdateStatusRequest
+}
+
+func (s *fakeOrderService) order(token string, orderID int) (*entity.Order, service.AppError) {
+	if token != "valid" {
+		return nil, *service.NewInvalidTokenError()
+	}
+	if orderID != 1 {
+		return nil, *service.NewOrderNotFoundError()
+	}
+	return &entity.Order{ID: 1, OrderNumber: "ORD-0001", Status: model.OrderStatusIncoming, Total: 8.5,
+		OrderDetails: []entity.OrderDetail{{ID: 1, ProductID: 3, Quantity: 2}}}, *service.NewSuccessError()
+}
+
+func (s *fakeOrderService) AddOrder(ctx context.Context, token string, request *model.OrderRequest) (*entity.Order, service.AppError) {
+	s.request = request
+	if request.Total <= 0 {
+		return nil, *service.NewInvalidTotalError()
+	}
+	return s.order(token, 1)
+}
+
+func (s *fakeOrderService) EditOrder(ctx context.Context, token string, request *model.OrderRequest) (*entity.Order, service.AppError) {
+	return s.order(token, int(request.ID))
+}
+
+func (s *fakeOrderService) GetOrder(ctx context.Context, token string, orderID int) (*entity.Order, service.AppError) {
+	return s.order(token, orderID)
+}
+
+func (s *fakeOrderService) ListOrders(ctx context.Context, token string, statuses []int, phoneNumber string) ([]*entity.Order, service.AppError) {
+	order, appErr := s.order(token, 1)
+	if appErr.Code != service.SuccessError {
+		return nil, appErr
+	}
+	return []*entity.Order{order}, appErr
+}
+
+func (s *fakeOrderService) UpdateStatus(ctx context.Context, token string, orderID int, request *model.UpdateStatusRequest) (*entity.Order, service.AppError) {
+	s.statusRequest = request
+	order, appErr := s.order(token, orderID)
+	if appErr.Code != service.SuccessError {
+		return nil, appErr
+	}
+	if request.Status <= order.Status {
+		return nil, *service.NewInvalidOrderStatusError()
+	}
+	order.Status = request.Status
+	return order, appErr
+}
+
+func (s *fakeOrderService) CancelOrder(ctx context.Context, token string, orderID int) (*entity.Order, service.AppError) {
+	return s.order(token, orderID)
+}
+
+func (s *fakeOrderService) GetScheduledOrders(ctx context.Context, token string) ([]*entity.Order, service.AppError) {
+	return nil, *service.NewSuccessError()
+}
+
+func newClient(t *testing.T, orderService service.OrderService) pb.OrderServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterOrderServiceServer(server, handler.NewOrderHandler(orderService))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewOrderServiceClient(conn)
+}
+
+func TestOrderHandler(t *testing.T) {
+	orderService := &fakeOrderService{}
+	client := newClient(t, orderService)
+	ctx := context.Background()
+
+	resp, err := client.CreateOrder(ctx, &pb.OrderRequest{
+		Token:        "valid",
+		ClientId:     1,
+		CustomerName: "Customer",
+		Total:        8.5,
+		RedeemPoints: 20,
+		Orders:       []*pb.OrderDetailRequest{{ProductId: 3, Price: 4.25, Quantity: 2, Total: 8.5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, orderService.request.RedeemPoints)
+	assert.Equal(t, "ORD-0001", resp.Data.OrderNumber)
+	assert.Equal(t, "Incoming", resp.Data.StatusText)
+	assert.Len(t, resp.Data.Details, 1)
+	assert.Equal(t, uint(3), orderService.request.Orders[0].ProductID)
+
+	_, err = client.GetOrder(ctx, &pb.GetOrderRequest{Token: "valid", OrderId: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetOrder(ctx, &pb.GetOrderRequest{Token: "invalid", OrderId: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetOrder(ctx, &pb.GetOrderRequest{OrderId: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CreateOrder(ctx, &pb.OrderRequest{Token: "valid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, service.InvalidTotalMessage, status.Convert(err).Message())
+
+	list, err := client.ListOrders(ctx, &pb.ListOrdersRequest{Token: "valid"})
+	assert.NoError(t, err)
+	assert.Len(t, list.Data, 1)
+
+	updated, err := client.UpdateStatus(ctx, &pb.UpdateStatusRequest{Token: "valid", OrderId: 1, Status: model.OrderStatusPaid, PaymentMethod: model.PaymentMethodQRIS})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(model.OrderStatusPaid), updated.Data.Status)
+	assert.Equal(t, model.PaymentMethodQRIS, orderService.statusRequest.PaymentMethod)
+
+	_, err = client.UpdateStatus(ctx, &pb.UpdateStatusRequest{Token: "valid", OrderId: 1, Status: model.OrderStatusIncoming})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, codes.OK, handler.StatusCode(service.SuccessError))
+	assert.Equal(t, codes.InvalidArgument, handler.StatusCode(service.InvalidRequestError))
+	assert.Equal(t, codes.InvalidArgument, handler.StatusCode(service.CurrencyMismatch))
+	assert.Equal(t, codes.Internal, handler.StatusCode(service.UpdateQueryError))
+	assert.Equal(t, codes.FailedPrecondition, handler.StatusCode(service.StoreClosed))
+	assert.Equal(t, codes.FailedPrecondition, handler.StatusCode(service.OutOfStock))
+	assert.Equal(t, codes.FailedPrecondition, handler.StatusCode(service.InsufficientPoints))
+	assert.Equal(t, codes.FailedPrecondition, handler.StatusCode(service.ShiftAlreadyOpen))
+	assert.Equal(t, codes.NotFound, handler.StatusCode(service.CustomerNotFound))
+	assert.Equal(t, codes.NotFound, handler.StatusCode(service.ShiftNotFound))
+	assert.Equal(t, codes.Unauthenticated, handler.StatusCode(service.InvalidToken))
+	assert.Equal(t, codes.Internal, handler.StatusCode(601+50))
+}
+
+func TestOrderHandler_ScheduledAt(t *testing.T) {
+	orderService := &fakeOrderService{}
+	client := newClient(t, orderService)
+	ctx := context.Background()
+
+	request := &pb.OrderRequest{
+		Token:        "valid",
+		ClientId:     1,
+		CustomerName: "Customer",
+		Total:        8.5,
+		Orders:       []*pb.OrderDetailRequest{{ProductId: 3, Price: 4.25, Quantity: 2, Total: 8.5}},
+		ScheduledAt:  "2024-05-01T10:30:00+07:00",
+	}
+
+	// The offset of the request is kept, the time zone of the service does not matter
+	_, err := client.CreateOrder(ctx, request)
+	assert.NoError(t, err)
+	if assert.NotNil(t, orderService.request.ScheduledAt) {
+		assert.True(t, time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC).Equal(*orderService.request.ScheduledAt))
+	}
+
+	request.ScheduledAt = "2024-05-01 10:30:00"
+	_, err = client.CreateOrder(ctx, request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
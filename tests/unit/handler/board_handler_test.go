@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"context"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"maqhaa/order_service/internal/interface/http/handler"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBoardService accepts the client token "valid" and the board token "board".
+type fakeBoardService struct {
+	hub *event.Hub
+}
+
+func (s *fakeBoardService) GetBoard(ctx context.Context, token string) (*model.QueueBoard, service.AppError) {
+	if token != "valid" {
+		return nil, *service.NewInvalidTokenError()
+	}
+	return &model.QueueBoard{NowServing: []model.QueueBoardEntry{}, Ready: []model.QueueBoardEntry{}}, *service.NewSuccessError()
+}
+
+func (s *fakeBoardService) IssueBoardToken(ctx context.Context, token string) (*model.BoardToken, service.AppError) {
+	if token != "valid" {
+		return nil, *service.NewInvalidTokenError()
+	}
+	return &model.BoardToken{Token: "board"}, *service.NewSuccessError()
+}
+
+func (s *fakeBoardService) Subscribe(ctx context.Context, token string) (*event.Subscription, *model.QueueBoard, service.AppError) {
+	board, appErr := s.GetBoard(ctx, token)
+	if appErr.Code != service.SuccessError {
+		return nil, nil, appErr
+	}
+	subscription, _ := s.hub.Subscribe(1, "")
+	return subscription, board, appErr
+}
+
+func (s *fakeBoardService) SubscribeWithBoardToken(ctx context.Context, boardToken string) (*event.Subscription, *model.QueueBoard, service.AppError) {
+	if boardToken != "board" {
+		return nil, nil, *service.NewInvalidTokenError()
+	}
+	return s.Subscribe(ctx, "valid")
+}
+
+func TestMain(m *testing.M) {
+	logging.InitLogger()
+	os.Exit(m.Run())
+}
+
+func newBoardServer() *httptest.Server {
+	boardHandler := handler.NewBoardHandler(&fakeBoardService{hub: event.NewHub(10)}, []string{"https://board.example.com"})
+	return httptest.NewServer(http.HandlerFunc(boardHandler.BoardSocketHandler))
+}
+
+func TestBoardSocketHandler_Origin(t *testing.T) {
+	server := newBoardServer()
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?board_token=board"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://board.example.com"}})
+	assert.NoError(t, err)
+	var message model.BoardMessage
+	assert.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, model.BoardMessageSnapshot, message.Type)
+	conn.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://evil.example.com"}})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestBoardSocketHandler_Token(t *testing.T) {
+	server := newBoardServer()
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// The client token is accepted in the header only, not in the URL
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Token": []string{"valid"}})
+	assert.NoError(t, err)
+	conn.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?token=valid", nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?board_token=expired", nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	allowed := []string{"https://board.example.com/"}
+	request := func(origin string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://api.example.com/board/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+
+	assert.True(t, handler.AllowedOrigin(request(""), allowed))
+	assert.True(t, handler.AllowedOrigin(request("http://api.example.com"), allowed))
+	assert.True(t, handler.AllowedOrigin(request("https://board.example.com"), allowed))
+	assert.False(t, handler.AllowedOrigin(request("http://board.example.com"), allowed))
+	assert.False(t, handler.AllowedOrigin(request("https://evil.example.com"), allowed))
+}
@@ -0,0 +1,69 @@
+package handler_test
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"maqhaa/order_service/internal/interface/http/handler"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEventService accepts the client token "valid" and the stream token "stream".
+type fakeEventService struct {
+	hub *event.Hub
+}
+
+func (s *fakeEventService) IssueStreamToken(ctx context.Context, token string) (*model.StreamToken, service.AppError) {
+	if token != "valid" {
+		return nil, *service.NewInvalidTokenError()
+	}
+	return &model.StreamToken{Token: "stream"}, *service.NewSuccessError()
+}
+
+func (s *fakeEventService) Subscribe(ctx context.Context, token string, lastEventID string) (*event.Subscription, []event.Event, service.AppError) {
+	if token != "valid" {
+		return nil, nil, *service.NewInvalidTokenError()
+	}
+	subscription, replay := s.hub.Subscribe(1, lastEventID)
+	return subscription, replay, *service.NewSuccessError()
+}
+
+func (s *fakeEventService) SubscribeWithStreamToken(ctx context.Context, streamToken string, lastEventID string) (*event.Subscription, []event.Event, service.AppError) {
+	if streamToken != "stream" {
+		return nil, nil, *service.NewInvalidTokenError()
+	}
+	subscription, replay := s.hub.Subscribe(1, lastEventID)
+	return subscription, replay, *service.NewSuccessError()
+}
+
+func TestStreamOrderEventsHandler_Token(t *testing.T) {
+	eventHandler := handler.NewEventHandler(&fakeEventService{hub: event.NewHub(10)})
+	server := httptest.NewServer(http.HandlerFunc(eventHandler.StreamOrderEventsHandler))
+	defer server.Close()
+
+	open := func(query string, header http.Header) int {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+query, nil)
+		for key, values := range header {
+			request.Header[key] = values
+		}
+		resp, err := http.DefaultClient.Do(request)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The client token is accepted in the header only, not in the URL
+	assert.Equal(t, http.StatusOK, open("", http.Header{"Token": []string{"valid"}}))
+	assert.Equal(t, http.StatusOK, open("?stream_token=stream", nil))
+	assert.Equal(t, http.StatusUnauthorized, open("?token=valid", nil))
+	assert.Equal(t, http.StatusUnauthorized, open("?stream_token=expired", nil))
+}
@@ -0,0 +1,29 @@
+package i18n_test
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/i18n"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	assert.Equal(t, i18n.Indonesian, i18n.FromAcceptLanguage("id-ID,id;q=0.9,en;q=0.8"))
+	assert.Equal(t, i18n.English, i18n.FromAcceptLanguage("fr-FR, en-US;q=0.7, id;q=0.5"))
+	assert.Equal(t, i18n.Indonesian, i18n.FromAcceptLanguage("en;q=0.2, id"))
+	assert.Equal(t, "", i18n.FromAcceptLanguage("fr, de;q=0.5"))
+	assert.Equal(t, "", i18n.FromAcceptLanguage("id;q=0"))
+	assert.Equal(t, "", i18n.FromAcceptLanguage(""))
+}
+
+func TestContextLanguage(t *testing.T) {
+	assert.Equal(t, i18n.DefaultLanguage, i18n.FromContext(context.Background()))
+	assert.Equal(t, i18n.Indonesian, i18n.FromContext(i18n.WithLanguage(context.Background(), i18n.Indonesian)))
+}
+
+func TestStatusText(t *testing.T) {
+	assert.Equal(t, "Diproses", i18n.StatusText(i18n.Indonesian, 3))
+	assert.Equal(t, "Processing", i18n.StatusText("fr", 3))
+	assert.Equal(t, "Unknown", i18n.StatusText(i18n.English, 42))
+}
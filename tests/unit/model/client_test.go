@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyClientSetting_Partial(t *testing.T) {
+	setting := &entity.ClientSetting{
+		CurrencyCode:        "IDR",
+		TaxRate:             11,
+		TaxInclusive:        true,
+		RoundingMode:        model.RoundingModeNearest,
+		RoundingIncrement:   100,
+		Language:            "id",
+		CountryCode:         "ID",
+		LoyaltyPointValue:   1000,
+		ScheduleLeadMinutes: 30,
+		Timezone:            "Asia/Jakarta",
+	}
+
+	taxRate := 0.0
+	taxInclusive := false
+	currency := "sgd"
+	model.ApplyClientSetting(setting, &model.ClientSettingRequest{
+		TaxRate:      &taxRate,
+		TaxInclusive: &taxInclusive,
+		CurrencyCode: &currency,
+	})
+
+	// Zero values that are sent are applied, the fields left out keep their value
+	assert.Equal(t, 0.0, setting.TaxRate)
+	assert.False(t, setting.TaxInclusive)
+	assert.Equal(t, "SGD", setting.CurrencyCode)
+	assert.Equal(t, model.RoundingModeNearest, setting.RoundingMode)
+	assert.Equal(t, 100.0, setting.RoundingIncrement)
+	assert.Equal(t, "id", setting.Language)
+	assert.Equal(t, "ID", setting.CountryCode)
+	assert.Equal(t, 1000.0, setting.LoyaltyPointValue)
+	assert.Equal(t, 30, setting.ScheduleLeadMinutes)
+	assert.Equal(t, "Asia/Jakarta", setting.Timezone)
+}
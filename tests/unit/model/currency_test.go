@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundAmount(t *testing.T) {
+	assert.Equal(t, 25801.0, model.RoundAmount(25800.5, "IDR"))
+	assert.Equal(t, 1235.0, model.RoundAmount(1234.56, "JPY"))
+	assert.Equal(t, 12.35, model.RoundAmount(12.345, "USD"))
+	assert.Equal(t, 1.235, model.RoundAmount(1.2345, "KWD"))
+	assert.Equal(t, 1.235, model.RoundAmount(1.2345, "BHD"))
+	assert.Equal(t, 500.0, model.RoundAmount(499.6, ""))
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "IDR 25,800", model.FormatAmount(25800, "IDR"))
+	assert.Equal(t, "IDR 1,234,568", model.FormatAmount(1234567.5, "IDR"))
+	assert.Equal(t, "USD 12.50", model.FormatAmount(12.5, "USD"))
+	assert.Equal(t, "USD -0.95", model.FormatAmount(-0.95, "usd"))
+	assert.Equal(t, "KWD 1.250", model.FormatAmount(1.25, "KWD"))
+	assert.Equal(t, "IDR 500", model.FormatAmount(500, ""))
+}
+
+func TestNewOrderAmounts(t *testing.T) {
+	order := &entity.Order{
+		CurrencyCode: "USD",
+		Subtotal:     10,
+		Tax:          1,
+		Total:        11,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 2.5, Quantity: 4, Total: 10},
+		},
+	}
+
+	amounts := model.NewOrderAmounts(order)
+	assert.Equal(t, "USD", amounts.Currency)
+	assert.Equal(t, "USD 10.00", amounts.Subtotal)
+	assert.Equal(t, "USD 11.00", amounts.Total)
+	assert.Len(t, amounts.OrderDetails, 1)
+	assert.Equal(t, "USD 2.50", amounts.OrderDetails[0].Price)
+}
@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"081234567890", "081234567890"},
+		{"0812-3456-7890", "081234567890"},
+		{" (0812) 3456 7890 ", "081234567890"},
+		{"+62 812 3456 7890", "+6281234567890"},
+		{"0062 812.3456.7890", "+6281234567890"},
+		{"62+812", "62812"},
+		{"+", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, model.NormalizePhoneNumber(test.input), test.input)
+	}
+}
@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngredientQuantities(t *testing.T) {
+	recipes := []*entity.RecipeItem{
+		{ProductID: 1, IngredientID: 10, Quantity: 18},
+		{ProductID: 1, IngredientID: 11, Quantity: 150},
+		{ProductID: 2, IngredientID: 10, Quantity: 9},
+		{ProductID: 3, IngredientID: 12, Quantity: 1},
+	}
+	details := []entity.OrderDetail{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Quantity: 1},
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 4, Quantity: 5},
+	}
+
+	assert.Equal(t, map[uint]float64{10: 63, 11: 450}, model.IngredientQuantities(details, recipes))
+}
+
+func TestNewConsumptionReport(t *testing.T) {
+	dateRange, err := model.ParseDateRange("2024-01-01", "2024-01-02", time.Now())
+	assert.NoError(t, err)
+
+	report := model.NewConsumptionReport(dateRange, []model.IngredientUsage{
+		{Date: "2024-01-01", IngredientID: 11, Name: "Milk", Unit: "ml", Quantity: 300},
+		{Date: "2024-01-01", IngredientID: 10, Name: "Coffee beans", Unit: "g", Quantity: 36},
+		{Date: "2024-01-02", IngredientID: 11, Name: "Milk", Unit: "ml", Quantity: 150},
+	})
+
+	assert.Equal(t, "2024-01-01", report.From)
+	assert.Equal(t, "2024-01-02", report.To)
+	assert.Len(t, report.Days, 3)
+	assert.Equal(t, []model.IngredientUsage{
+		{IngredientID: 10, Name: "Coffee beans", Unit: "g", Quantity: 36},
+		{IngredientID: 11, Name: "Milk", Unit: "ml", Quantity: 450},
+	}, report.Totals)
+
+	empty := model.NewConsumptionReport(dateRange, nil)
+	assert.NotNil(t, empty.Days)
+	assert.NotNil(t, empty.Totals)
+}
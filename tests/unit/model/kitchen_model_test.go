@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitchenOrderStatus(t *testing.T) {
+	details := []entity.OrderDetail{
+		{ID: 1, PrepStatus: model.PrepStatusPending},
+		{ID: 2, PrepStatus: model.PrepStatusPending},
+	}
+	assert.Equal(t, model.OrderStatusPaid, model.KitchenOrderStatus(model.OrderStatusPaid, details))
+
+	details[0].PrepStatus = model.PrepStatusStarted
+	assert.Equal(t, model.OrderStatusProcessing, model.KitchenOrderStatus(model.OrderStatusPaid, details))
+
+	details[0].PrepStatus = model.PrepStatusReady
+	assert.Equal(t, model.OrderStatusProcessing, model.KitchenOrderStatus(model.OrderStatusProcessing, details))
+
+	details[1].PrepStatus = model.PrepStatusReady
+	assert.Equal(t, model.OrderStatusSuccess, model.KitchenOrderStatus(model.OrderStatusProcessing, details))
+
+	assert.Equal(t, model.OrderStatusIncoming, model.KitchenOrderStatus(model.OrderStatusIncoming, nil))
+}
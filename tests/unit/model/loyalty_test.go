@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoyaltyPoints(t *testing.T) {
+	setting := &entity.ClientSetting{LoyaltySpendPerPoint: 10000}
+
+	assert.Equal(t, 2, model.LoyaltyPoints(setting, 25800))
+	assert.Equal(t, 3, model.LoyaltyPoints(setting, 30000))
+	assert.Equal(t, 0, model.LoyaltyPoints(setting, 9999))
+	assert.Equal(t, 0, model.LoyaltyPoints(setting, -10000))
+
+	// A spend that is an exact multiple is not rounded down by float errors
+	assert.Equal(t, 3, model.LoyaltyPoints(&entity.ClientSetting{LoyaltySpendPerPoint: 0.1}, 0.3))
+
+	// Loyalty is disabled without a spend ratio
+	assert.Equal(t, 0, model.LoyaltyPoints(&entity.ClientSetting{}, 25800))
+}
+
+func TestLoyaltyPointsAmount(t *testing.T) {
+	assert.Equal(t, 1500.0, model.LoyaltyPointsAmount(&entity.ClientSetting{LoyaltyPointValue: 100}, 15))
+	assert.Equal(t, 0.3, model.LoyaltyPointsAmount(&entity.ClientSetting{CurrencyCode: "USD", LoyaltyPointValue: 0.1}, 3))
+	assert.Equal(t, 0.0, model.LoyaltyPointsAmount(&entity.ClientSetting{LoyaltyPointValue: 0.1}, 3))
+	assert.Equal(t, 0.0, model.LoyaltyPointsAmount(&entity.ClientSetting{}, 15))
+}
+
+func TestLoyaltyExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, model.LoyaltyExpiry(&entity.ClientSetting{}, now))
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), *model.LoyaltyExpiry(&entity.ClientSetting{LoyaltyExpiryDays: 30}, now))
+}
+
+func TestNewOrderAmounts_PointsPayment(t *testing.T) {
+	amounts := model.NewOrderAmounts(&entity.Order{CurrencyCode: "IDR", Total: 25800, PointsAmount: 1500})
+
+	assert.Equal(t, "IDR 1,500", amounts.PointsAmount)
+	assert.Equal(t, "IDR 24,300", amounts.AmountDue)
+}
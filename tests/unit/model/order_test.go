@@ -0,0 +1,32 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanChangeOrderStatus(t *testing.T) {
+	now := time.Now()
+	incoming := &entity.Order{Status: model.OrderStatusIncoming, ReleasedAt: &now}
+
+	assert.True(t, model.CanChangeOrderStatus(incoming, model.OrderStatusPaid))
+	assert.True(t, model.CanChangeOrderStatus(incoming, model.OrderStatusSuccess))
+	assert.True(t, model.CanChangeOrderStatus(incoming, model.OrderStatusCancelled))
+	assert.False(t, model.CanChangeOrderStatus(&entity.Order{Status: model.OrderStatusProcessing, ReleasedAt: &now}, model.OrderStatusPaid))
+
+	// Orders waiting for release can be paid but not prepared
+	scheduled := &entity.Order{Status: model.OrderStatusIncoming}
+	assert.True(t, model.CanChangeOrderStatus(scheduled, model.OrderStatusPaid))
+	assert.False(t, model.CanChangeOrderStatus(scheduled, model.OrderStatusProcessing))
+
+	// Finished orders can not be changed, not even cancelled again
+	for _, status := range []int{model.OrderStatusSuccess, model.OrderStatusCancelled} {
+		finished := &entity.Order{Status: status, ReleasedAt: &now}
+		assert.False(t, model.CanChangeOrderStatus(finished, model.OrderStatusCancelled))
+		assert.False(t, model.CanChangeOrderStatus(finished, model.OrderStatusSuccess))
+	}
+}
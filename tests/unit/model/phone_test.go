@@ -0,0 +1,53 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePhoneNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		country  string
+		expected string
+	}{
+		{"081234567890", "ID", "+6281234567890"},
+		{"0812-3456-7890", "ID", "+6281234567890"},
+		{"(0812) 3456.7890", "ID", "+6281234567890"},
+		{"81234567890", "ID", "+6281234567890"},
+		{"6281234567890", "ID", "+6281234567890"},
+		{"+62 812 3456 7890", "ID", "+6281234567890"},
+		{"0062 812 3456 7890", "ID", "+6281234567890"},
+		{"+65 6123 4567", "ID", "+6561234567"},
+		{"6123 4567", "SG", "+6561234567"},
+		{"012-345 6789", "my", "+60123456789"},
+		{"(212) 555-0100", "US", "+12125550100"},
+		{"1 212 555 0100", "US", "+12125550100"},
+	}
+
+	for _, test := range tests {
+		phoneNumber, err := model.ParsePhoneNumber(test.input, test.country)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, phoneNumber, test.input)
+	}
+
+	for _, input := range []string{"", "+", "0812", "+0812345678", "+1234567890123456", "08123456789", "XX"} {
+		country := "ID"
+		if input == "08123456789" {
+			// Local numbers need a known country
+			country = "ZZ"
+		}
+		_, err := model.ParsePhoneNumber(input, country)
+		assert.ErrorIs(t, err, model.ErrInvalidPhoneNumber, input)
+	}
+}
+
+func TestValidPhoneNumber(t *testing.T) {
+	assert.True(t, model.ValidPhoneNumber("0812-3456-7890"))
+	assert.True(t, model.ValidPhoneNumber("+62 (812) 3456.7890"))
+	assert.False(t, model.ValidPhoneNumber("0812-CALL-ME"))
+	assert.False(t, model.ValidPhoneNumber("62+81234567890"))
+	assert.False(t, model.ValidPhoneNumber("123"))
+}
@@ -0,0 +1,138 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateRange(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, jakarta)
+
+	// Today by default
+	dateRange, err := model.ParseDateRange("", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, jakarta), dateRange.From)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, jakarta), dateRange.To)
+	assert.Equal(t, "2024-03-10", dateRange.FirstDay())
+	assert.Equal(t, "2024-03-10", dateRange.LastDay())
+
+	dateRange, err = model.ParseDateRange("2024-03-01", "2024-03-07", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, jakarta), dateRange.To)
+	assert.Equal(t, "2024-03-07", dateRange.LastDay())
+
+	// A single day
+	dateRange, err = model.ParseDateRange("2024-03-01", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-01", dateRange.LastDay())
+
+	_, err = model.ParseDateRange("01/03/2024", "", now)
+	assert.ErrorIs(t, err, model.ErrInvalidDateRange)
+	_, err = model.ParseDateRange("2024-03-07", "2024-03-01", now)
+	assert.ErrorIs(t, err, model.ErrInvalidDateRange)
+
+	// At most MaxReportDays days
+	_, err = model.ParseDateRange("2024-01-01", "2024-04-01", now)
+	assert.NoError(t, err)
+	_, err = model.ParseDateRange("2024-01-01", "2024-04-02", now)
+	assert.ErrorIs(t, err, model.ErrInvalidDateRange)
+}
+
+func TestNewDailyReport(t *testing.T) {
+	dateRange, err := model.ParseDateRange("2024-03-01", "2024-03-03", time.Now())
+	assert.NoError(t, err)
+
+	report := model.NewDailyReport(7, "IDR", dateRange, []model.DailySalesRow{
+		{Date: "2024-03-01", Status: model.OrderStatusSuccess, OrderCount: 2, GrossSales: 100000, LineDiscounts: 5000, PromoDiscount: 10000, ServiceCharge: 4250, Tax: 9350, Total: 98600},
+		{Date: "2024-03-01", Status: model.OrderStatusPaid, OrderCount: 1, GrossSales: 20000, Total: 22000},
+		{Date: "2024-03-01", Status: model.OrderStatusCancelled, OrderCount: 1, GrossSales: 50000, Total: 55000},
+		{Date: "2024-03-03", Status: model.OrderStatusSuccess, OrderCount: 1, GrossSales: 30000, Total: 33000},
+	})
+
+	assert.Equal(t, uint(7), report.ClientID)
+	assert.Equal(t, "2024-03-01", report.From)
+	assert.Equal(t, "2024-03-03", report.To)
+	assert.Len(t, report.Days, 3)
+
+	day := report.Days[0]
+	assert.Equal(t, 3, day.OrderCount)
+	assert.Equal(t, 120000.0, day.GrossSales)
+	assert.Equal(t, 15000.0, day.Discounts)
+	assert.Equal(t, 105000.0, day.NetSales)
+	assert.Equal(t, 120600.0, day.Total)
+	assert.Equal(t, 35000.0, day.AverageTicket)
+
+	// Cancelled orders only count in the breakdown by status
+	assert.Len(t, day.Statuses, 5)
+	assert.Equal(t, model.StatusSales{Status: model.OrderStatusCancelled, StatusText: "Cancelled", OrderCount: 1, Total: 55000}, day.Statuses[4])
+	assert.Equal(t, 2, day.Statuses[3].OrderCount)
+
+	// Days without orders are listed
+	assert.Equal(t, "2024-03-02", report.Days[1].Date)
+	assert.Equal(t, 0, report.Days[1].OrderCount)
+	assert.Equal(t, 0.0, report.Days[1].AverageTicket)
+
+	assert.Equal(t, 4, report.Summary.OrderCount)
+	assert.Equal(t, 135000.0, report.Summary.NetSales)
+	assert.Equal(t, 33750.0, report.Summary.AverageTicket)
+}
+
+func TestProductRanking_CSVRecords(t *testing.T) {
+	ranking := &model.ProductRanking{Products: []model.ProductSales{
+		{Rank: 1, ProductID: 12, ProductName: "Latte, iced", Quantity: 40, Revenue: 1100000, OrderCount: 31},
+		{Rank: 2, ProductID: 7, Quantity: 12, Revenue: 99999.5, OrderCount: 9},
+	}}
+
+	assert.Equal(t, [][]string{
+		{"rank", "product_id", "product_name", "quantity", "revenue", "order_count"},
+		{"1", "12", "Latte, iced", "40", "1100000", "31"},
+		{"2", "7", "", "12", "99999.5", "9"},
+	}, ranking.CSVRecords())
+}
+
+func TestIsRankingSort(t *testing.T) {
+	assert.True(t, model.IsRankingSort(model.RankingByQuantity))
+	assert.True(t, model.IsRankingSort(model.RankingByRevenue))
+	assert.False(t, model.IsRankingSort("name"))
+}
+
+func TestNewHourlyHeatmap(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	assert.NoError(t, err)
+	dateRange, err := model.ParseDateRange("2024-01-01", "2024-01-07", time.Date(2024, 1, 1, 0, 0, 0, 0, kolkata))
+	assert.NoError(t, err)
+
+	// Buckets are converted to the time zone of the client, Kolkata is 5:30 ahead of UTC
+	heatmap := model.NewHourlyHeatmap(7, "INR", kolkata, dateRange, []int{model.OrderStatusSuccess}, []model.HeatmapBucket{
+		{Start: time.Date(2024, 1, 1, 3, 15, 0, 0, time.UTC), OrderCount: 2, Sales: 100.5},
+		{Start: time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC), OrderCount: 1, Sales: 40.25},
+		{Start: time.Date(2024, 1, 7, 20, 0, 0, 0, time.UTC), OrderCount: 3, Sales: 90},
+	})
+
+	assert.Equal(t, "Asia/Kolkata", heatmap.Timezone)
+	assert.Equal(t, []int{model.OrderStatusSuccess}, heatmap.Statuses)
+
+	// Monday 08:45 and 09:00
+	assert.Equal(t, 2, heatmap.Orders[time.Monday][8])
+	assert.Equal(t, 100.5, heatmap.Sales[time.Monday][8])
+	assert.Equal(t, 1, heatmap.Orders[time.Monday][9])
+
+	// Sunday 20:00 UTC is Monday 01:30
+	assert.Equal(t, 3, heatmap.Orders[time.Monday][1])
+	assert.Equal(t, 0, heatmap.Orders[time.Sunday][20])
+}
+
+func TestClientLocation(t *testing.T) {
+	assert.Equal(t, "Asia/Jakarta", model.ClientLocation(&entity.ClientSetting{Timezone: "Asia/Jakarta"}).String())
+	assert.Equal(t, time.Local, model.ClientLocation(&entity.ClientSetting{}))
+	assert.Equal(t, time.Local, model.ClientLocation(&entity.ClientSetting{Timezone: "Mars/Olympus"}))
+
+	assert.True(t, model.IsTimezone("Asia/Jakarta"))
+	assert.False(t, model.IsTimezone("Mars/Olympus"))
+	assert.False(t, model.IsTimezone(""))
+}
@@ -0,0 +1,68 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 2024-01-01 is a Monday
+var scheduleHours = []*entity.ClientOpeningHour{
+	{Weekday: 1, OpenTime: "08:00", CloseTime: "12:00"},
+	{Weekday: 1, OpenTime: "17:00", CloseTime: "00:00"},
+	{Weekday: 2, OpenTime: "08:00", CloseTime: "22:00"},
+}
+
+func TestIsWithinOpeningHours(t *testing.T) {
+	monday := func(hour, minute int) time.Time { return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC) }
+
+	assert.True(t, model.IsWithinOpeningHours(scheduleHours, nil, monday(8, 0)))
+	assert.True(t, model.IsWithinOpeningHours(scheduleHours, nil, monday(11, 59)))
+	assert.False(t, model.IsWithinOpeningHours(scheduleHours, nil, monday(12, 0)))
+	assert.False(t, model.IsWithinOpeningHours(scheduleHours, nil, monday(7, 59)))
+
+	// A close time of 00:00 is midnight at the end of the day
+	assert.True(t, model.IsWithinOpeningHours(scheduleHours, nil, monday(23, 59)))
+
+	// Days without opening hours are closed
+	assert.False(t, model.IsWithinOpeningHours(scheduleHours, nil, time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)))
+
+	// Clients without opening hours are always open
+	assert.True(t, model.IsWithinOpeningHours(nil, nil, time.Date(2024, 1, 3, 3, 0, 0, 0, time.UTC)))
+}
+
+func TestValidOpeningHour(t *testing.T) {
+	assert.True(t, model.ValidOpeningHour("08:00", "22:00"))
+	assert.True(t, model.ValidOpeningHour("17:00", "00:00"))
+	assert.False(t, model.ValidOpeningHour("22:00", "08:00"))
+	assert.False(t, model.ValidOpeningHour("08:00", "08:00"))
+	assert.False(t, model.ValidOpeningHour("8am", "22:00"))
+}
+
+func TestValidScheduledTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	assert.True(t, model.ValidScheduledTime(scheduleHours, nil, now.Add(2*time.Hour), now))
+	assert.False(t, model.ValidScheduledTime(scheduleHours, nil, now.Add(-time.Minute), now))
+	assert.False(t, model.ValidScheduledTime(scheduleHours, nil, now.Add(4*time.Hour), now))
+
+	// At most a week ahead
+	assert.True(t, model.ValidScheduledTime(nil, nil, now.AddDate(0, 0, 7), now))
+	assert.False(t, model.ValidScheduledTime(nil, nil, now.AddDate(0, 0, 7).Add(time.Minute), now))
+
+	// The pickup time is checked in the time zone of the service
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	assert.True(t, model.ValidScheduledTime(scheduleHours, nil, time.Date(2024, 1, 2, 1, 0, 0, 0, jakarta), now))
+}
+
+func TestIsScheduleDue(t *testing.T) {
+	pickup := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, model.IsScheduleDue(&entity.ClientSetting{}, pickup, pickup.Add(-16*time.Minute)))
+	assert.True(t, model.IsScheduleDue(&entity.ClientSetting{}, pickup, pickup.Add(-15*time.Minute)))
+	assert.True(t, model.IsScheduleDue(&entity.ClientSetting{ScheduleLeadMinutes: 45}, pickup, pickup.Add(-30*time.Minute)))
+	assert.False(t, model.IsScheduleDue(&entity.ClientSetting{ScheduleLeadMinutes: 45}, pickup, pickup.Add(-time.Hour)))
+}
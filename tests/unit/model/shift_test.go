@@ -0,0 +1,53 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderAmountDue(t *testing.T) {
+	assert.Equal(t, 45000.0, model.OrderAmountDue(&entity.Order{Total: 50000, PointsAmount: 5000}))
+
+	// Orders paid entirely with points are not paid in cash
+	assert.Equal(t, 0.0, model.OrderAmountDue(&entity.Order{Total: 10000, PointsAmount: 10000}))
+}
+
+func TestSettleShift(t *testing.T) {
+	shift := &entity.Shift{OpeningCash: 200000}
+	totals := model.CashTotals{Sales: 350000, Refunds: 25000, Adjustments: -5000}
+
+	// The variance is unknown until the cash is counted
+	model.SettleShift(shift, totals)
+	assert.Equal(t, 350000.0, shift.CashSales)
+	assert.Equal(t, 25000.0, shift.CashRefunds)
+	assert.Equal(t, -5000.0, shift.CashAdjustments)
+	assert.Equal(t, 520000.0, shift.ExpectedCash)
+	assert.Nil(t, shift.Variance)
+
+	counted := 518500.0
+	shift.CountedCash = &counted
+	model.SettleShift(shift, totals)
+	if assert.NotNil(t, shift.Variance) {
+		assert.Equal(t, -1500.0, *shift.Variance)
+	}
+}
+
+func TestNewShiftReport(t *testing.T) {
+	shift := &entity.Shift{ID: 7}
+	report := model.NewShiftReport(shift, "IDR", []model.PaymentSummary{
+		{PaymentMethod: model.PaymentMethodCard, OrderCount: 2, Total: 80000},
+		{PaymentMethod: model.PaymentMethodCash, OrderCount: 3, Total: 120000.004},
+	})
+
+	assert.Equal(t, shift, report.Shift)
+	assert.Equal(t, "IDR", report.CurrencyCode)
+	assert.Equal(t, 5, report.OrderCount)
+	assert.Equal(t, 200000.0, report.Total)
+	assert.Equal(t, 120000.0, report.Payments[1].Total)
+
+	// A shift without paid orders has an empty list of payments
+	assert.Equal(t, []model.PaymentSummary{}, model.NewShiftReport(shift, "IDR", nil).Payments)
+}
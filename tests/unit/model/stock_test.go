@@ -0,0 +1,49 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockAvailable(t *testing.T) {
+	assert.Equal(t, 3, model.StockAvailable(&entity.ProductStock{OnHand: 5, Reserved: 2}))
+	assert.Equal(t, 0, model.StockAvailable(&entity.ProductStock{OnHand: 1, Reserved: 2}))
+}
+
+func TestStockQuantities(t *testing.T) {
+	details := []entity.OrderDetail{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Quantity: 1},
+		{ProductID: 1, Quantity: 3},
+	}
+
+	assert.Equal(t, map[uint]int{1: 5, 2: 1}, model.StockQuantities(details))
+}
+
+func TestStockShortages(t *testing.T) {
+	stocks := map[uint]*entity.ProductStock{
+		1: {ProductID: 1, OnHand: 4, Reserved: 0},
+		2: {ProductID: 2, OnHand: 10, Reserved: 9},
+	}
+
+	// Products without stock are not tracked
+	assert.Empty(t, model.StockShortages([]entity.OrderDetail{{ProductID: 3, Quantity: 100}}, stocks))
+	assert.Empty(t, model.StockShortages([]entity.OrderDetail{{ProductID: 1, Quantity: 4}}, stocks))
+
+	// Lines of a product share its stock
+	details := []entity.OrderDetail{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Quantity: 1},
+		{ProductID: 1, Quantity: 3},
+		{ProductID: 2, Quantity: 1},
+	}
+	assert.Equal(t, []model.StockShortage{
+		{Line: 0, ProductID: 1, Requested: 5, Available: 4},
+		{Line: 1, ProductID: 2, Requested: 2, Available: 1},
+		{Line: 2, ProductID: 1, Requested: 5, Available: 4},
+		{Line: 3, ProductID: 2, Requested: 2, Available: 1},
+	}, model.StockShortages(details, stocks))
+}
@@ -0,0 +1,108 @@
+package model_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func storeTime(day, hour, minute int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestIsWithinOpeningHours_Holidays(t *testing.T) {
+	closed := []*entity.ClientHoliday{{Date: "2024-01-01", Note: "New Year"}}
+	assert.False(t, model.IsWithinOpeningHours(scheduleHours, closed, storeTime(1, 10, 0)))
+	assert.True(t, model.IsWithinOpeningHours(scheduleHours, closed, storeTime(2, 10, 0)))
+
+	// The hours of a holiday replace the weekly opening hours of its date
+	shortDay := []*entity.ClientHoliday{{Date: "2024-01-01", OpenTime: "10:00", CloseTime: "14:00"}}
+	assert.True(t, model.IsWithinOpeningHours(scheduleHours, shortDay, storeTime(1, 13, 0)))
+	assert.False(t, model.IsWithinOpeningHours(scheduleHours, shortDay, storeTime(1, 18, 0)))
+
+	// Clients without weekly opening hours are only closed on holidays
+	assert.False(t, model.IsWithinOpeningHours(nil, closed, storeTime(1, 10, 0)))
+	assert.True(t, model.IsWithinOpeningHours(nil, closed, storeTime(3, 10, 0)))
+}
+
+func TestIsOrdersPaused(t *testing.T) {
+	now := storeTime(1, 9, 0)
+	pausedUntil := storeTime(1, 9, 30)
+
+	assert.False(t, model.IsOrdersPaused(&entity.ClientSetting{}, now))
+	assert.True(t, model.IsOrdersPaused(&entity.ClientSetting{OrdersPaused: true}, now))
+	assert.True(t, model.IsOrdersPaused(&entity.ClientSetting{OrdersPaused: true, PausedUntil: &pausedUntil}, now))
+	assert.False(t, model.IsOrdersPaused(&entity.ClientSetting{OrdersPaused: true, PausedUntil: &pausedUntil}, pausedUntil))
+}
+
+func TestNewStoreState(t *testing.T) {
+	setting := &entity.ClientSetting{}
+
+	state := model.NewStoreState(setting, scheduleHours, nil, storeTime(1, 9, 0))
+	assert.True(t, state.Open)
+	assert.Equal(t, model.StoreStateOpen, state.State)
+	assert.Equal(t, storeTime(1, 12, 0), *state.ClosesAt)
+	assert.Nil(t, state.OpensAt)
+
+	state = model.NewStoreState(setting, scheduleHours, nil, storeTime(1, 13, 0))
+	assert.False(t, state.Open)
+	assert.Equal(t, model.StoreStateClosed, state.State)
+	assert.Equal(t, storeTime(1, 17, 0), *state.OpensAt)
+
+	// Closed on Wednesday until Monday
+	state = model.NewStoreState(setting, scheduleHours, nil, storeTime(3, 10, 0))
+	assert.Equal(t, storeTime(8, 8, 0), *state.OpensAt)
+
+	// Clients without opening hours never close
+	state = model.NewStoreState(setting, nil, nil, storeTime(1, 3, 0))
+	assert.True(t, state.Open)
+	assert.Nil(t, state.ClosesAt)
+}
+
+func TestNewStoreState_OvernightHours(t *testing.T) {
+	hours := []*entity.ClientOpeningHour{
+		{Weekday: 1, OpenTime: "17:00", CloseTime: "00:00"},
+		{Weekday: 2, OpenTime: "00:00", CloseTime: "02:00"},
+	}
+
+	state := model.NewStoreState(&entity.ClientSetting{}, hours, nil, storeTime(1, 23, 0))
+	assert.True(t, state.Open)
+	assert.Equal(t, storeTime(2, 2, 0), *state.ClosesAt)
+}
+
+func TestNewStoreState_Holiday(t *testing.T) {
+	holidays := []*entity.ClientHoliday{{Date: "2024-01-01", Note: "New Year"}}
+
+	state := model.NewStoreState(&entity.ClientSetting{}, scheduleHours, holidays, storeTime(1, 9, 0))
+	assert.False(t, state.Open)
+	assert.Equal(t, model.StoreStateHoliday, state.State)
+	assert.Equal(t, "New Year", state.Note)
+	assert.Equal(t, storeTime(2, 8, 0), *state.OpensAt)
+}
+
+func TestNewStoreState_Paused(t *testing.T) {
+	now := storeTime(1, 9, 0)
+
+	state := model.NewStoreState(&entity.ClientSetting{OrdersPaused: true}, scheduleHours, nil, now)
+	assert.False(t, state.Open)
+	assert.Equal(t, model.StoreStatePaused, state.State)
+	assert.Nil(t, state.OpensAt)
+
+	// A pause ending while open opens again at its end
+	pausedUntil := storeTime(1, 10, 30)
+	state = model.NewStoreState(&entity.ClientSetting{OrdersPaused: true, PausedUntil: &pausedUntil}, scheduleHours, nil, now)
+	assert.Equal(t, pausedUntil, *state.PausedUntil)
+	assert.Equal(t, pausedUntil, *state.OpensAt)
+
+	// A pause ending while closed opens with the next opening period
+	pausedUntil = storeTime(1, 12, 30)
+	state = model.NewStoreState(&entity.ClientSetting{OrdersPaused: true, PausedUntil: &pausedUntil}, scheduleHours, nil, now)
+	assert.Equal(t, storeTime(1, 17, 0), *state.OpensAt)
+
+	// An ended pause no longer applies
+	state = model.NewStoreState(&entity.ClientSetting{OrdersPaused: true, PausedUntil: &pausedUntil}, scheduleHours, nil, storeTime(1, 18, 0))
+	assert.True(t, state.Open)
+}
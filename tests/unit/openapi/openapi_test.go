@@ -0,0 +1,129 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maqhaa/order_service/internal/interface/http/handler"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type spec struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas struct {
+			ErrorCode struct {
+				Enum []int `json:"enum"`
+			} `json:"ErrorCode"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) spec {
+	rr := httptest.NewRecorder()
+	handler.NewOpenAPIHandler().GetSpecHandler(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var s spec
+	if err := json.Unmarshal(rr.Body.Bytes(), &s); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	return s
+}
+
+// registeredRoutes returns the routes registered on httpRouter in cmd/main.go.
+func registeredRoutes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../../cmd/main.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		receiver, ok := selector.X.(*ast.Ident)
+		if !ok || receiver.Name != "httpRouter" {
+			return true
+		}
+		switch selector.Sel.Name {
+		case "GET", "POST", "PUT", "DELETE":
+		default:
+			return true
+		}
+		path, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			t.Fatalf("route path of httpRouter.%s is not a string literal", selector.Sel.Name)
+		}
+		uri, _ := strconv.Unquote(path.Value)
+		routes = append(routes, selector.Sel.Name+" "+uri)
+		return true
+	})
+
+	return routes
+}
+
+// appErrorCodes returns the AppError codes declared in errors_utils.go.
+func appErrorCodes(t *testing.T) []int {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../../internal/app/service/errors_utils.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var codes []int
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, s := range gen.Specs {
+			value := s.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				literal, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || literal.Kind != token.INT || strings.HasSuffix(name.Name, "Message") {
+					continue
+				}
+				code, _ := strconv.ParseInt(literal.Value, 0, 64)
+				codes = append(codes, int(code))
+			}
+		}
+	}
+
+	return codes
+}
+
+func TestOpenAPI_RoutesMatchSpec(t *testing.T) {
+	s := loadSpec(t)
+	assert.True(t, strings.HasPrefix(s.OpenAPI, "3."))
+
+	var documented []string
+	for path, operations := range s.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, registeredRoutes(t), documented, "routes in cmd/main.go and openapi.json differ")
+}
+
+func TestOpenAPI_ErrorCodesMatchSpec(t *testing.T) {
+	s := loadSpec(t)
+
+	assert.ElementsMatch(t, appErrorCodes(t), s.Components.Schemas.ErrorCode.Enum, "AppError codes and the ErrorCode schema differ")
+}
@@ -0,0 +1,185 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/outbox"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryOutboxRepository keeps the outbox in memory.
+type memoryOutboxRepository struct {
+	events []*entity.OutboxEvent
+}
+
+func (r *memoryOutboxRepository) GetPendingEvents(ctx context.Context, sink string, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	var pending []*entity.OutboxEvent
+	waiting := make(map[uint]bool)
+	for _, e := range r.events {
+		if e.PublishedAt != nil || e.DeadAt != nil {
+			continue
+		}
+		if e.NextAttemptAt.After(now) {
+			waiting[e.OrderID] = true
+			continue
+		}
+		if !waiting[e.OrderID] && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(ctx context.Context, eventID uint) error {
+	now := time.Now()
+	for _, e := range r.events {
+		if e.ID == eventID {
+			e.Attempts++
+			e.PublishedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) MarkFailed(ctx context.Context, e *entity.OutboxEvent) error {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	logging.InitLogger()
+	os.Exit(m.Run())
+}
+
+func TestRelay_ProcessBatch(t *testing.T) {
+	repo := &memoryOutboxRepository{events: []*entity.OutboxEvent{
+		{ID: 1, OrderID: 10, Type: "order.created", Payload: `{"id":10}`},
+		{ID: 2, OrderID: 20, Type: "order.created", Payload: `{"id":20}`},
+		{ID: 3, OrderID: 10, Type: "order.status_changed", Payload: `{"id":10}`},
+		{ID: 4, OrderID: 20, Type: "order.status_changed", Payload: `{"id":20}`},
+	}}
+
+	// The first event of order 10 fails once
+	failed := false
+	publisher := outbox.NewMemoryPublisher()
+	publisher.Fail = func(message outbox.Message) error {
+		if message.ID == 1 && !failed {
+			failed = true
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	relay := outbox.NewRelay(repo, model.OutboxSinkPublisher, publisher, time.Second, 10, 3, time.Minute)
+	now := time.Now()
+
+	published, err := relay.ProcessBatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	// Later events of the failing order are held back
+	messages := publisher.Messages()
+	assert.Equal(t, uint(2), messages[0].ID)
+	assert.Equal(t, uint(4), messages[1].ID)
+	assert.Equal(t, "unavailable", repo.events[0].LastError)
+	assert.Nil(t, repo.events[2].PublishedAt)
+	assert.Equal(t, now.Add(time.Minute), repo.events[0].NextAttemptAt)
+
+	// Not retried before the backoff has passed
+	published, err = relay.ProcessBatch(context.Background(), now.Add(30*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	published, err = relay.ProcessBatch(context.Background(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	messages = publisher.Messages()
+	assert.Equal(t, uint(1), messages[2].ID)
+	assert.Equal(t, uint(3), messages[3].ID)
+	assert.Equal(t, 2, repo.events[0].Attempts)
+	assert.JSONEq(t, `{"id":10}`, string(messages[2].Data))
+
+	published, err = relay.ProcessBatch(context.Background(), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+}
+
+func TestRelay_DeadEvent(t *testing.T) {
+	repo := &memoryOutboxRepository{events: []*entity.OutboxEvent{
+		{ID: 1, OrderID: 10, Type: "order.created", Payload: `{"id":10}`},
+		{ID: 2, OrderID: 10, Type: "order.status_changed", Payload: `{"id":10}`},
+		{ID: 3, OrderID: 20, Type: "order.created", Payload: `{"id":20}`},
+	}}
+
+	publisher := outbox.NewMemoryPublisher()
+	publisher.Fail = func(message outbox.Message) error {
+		if message.ID == 1 {
+			return errors.New("rejected")
+		}
+		return nil
+	}
+
+	relay := outbox.NewRelay(repo, model.OutboxSinkPublisher, publisher, time.Second, 10, 2, time.Minute)
+	now := time.Now()
+
+	// Other orders are published while the failing event backs off
+	published, err := relay.ProcessBatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Nil(t, repo.events[0].DeadAt)
+
+	published, err = relay.ProcessBatch(context.Background(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 2, repo.events[0].Attempts)
+	assert.NotNil(t, repo.events[0].DeadAt)
+
+	// The later event of the order is published once the failing one is dead
+	published, err = relay.ProcessBatch(context.Background(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.NotNil(t, repo.events[1].PublishedAt)
+
+	// Dead events are not retried
+	published, _ = relay.ProcessBatch(context.Background(), now.Add(24*time.Hour))
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 2, repo.events[0].Attempts)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, outbox.Backoff(10*time.Second, 1))
+	assert.Equal(t, 40*time.Second, outbox.Backoff(10*time.Second, 3))
+	assert.Equal(t, outbox.MaxBackoff, outbox.Backoff(10*time.Second, 30))
+}
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var received outbox.Message
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "order.created", r.Header.Get("X-Event-Type"))
+		assert.Equal(t, "7", r.Header.Get("X-Event-ID"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	publisher := outbox.NewWebhookPublisher(server.URL, time.Second)
+	message := outbox.NewMessage(&entity.OutboxEvent{ID: 7, OrderID: 10, Type: "order.created", Payload: `{"id":10}`})
+
+	assert.NoError(t, publisher.Publish(context.Background(), message))
+	assert.Equal(t, uint(10), received.OrderID)
+	assert.JSONEq(t, `{"id":10}`, string(received.Data))
+
+	status = http.StatusInternalServerError
+	assert.Error(t, publisher.Publish(context.Background(), message))
+}
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerRepository_NormalizePhoneNumbers(t *testing.T) {
+	tables := []string{"loyalty_ledger", "customer", "`order`", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	// The same customer stored before and after phone numbers were normalised
+	local := entity.Customer{ClientID: client.ID, PhoneNumber: "0812 3456 7890", Name: "Budi"}
+	normalized := entity.Customer{ClientID: client.ID, PhoneNumber: "+6281234567890", Name: "Budi"}
+	assert.NoError(t, db.Create(&local).Error)
+	assert.NoError(t, db.Create(&normalized).Error)
+
+	order := entity.Order{ClientID: client.ID, OrderNumber: "ORD-0001", CustomerName: "Budi", PhoneNumber: "(0812) 3456-7890", CustomerID: &local.ID, Total: 10}
+	assert.NoError(t, db.Create(&order).Error)
+	credit := entity.LoyaltyLedger{ClientID: client.ID, CustomerID: local.ID, OrderID: order.ID, Type: model.LoyaltyEntryEarn, Points: 10, Remaining: 10}
+	assert.NoError(t, db.Create(&credit).Error)
+
+	changed, err := repository.NewCustomerRepository(db).NormalizePhoneNumbers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, changed)
+
+	var storedOrder entity.Order
+	assert.NoError(t, db.First(&storedOrder, order.ID).Error)
+	assert.Equal(t, "+6281234567890", storedOrder.PhoneNumber)
+	assert.Equal(t, normalized.ID, *storedOrder.CustomerID)
+
+	var storedCredit entity.LoyaltyLedger
+	assert.NoError(t, db.First(&storedCredit, credit.ID).Error)
+	assert.Equal(t, normalized.ID, storedCredit.CustomerID)
+
+	var customers []entity.Customer
+	assert.NoError(t, db.Where("client_id = ?", client.ID).Find(&customers).Error)
+	assert.Len(t, customers, 1)
+	assert.Equal(t, normalized.ID, customers[0].ID)
+}
@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitchenRepository_GetStationTicketsFinishedOrders(t *testing.T) {
+	tables := []string{"kitchen_ticket", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	kitchenRepo := repository.NewKitchenRepository(db)
+
+	now := time.Now()
+	newOrder := func() *entity.Order {
+		return &entity.Order{
+			ClientID:     1,
+			CustomerName: "John Doe",
+			Total:        50.0,
+			Status:       model.OrderStatusPaid,
+			ReleasedAt:   &now,
+			OrderDetails: []entity.OrderDetail{
+				{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+			},
+			KitchenTickets: []entity.KitchenTicket{
+				{StationID: 0, Status: model.PrepStatusPending},
+			},
+		}
+	}
+
+	openOrder, err := orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+	cancelledOrder, err := orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+	completedOrder, err := orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+	unpaidOrder := newOrder()
+	unpaidOrder.Status = model.OrderStatusIncoming
+	_, err = orderRepo.AddOrder(ctx, unpaidOrder)
+	assert.NoError(t, err)
+
+	// Orders finished by a status change leave their tickets open, unpaid orders are not prepared yet
+	assert.NoError(t, db.Model(&entity.Order{}).Where("id = ?", cancelledOrder.ID).Update("status", model.OrderStatusCancelled).Error)
+	assert.NoError(t, db.Model(&entity.Order{}).Where("id = ?", completedOrder.ID).Update("status", model.OrderStatusSuccess).Error)
+
+	tickets, err := kitchenRepo.GetStationTickets(ctx, 1, 0)
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, openOrder.ID, tickets[0].OrderID)
+}
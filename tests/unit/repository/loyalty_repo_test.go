@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoyaltyRepository_AccrueAndReverse(t *testing.T) {
+	tables := []string{"loyalty_ledger", "customer", "client_setting", "kitchen_ticket", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	assert.NoError(t, db.Create(&entity.ClientSetting{ClientID: 1, LoyaltySpendPerPoint: 1}).Error)
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
+
+	now := time.Now()
+	order := &entity.Order{
+		ClientID:     1,
+		CustomerName: "John Doe",
+		PhoneNumber:  "+6281234567890",
+		Total:        100.0,
+		Status:       model.OrderStatusIncoming,
+		ReleasedAt:   &now,
+		CreatedAt:    now,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 50.0, Quantity: 2, Total: 100.0},
+		},
+	}
+
+	createdOrder, err := orderRepo.AddOrder(ctx, order)
+	assert.NoError(t, err)
+	assert.NotNil(t, createdOrder.CustomerID)
+	customerID := *createdOrder.CustomerID
+
+	// No points are earned before the order is paid
+	balance, err := loyaltyRepo.GetBalance(ctx, customerID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, balance)
+
+	paidOrder, err := orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: createdOrder.ID, PaymentMethod: model.PaymentMethodCash}, model.OrderStatusPaid)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, paidOrder.PointsEarned)
+
+	// Completing the order does not earn the points again
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: createdOrder.ID}, model.OrderStatusProcessing)
+	assert.NoError(t, err)
+	balance, err = loyaltyRepo.GetBalance(ctx, customerID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, balance)
+
+	// Lowering the total of the paid order removes the points of the difference
+	edit := &entity.Order{
+		ID:           createdOrder.ID,
+		ClientID:     1,
+		CustomerName: "John Doe",
+		PhoneNumber:  "+6281234567890",
+		Total:        60.0,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 30.0, Quantity: 2, Total: 60.0},
+		},
+	}
+	_, err = orderRepo.EditOrder(ctx, edit)
+	assert.NoError(t, err)
+
+	var storedOrder entity.Order
+	assert.NoError(t, db.First(&storedOrder, createdOrder.ID).Error)
+	assert.Equal(t, 60, storedOrder.PointsEarned)
+	balance, err = loyaltyRepo.GetBalance(ctx, customerID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, balance)
+
+	// Raising it again credits the difference
+	edit.Total = 80.0
+	edit.OrderDetails = []entity.OrderDetail{{ProductID: 1, Price: 40.0, Quantity: 2, Total: 80.0}}
+	_, err = orderRepo.EditOrder(ctx, edit)
+	assert.NoError(t, err)
+	balance, err = loyaltyRepo.GetBalance(ctx, customerID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 80, balance)
+
+	// Cancelling the paid order removes the points it earned
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: createdOrder.ID}, model.OrderStatusCancelled)
+	assert.NoError(t, err)
+	balance, err = loyaltyRepo.GetBalance(ctx, customerID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, balance)
+}
@@ -2,6 +2,8 @@ package repository_test
 
 import (
 	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
 	"testing"
 	"time"
 
@@ -199,3 +201,131 @@ func TestOrderRepository_EditOrderDoubleClient(t *testing.T) {
 	assert.Equal(t, newOrder.OrderDetails[1].Quantity, orders.OrderDetails[1].Quantity)
 
 }
+
+func TestOrderRepository_UpdatePrepStatus(t *testing.T) {
+	tables := []string{"order_detail", "`order`"}
+	defer clearDB(tables)
+
+	order := &entity.Order{
+		ClientID:     1,
+		CustomerName: "John Doe",
+		PhoneNumber:  "123456789",
+		Total:        200.0,
+		Status:       model.OrderStatusPaid,
+		CreatedAt:    time.Now(),
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+			{ProductID: 2, Price: 75.0, Quantity: 2, Total: 150.0},
+		},
+	}
+
+	createdOrder, err := orderRepo.AddOrder(ctx, order)
+	assert.NoError(t, err)
+
+	// Starting the first line moves the order to Processing
+	updatedOrder, err := orderRepo.UpdatePrepStatus(ctx, createdOrder.OrderDetails[0].ID, model.PrepStatusStarted)
+	assert.NoError(t, err)
+	assert.Equal(t, model.OrderStatusProcessing, updatedOrder.Status)
+	assert.NotNil(t, updatedOrder.ProcessingAt)
+	assert.NotNil(t, updatedOrder.OrderDetails[0].StartedAt)
+
+	updatedOrder, err = orderRepo.UpdatePrepStatus(ctx, createdOrder.OrderDetails[0].ID, model.PrepStatusReady)
+	assert.NoError(t, err)
+	assert.Equal(t, model.OrderStatusProcessing, updatedOrder.Status)
+
+	// The order is finished once every line is ready
+	updatedOrder, err = orderRepo.UpdatePrepStatus(ctx, createdOrder.OrderDetails[1].ID, model.PrepStatusReady)
+	assert.NoError(t, err)
+	assert.Equal(t, model.OrderStatusSuccess, updatedOrder.Status)
+	assert.NotNil(t, updatedOrder.CompletedAt)
+	assert.NotNil(t, updatedOrder.OrderDetails[1].StartedAt)
+	assert.NotNil(t, updatedOrder.OrderDetails[1].ReadyAt)
+}
+
+func TestOrderRepository_UpdatePrepStatusNotPaid(t *testing.T) {
+	tables := []string{"order_detail", "`order`"}
+	defer clearDB(tables)
+
+	now := time.Now()
+	order := &entity.Order{
+		ClientID:     1,
+		CustomerName: "John Doe",
+		PhoneNumber:  "123456789",
+		Total:        50.0,
+		Status:       model.OrderStatusIncoming,
+		ReleasedAt:   &now,
+		CreatedAt:    now,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+		},
+	}
+
+	createdOrder, err := orderRepo.AddOrder(ctx, order)
+	assert.NoError(t, err)
+
+	// The kitchen can not complete an order whose payment was never recorded
+	_, err = orderRepo.UpdatePrepStatus(ctx, createdOrder.OrderDetails[0].ID, model.PrepStatusReady)
+	assert.ErrorIs(t, err, repository.ErrOrderNotPaid)
+
+	var storedOrder entity.Order
+	assert.NoError(t, db.First(&storedOrder, createdOrder.ID).Error)
+	assert.Equal(t, model.OrderStatusIncoming, storedOrder.Status)
+	assert.Nil(t, storedOrder.CompletedAt)
+	assert.Zero(t, storedOrder.PointsEarned)
+
+	var detail entity.OrderDetail
+	assert.NoError(t, db.First(&detail, createdOrder.OrderDetails[0].ID).Error)
+	assert.Equal(t, model.PrepStatusPending, detail.PrepStatus)
+}
+
+func TestOrderRepository_EditOrderStarted(t *testing.T) {
+	tables := []string{"kitchen_ticket", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	now := time.Now()
+	order := &entity.Order{
+		ClientID:     1,
+		CustomerName: "John Doe",
+		Total:        100.0,
+		Status:       model.OrderStatusPaid,
+		ReleasedAt:   &now,
+		CreatedAt:    now,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 50.0, Quantity: 2, Total: 100.0},
+		},
+	}
+
+	createdOrder, err := orderRepo.AddOrder(ctx, order)
+	assert.NoError(t, err)
+
+	// An edit keeps the status of the order
+	edit := &entity.Order{
+		ID:           createdOrder.ID,
+		ClientID:     1,
+		CustomerName: "John Doe",
+		Total:        50.0,
+		Status:       model.OrderStatusIncoming,
+		OrderDetails: []entity.OrderDetail{
+			{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+		},
+	}
+	_, err = orderRepo.EditOrder(ctx, edit)
+	assert.NoError(t, err)
+
+	var storedOrder entity.Order
+	assert.NoError(t, db.Preload("OrderDetails").First(&storedOrder, createdOrder.ID).Error)
+	assert.Equal(t, model.OrderStatusPaid, storedOrder.Status)
+
+	// Once the kitchen started a line the order can no longer be edited
+	_, err = orderRepo.UpdatePrepStatus(ctx, storedOrder.OrderDetails[0].ID, model.PrepStatusStarted)
+	assert.NoError(t, err)
+
+	edit.Total = 100.0
+	edit.OrderDetails = []entity.OrderDetail{{ProductID: 1, Price: 50.0, Quantity: 2, Total: 100.0}}
+	_, err = orderRepo.EditOrder(ctx, edit)
+	assert.ErrorIs(t, err, repository.ErrInvalidOrderStatus)
+
+	var detail entity.OrderDetail
+	assert.NoError(t, db.First(&detail, storedOrder.OrderDetails[0].ID).Error)
+	assert.Equal(t, model.PrepStatusStarted, detail.PrepStatus)
+}
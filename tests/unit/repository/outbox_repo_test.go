@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository_GetPendingEventsBackoff(t *testing.T) {
+	tables := []string{"order_outbox"}
+	defer clearDB(tables)
+
+	now := time.Now().Truncate(time.Second)
+	events := []*entity.OutboxEvent{
+		{Sink: model.OutboxSinkPublisher, ClientID: 1, OrderID: 10, Type: "order.created", Payload: "{}", NextAttemptAt: now},
+		{Sink: model.OutboxSinkPublisher, ClientID: 1, OrderID: 10, Type: "order.status_changed", Payload: "{}", NextAttemptAt: now},
+		{Sink: model.OutboxSinkPublisher, ClientID: 1, OrderID: 20, Type: "order.created", Payload: "{}", NextAttemptAt: now},
+		{Sink: model.OutboxSinkPublisher, ClientID: 1, OrderID: 30, Type: "order.created", Payload: "{}", NextAttemptAt: now},
+	}
+	for _, e := range events {
+		assert.NoError(t, db.Create(e).Error)
+	}
+
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	// The first event of order 10 backs off, the one of order 30 is dead
+	events[0].Attempts = 1
+	events[0].LastError = "unavailable"
+	events[0].NextAttemptAt = now.Add(time.Minute)
+	assert.NoError(t, outboxRepo.MarkFailed(ctx, events[0]))
+	events[3].Attempts = 10
+	events[3].DeadAt = &now
+	assert.NoError(t, outboxRepo.MarkFailed(ctx, events[3]))
+
+	pending, err := outboxRepo.GetPendingEvents(ctx, model.OutboxSinkPublisher, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, events[2].ID, pending[0].ID)
+
+	// Both events of order 10 are due once the backoff has passed
+	pending, err = outboxRepo.GetPendingEvents(ctx, model.OutboxSinkPublisher, now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 3)
+	assert.Equal(t, events[0].ID, pending[0].ID)
+	assert.Equal(t, 1, pending[0].Attempts)
+}
+
+func TestOutboxRepository_Sinks(t *testing.T) {
+	tables := []string{"order_outbox", "kitchen_ticket", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	now := time.Now()
+	_, err := orderRepo.AddOrder(ctx, &entity.Order{ClientID: 1, CustomerName: "John Doe", Total: 10.0, ReleasedAt: &now,
+		OrderDetails: []entity.OrderDetail{{ProductID: 1, Price: 10.0, Quantity: 1, Total: 10.0}}})
+	assert.NoError(t, err)
+
+	outboxRepo := repository.NewOutboxRepository(db)
+	later := now.Add(time.Second)
+
+	publisherEvents, err := outboxRepo.GetPendingEvents(ctx, model.OutboxSinkPublisher, later, 10)
+	assert.NoError(t, err)
+	assert.Len(t, publisherEvents, 1)
+	webhookEvents, err := outboxRepo.GetPendingEvents(ctx, model.OutboxSinkWebhook, later, 10)
+	assert.NoError(t, err)
+	assert.Len(t, webhookEvents, 1)
+
+	// A sink that fails does not hold back the other sink
+	publisherEvents[0].Attempts = 1
+	publisherEvents[0].NextAttemptAt = later.Add(time.Hour)
+	assert.NoError(t, outboxRepo.MarkFailed(ctx, publisherEvents[0]))
+	assert.NoError(t, outboxRepo.MarkPublished(ctx, webhookEvents[0].ID))
+
+	pending, err := outboxRepo.GetPendingEvents(ctx, model.OutboxSinkPublisher, later, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = outboxRepo.GetPendingEvents(ctx, model.OutboxSinkWebhook, later, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = outboxRepo.GetPendingEvents(ctx, model.OutboxSinkPublisher, later.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
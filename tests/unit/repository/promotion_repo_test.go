@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotionRepository_AddPromotionDuplicateCode(t *testing.T) {
+	tables := []string{"promotion", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	promotionRepo := repository.NewPromotionRepository(db)
+
+	_, err := promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Code: "HEMAT10", Name: "Hemat", Type: model.PromotionTypePercentage, Value: 10, IsActive: true})
+	assert.NoError(t, err)
+
+	_, err = promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Code: "HEMAT10", Name: "Hemat again", Type: model.PromotionTypePercentage, Value: 20, IsActive: true})
+	assert.ErrorIs(t, err, repository.ErrPromoCodeExists)
+
+	// Automatic promotions without a code do not collide
+	_, err = promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Name: "Morning", Type: model.PromotionTypePercentage, Value: 5, AutoApply: true, IsActive: true})
+	assert.NoError(t, err)
+	_, err = promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Name: "Evening", Type: model.PromotionTypePercentage, Value: 5, AutoApply: true, IsActive: true})
+	assert.NoError(t, err)
+}
+
+func TestPromotionRepository_UsageLimit(t *testing.T) {
+	tables := []string{"promotion_redemption", "promotion", "order_detail", "`order`", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotion, err := promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Code: "ONCE", Name: "Once", Type: model.PromotionTypePercentage, Value: 10, UsageLimit: 1, IsActive: true})
+	assert.NoError(t, err)
+
+	newOrder := func() *entity.Order {
+		return &entity.Order{
+			ClientID:     client.ID,
+			CustomerName: "John Doe",
+			Total:        45.0,
+			Status:       model.OrderStatusIncoming,
+			OrderDetails: []entity.OrderDetail{
+				{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+			},
+			PromotionRedemptions: []entity.PromotionRedemption{
+				{PromotionID: promotion.ID, ClientID: client.ID, Discount: 5.0},
+			},
+		}
+	}
+
+	firstOrder, err := orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+
+	// The last redemption is taken, even when the service counted before the first order was added
+	_, err = orderRepo.AddOrder(ctx, newOrder())
+	assert.ErrorIs(t, err, repository.ErrPromoUsageLimit)
+
+	// An edit of the order holding the redemption does not count against itself
+	_, err = orderRepo.EditOrder(ctx, firstOrder)
+	assert.NoError(t, err)
+}
+
+func TestPromotionRepository_UsageLimitCancelled(t *testing.T) {
+	tables := []string{"promotion_redemption", "promotion", "order_outbox", "kitchen_ticket", "order_detail", "`order`", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotion, err := promotionRepo.AddPromotion(ctx, &entity.Promotion{ClientID: client.ID, Code: "ONCE", Name: "Once", Type: model.PromotionTypePercentage, Value: 10, UsageLimit: 1, PerCustomerLimit: 1, IsActive: true})
+	assert.NoError(t, err)
+
+	newOrder := func() *entity.Order {
+		return &entity.Order{
+			ClientID:     client.ID,
+			CustomerName: "John Doe",
+			PhoneNumber:  "+6281234567890",
+			Total:        45.0,
+			Status:       model.OrderStatusIncoming,
+			OrderDetails: []entity.OrderDetail{
+				{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0},
+			},
+			PromotionRedemptions: []entity.PromotionRedemption{
+				{PromotionID: promotion.ID, ClientID: client.ID, PhoneNumber: "+6281234567890", Discount: 5.0},
+			},
+		}
+	}
+
+	firstOrder, err := orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+	count, err := promotionRepo.CountRedemptions(ctx, promotion.ID, "+6281234567890", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Cancelling the order gives the redemption back to the total and the customer limit
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: firstOrder.ID}, model.OrderStatusCancelled)
+	assert.NoError(t, err)
+	count, err = promotionRepo.CountRedemptions(ctx, promotion.ID, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = promotionRepo.CountRedemptions(ctx, promotion.ID, "+6281234567890", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	_, err = orderRepo.AddOrder(ctx, newOrder())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,35 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository_GetDailySalesTimezone(t *testing.T) {
+	tables := []string{"order_detail", "`order`"}
+	defer clearDB(tables)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+
+	// 20:00 UTC on the first is 03:00 on the second in Jakarta
+	createdAt := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	order := entity.Order{ClientID: 1, OrderNumber: "ORD-0001", CustomerName: "John Doe", Status: model.OrderStatusSuccess, Total: 50.0, CreatedAt: createdAt,
+		OrderDetails: []entity.OrderDetail{{ProductID: 1, Price: 50.0, Quantity: 1, Total: 50.0}}}
+	assert.NoError(t, db.Create(&order).Error)
+
+	reportRepo := repository.NewReportRepository(db)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, jakarta)
+	rows, err := reportRepo.GetDailySales(ctx, 1, from, from.AddDate(0, 0, 2), jakarta)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "2024-01-02", rows[0].Date)
+	assert.Equal(t, 1, rows[0].OrderCount)
+	assert.Equal(t, 50.0, rows[0].Total)
+	assert.Equal(t, 50.0, rows[0].GrossSales)
+}
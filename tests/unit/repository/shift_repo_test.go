@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftRepository_CashMovements(t *testing.T) {
+	tables := []string{"shift_cash_movement", "shift", "order_outbox", "kitchen_ticket", "order_detail", "`order`", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	shiftRepo := repository.NewShiftRepository(db)
+	shift, err := shiftRepo.OpenShift(ctx, &entity.Shift{ClientID: client.ID, BusinessDate: time.Now().Format("2006-01-02"), OpenedBy: "Ani", OpeningCash: 100.0, OpenedAt: time.Now()})
+	assert.NoError(t, err)
+
+	_, err = shiftRepo.OpenShift(ctx, &entity.Shift{ClientID: client.ID, OpenedBy: "Budi", OpenedAt: time.Now()})
+	assert.ErrorIs(t, err, repository.ErrShiftAlreadyOpen)
+
+	now := time.Now()
+	newOrder := func(total float64) *entity.Order {
+		return &entity.Order{
+			ClientID:     client.ID,
+			CustomerName: "John Doe",
+			Total:        total,
+			Status:       model.OrderStatusIncoming,
+			ReleasedAt:   &now,
+			OrderDetails: []entity.OrderDetail{
+				{ProductID: 1, Price: total, Quantity: 1, Total: total},
+			},
+		}
+	}
+
+	// Cash is taken when the order is paid
+	cashOrder, err := orderRepo.AddOrder(ctx, newOrder(50.0))
+	assert.NoError(t, err)
+	paidOrder, err := orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: cashOrder.ID, PaymentMethod: model.PaymentMethodCash}, model.OrderStatusPaid)
+	assert.NoError(t, err)
+	assert.Equal(t, shift.ID, *paidOrder.ShiftID)
+
+	// Editing the paid order takes or pays back the difference
+	edit := newOrder(70.0)
+	edit.ID = cashOrder.ID
+	_, err = orderRepo.EditOrder(ctx, edit)
+	assert.NoError(t, err)
+
+	// Card payments do not move cash
+	cardOrder, err := orderRepo.AddOrder(ctx, newOrder(30.0))
+	assert.NoError(t, err)
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: cardOrder.ID, PaymentMethod: model.PaymentMethodCard}, model.OrderStatusPaid)
+	assert.NoError(t, err)
+
+	// Cancelling a cash order after payment pays the cash back
+	refundOrder, err := orderRepo.AddOrder(ctx, newOrder(20.0))
+	assert.NoError(t, err)
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: refundOrder.ID, PaymentMethod: model.PaymentMethodCash}, model.OrderStatusPaid)
+	assert.NoError(t, err)
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: refundOrder.ID}, model.OrderStatusCancelled)
+	assert.NoError(t, err)
+
+	var movements []entity.ShiftCashMovement
+	assert.NoError(t, db.Where("shift_id = ?", shift.ID).Order("id ASC").Find(&movements).Error)
+	assert.Len(t, movements, 4)
+
+	closedShift, err := shiftRepo.CloseShift(ctx, client.ID, 185.0, "Ani", "", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, model.ShiftStatusClosed, closedShift.Status)
+	assert.Equal(t, 70.0, closedShift.CashSales)
+	assert.Equal(t, 20.0, closedShift.CashRefunds)
+	assert.Equal(t, 20.0, closedShift.CashAdjustments)
+	assert.Equal(t, 170.0, closedShift.ExpectedCash)
+	assert.Equal(t, 15.0, *closedShift.Variance)
+
+	_, err = shiftRepo.CloseShift(ctx, client.ID, 0, "Ani", "", time.Now())
+	assert.ErrorIs(t, err, repository.ErrShiftNotFound)
+}
+
+func TestShiftRepository_CloseShiftLocksOrders(t *testing.T) {
+	tables := []string{"shift_cash_movement", "shift", "order_outbox", "kitchen_ticket", "order_detail", "`order`", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	shiftRepo := repository.NewShiftRepository(db)
+	_, err := shiftRepo.OpenShift(ctx, &entity.Shift{ClientID: client.ID, BusinessDate: time.Now().Format("2006-01-02"), OpenedBy: "Ani", OpenedAt: time.Now()})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	later := now.Add(24 * time.Hour)
+	servedOrder, err := orderRepo.AddOrder(ctx, &entity.Order{ClientID: client.ID, CustomerName: "John Doe", Total: 10.0, Status: model.OrderStatusIncoming, ReleasedAt: &now,
+		OrderDetails: []entity.OrderDetail{{ProductID: 1, Price: 10.0, Quantity: 1, Total: 10.0}}})
+	assert.NoError(t, err)
+	scheduledOrder, err := orderRepo.AddOrder(ctx, &entity.Order{ClientID: client.ID, CustomerName: "Jane Doe", Total: 10.0, Status: model.OrderStatusIncoming, ScheduledAt: &later,
+		OrderDetails: []entity.OrderDetail{{ProductID: 1, Price: 10.0, Quantity: 1, Total: 10.0}}})
+	assert.NoError(t, err)
+
+	_, err = shiftRepo.CloseShift(ctx, client.ID, 0, "Ani", "", time.Now().Add(time.Second))
+	assert.NoError(t, err)
+
+	// The orders of the closed day can no longer be cancelled, orders for a later pickup can
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: servedOrder.ID}, model.OrderStatusCancelled)
+	assert.ErrorIs(t, err, repository.ErrOrderLocked)
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: scheduledOrder.ID}, model.OrderStatusCancelled)
+	assert.NoError(t, err)
+}
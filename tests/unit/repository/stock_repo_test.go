@@ -0,0 +1,72 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockRepository_ReserveAndSettle(t *testing.T) {
+	tables := []string{"product_stock", "order_outbox", "kitchen_ticket", "order_detail", "`order`"}
+	defer clearDB(tables)
+
+	stockRepo := repository.NewStockRepository(db)
+	_, err := stockRepo.SetStock(ctx, 1, 1, 3)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	newOrder := func(quantity int) *entity.Order {
+		return &entity.Order{
+			ClientID:     1,
+			CustomerName: "John Doe",
+			Total:        float64(quantity) * 10.0,
+			Status:       model.OrderStatusIncoming,
+			ReleasedAt:   &now,
+			OrderDetails: []entity.OrderDetail{
+				{ProductID: 1, Price: 10.0, Quantity: quantity, Total: float64(quantity) * 10.0},
+			},
+		}
+	}
+	stock := func() entity.ProductStock {
+		var stock entity.ProductStock
+		assert.NoError(t, db.Where("client_id = ? AND product_id = ?", 1, 1).First(&stock).Error)
+		return stock
+	}
+
+	firstOrder, err := orderRepo.AddOrder(ctx, newOrder(2))
+	assert.NoError(t, err)
+	assert.True(t, firstOrder.StockReserved)
+	assert.Equal(t, 2, stock().Reserved)
+
+	// Only one item is left for other orders
+	_, err = orderRepo.AddOrder(ctx, newOrder(2))
+	var outOfStock *repository.OutOfStockError
+	assert.ErrorAs(t, err, &outOfStock)
+	assert.Equal(t, 2, stock().Reserved)
+
+	// Cancelling returns the reservation, the stock on hand is unchanged
+	_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: firstOrder.ID}, model.OrderStatusCancelled)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stock().Reserved)
+	assert.Equal(t, 3, stock().OnHand)
+
+	secondOrder, err := orderRepo.AddOrder(ctx, newOrder(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stock().Reserved)
+
+	// Completing takes the reservation from the stock on hand, once
+	for _, status := range []int{model.OrderStatusPaid, model.OrderStatusProcessing, model.OrderStatusSuccess} {
+		_, err = orderRepo.UpdateOrderStatus(ctx, &entity.Order{ID: secondOrder.ID, PaymentMethod: model.PaymentMethodCard}, status)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 0, stock().Reserved)
+	assert.Equal(t, 0, stock().OnHand)
+
+	var storedOrder entity.Order
+	assert.NoError(t, db.First(&storedOrder, secondOrder.ID).Error)
+	assert.False(t, storedOrder.StockReserved)
+}
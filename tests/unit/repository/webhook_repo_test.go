@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository_DeactivateSubscription(t *testing.T) {
+	tables := []string{"webhook_delivery", "webhook_subscription", "client"}
+	defer clearDB(tables)
+
+	client := SampleClient()
+	assert.NoError(t, db.Create(client).Error)
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	subscription, err := webhookRepo.AddSubscription(ctx, &entity.WebhookSubscription{ClientID: client.ID, URL: "https://example.com/hook", EventTypes: "order.created", Secret: "secret", IsActive: true})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, webhookRepo.AddDeliveries(ctx, []*entity.WebhookDelivery{
+		{SubscriptionID: subscription.ID, ClientID: client.ID, EventID: 1, EventType: "order.created", Payload: `{"id":1}`, Status: model.WebhookDeliveryPending, NextAttemptAt: now},
+		{SubscriptionID: subscription.ID, ClientID: client.ID, EventID: 2, EventType: "order.created", Payload: `{"id":2}`, Status: model.WebhookDeliveryDelivered, NextAttemptAt: now},
+	}))
+
+	// Another client cannot deactivate the subscription
+	assert.ErrorIs(t, webhookRepo.DeactivateSubscription(ctx, client.ID+1, subscription.ID), repository.ErrWebhookNotFound)
+
+	assert.NoError(t, webhookRepo.DeactivateSubscription(ctx, client.ID, subscription.ID))
+	// Deactivating again is not an error
+	assert.NoError(t, webhookRepo.DeactivateSubscription(ctx, client.ID, subscription.ID))
+
+	subscriptions, err := webhookRepo.GetSubscriptions(ctx, client.ID)
+	assert.NoError(t, err)
+	assert.False(t, subscriptions[0].IsActive)
+
+	due, err := webhookRepo.GetDueDeliveries(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	deliveries, err := webhookRepo.GetDeliveries(ctx, client.ID, model.WebhookDeliveryDelivered)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
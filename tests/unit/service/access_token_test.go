@@ -0,0 +1,35 @@
+package service_test
+
+import (
+	"maqhaa/order_service/internal/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	token := service.SignAccessToken(secret, service.TokenScopeBoard, 7, now.Add(time.Hour))
+
+	clientID, err := service.ParseAccessToken(secret, service.TokenScopeBoard, token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), clientID)
+
+	_, err = service.ParseAccessToken(secret, service.TokenScopeBoard, token, now.Add(time.Hour))
+	assert.ErrorIs(t, err, service.ErrInvalidAccessToken)
+	_, err = service.ParseAccessToken([]byte("other"), service.TokenScopeBoard, token, now)
+	assert.ErrorIs(t, err, service.ErrInvalidAccessToken)
+
+	// A board token does not open the order event stream
+	_, err = service.ParseAccessToken(secret, service.TokenScopeEvents, token, now)
+	assert.ErrorIs(t, err, service.ErrInvalidAccessToken)
+
+	// The client ID can not be changed without the secret
+	tampered := "8" + token[1:]
+	_, err = service.ParseAccessToken(secret, service.TokenScopeBoard, tampered, now)
+	assert.ErrorIs(t, err, service.ErrInvalidAccessToken)
+	_, err = service.ParseAccessToken(secret, service.TokenScopeBoard, "7.1", now)
+	assert.ErrorIs(t, err, service.ErrInvalidAccessToken)
+}
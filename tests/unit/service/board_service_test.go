@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/event"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardMessageFromEvent(t *testing.T) {
+	completedAt := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		event    event.Event
+		expected string
+	}{
+		{"processing", event.Event{Type: event.OrderStatusChanged, Order: &entity.Order{ID: 1, QueueNumber: 7, Status: model.OrderStatusProcessing}}, model.BoardMessageNowServing},
+		{"success", event.Event{Type: event.OrderStatusChanged, Order: &entity.Order{ID: 1, QueueNumber: 7, Status: model.OrderStatusSuccess, CompletedAt: &completedAt}}, model.BoardMessageReady},
+		{"cancelled", event.Event{Type: event.OrderCancelled, Order: &entity.Order{ID: 1, QueueNumber: 7, Status: model.OrderStatusCancelled}}, model.BoardMessageRemoved},
+		{"paid", event.Event{Type: event.OrderStatusChanged, Order: &entity.Order{ID: 1, QueueNumber: 7, Status: model.OrderStatusPaid}}, ""},
+		{"created", event.Event{Type: event.OrderCreated, Order: &entity.Order{ID: 1, QueueNumber: 7, Status: model.OrderStatusIncoming}}, ""},
+		{"without order", event.Event{Type: event.OrderStatusChanged}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := service.BoardMessageFromEvent(test.event)
+			if test.expected == "" {
+				assert.Nil(t, message)
+				return
+			}
+
+			assert.NotNil(t, message)
+			assert.Equal(t, test.expected, message.Type)
+			assert.Equal(t, 7, message.Entry.QueueNumber)
+		})
+	}
+
+	message := service.BoardMessageFromEvent(event.Event{Type: event.OrderStatusChanged, Order: &entity.Order{Status: model.OrderStatusSuccess, CompletedAt: &completedAt}})
+	assert.Equal(t, completedAt, message.Entry.UpdatedAt)
+}
@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateCharges_NoSetting(t *testing.T) {
+	charges := service.CalculateCharges(&entity.ClientSetting{}, 100000, 10000)
+
+	assert.Equal(t, 100000.0, charges.Subtotal)
+	assert.Equal(t, 10000.0, charges.Discount)
+	assert.Equal(t, 0.0, charges.ServiceCharge)
+	assert.Equal(t, 0.0, charges.Tax)
+	assert.Equal(t, 90000.0, charges.GrandTotal)
+}
+
+func TestCalculateCharges_ExclusiveTax(t *testing.T) {
+	setting := &entity.ClientSetting{TaxRate: 10, ServiceChargeRate: 5, TaxOnServiceCharge: true}
+
+	charges := service.CalculateCharges(setting, 100000, 0)
+	assert.Equal(t, 5000.0, charges.ServiceCharge)
+	assert.Equal(t, 10500.0, charges.Tax)
+	assert.Equal(t, 115500.0, charges.GrandTotal)
+
+	setting.TaxOnServiceCharge = false
+	charges = service.CalculateCharges(setting, 100000, 0)
+	assert.Equal(t, 10000.0, charges.Tax)
+	assert.Equal(t, 115000.0, charges.GrandTotal)
+}
+
+func TestCalculateCharges_InclusiveTax(t *testing.T) {
+	setting := &entity.ClientSetting{TaxRate: 10, TaxInclusive: true}
+
+	charges := service.CalculateCharges(setting, 110000, 0)
+	assert.Equal(t, 10000.0, charges.Tax)
+	assert.Equal(t, 110000.0, charges.GrandTotal)
+}
+
+func TestCalculateCharges_Rounding(t *testing.T) {
+	setting := &entity.ClientSetting{TaxRate: 10, RoundingMode: "nearest", RoundingIncrement: 100}
+
+	charges := service.CalculateCharges(setting, 23450, 0)
+	assert.Equal(t, 2345.0, charges.Tax)
+	assert.Equal(t, 25800.0, charges.GrandTotal)
+	assert.Equal(t, 5.0, charges.RoundingAdjustment)
+
+	setting.RoundingMode = "down"
+	charges = service.CalculateCharges(setting, 23450, 0)
+	assert.Equal(t, 25700.0, charges.GrandTotal)
+	assert.Equal(t, -95.0, charges.RoundingAdjustment)
+}
+
+func TestCalculateCharges_MinorUnits(t *testing.T) {
+	// IDR has no minor units
+	setting := &entity.ClientSetting{TaxRate: 11}
+	charges := service.CalculateCharges(setting, 23455, 0)
+	assert.Equal(t, 2580.0, charges.Tax)
+	assert.Equal(t, 26035.0, charges.GrandTotal)
+
+	// KWD has three
+	setting = &entity.ClientSetting{CurrencyCode: "KWD", TaxRate: 5}
+	charges = service.CalculateCharges(setting, 1.235, 0)
+	assert.Equal(t, 0.062, charges.Tax)
+	assert.Equal(t, 1.297, charges.GrandTotal)
+
+	setting = &entity.ClientSetting{CurrencyCode: "USD", TaxRate: 5}
+	charges = service.CalculateCharges(setting, 1.23, 0)
+	assert.Equal(t, 0.06, charges.Tax)
+	assert.Equal(t, 1.29, charges.GrandTotal)
+}
+
+func TestRoundTotal(t *testing.T) {
+	assert.Equal(t, 12300.0, service.RoundTotal(12345, "nearest", 100))
+	assert.Equal(t, 12400.0, service.RoundTotal(12345, "up", 100))
+	assert.Equal(t, 12300.0, service.RoundTotal(12345, "down", 100))
+	assert.Equal(t, 12345.0, service.RoundTotal(12345, "none", 100))
+	assert.Equal(t, 12345.0, service.RoundTotal(12345, "nearest", 0))
+	assert.Equal(t, 12300.0, service.RoundTotal(12300, "up", 100))
+}
+
+func TestCalculateCharges_OrderCurrency(t *testing.T) {
+	// The client moved from KWD to IDR after the order was created
+	setting := &entity.ClientSetting{CurrencyCode: "IDR", TaxRate: 5, RoundingMode: "nearest", RoundingIncrement: 100}
+
+	charges := service.CalculateCharges(service.OrderSetting(setting, "KWD"), 1.235, 0)
+	assert.Equal(t, 0.062, charges.Tax)
+	assert.Equal(t, 1.297, charges.GrandTotal)
+	assert.Equal(t, 0.0, charges.RoundingAdjustment)
+
+	// The setting of the client is not changed
+	assert.Equal(t, "IDR", setting.CurrencyCode)
+	assert.Same(t, setting, service.OrderSetting(setting, "IDR"))
+}
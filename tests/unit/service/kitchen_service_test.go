@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteOrderDetails(t *testing.T) {
+	stations := []*entity.KitchenStation{
+		{ID: 1, Name: "Bar", Categories: []entity.KitchenStationCategory{{CategoryID: 1}, {CategoryID: 2}}},
+		{ID: 2, Name: "Grill", Categories: []entity.KitchenStationCategory{{CategoryID: 3}}},
+	}
+
+	details := []entity.OrderDetail{
+		{ProductID: 1, CategoryID: 1},
+		{ProductID: 4, CategoryID: 3},
+		{ProductID: 3, CategoryID: 2},
+		{ProductID: 9, CategoryID: 7},
+	}
+
+	tickets := service.RouteOrderDetails(stations, details)
+
+	assert.Len(t, tickets, 3)
+	assert.Equal(t, uint(1), tickets[0].StationID)
+	assert.Equal(t, uint(2), tickets[1].StationID)
+	assert.Equal(t, uint(0), tickets[2].StationID)
+
+	assert.Equal(t, uint(1), details[0].StationID)
+	assert.Equal(t, uint(2), details[1].StationID)
+	assert.Equal(t, uint(1), details[2].StationID)
+	assert.Equal(t, uint(0), details[3].StationID)
+}
+
+func TestKitchenQueue(t *testing.T) {
+	now := time.Now()
+	orders := []*entity.Order{
+		{ID: 1, Status: model.OrderStatusIncoming, ReleasedAt: &now},
+		{ID: 2, Status: model.OrderStatusPaid, ReleasedAt: &now},
+		{ID: 3, Status: model.OrderStatusPaid},
+		{ID: 4, Status: model.OrderStatusProcessing, ReleasedAt: &now},
+	}
+
+	// Unpaid orders and scheduled orders that are not released are not prepared yet
+	queue := service.KitchenQueue(orders)
+	if assert.Len(t, queue, 2) {
+		assert.Equal(t, model.OrderStatusPaid, queue[0].Status)
+		if assert.Len(t, queue[0].Orders, 1) {
+			assert.Equal(t, uint(2), queue[0].Orders[0].ID)
+		}
+		assert.Equal(t, model.OrderStatusProcessing, queue[1].Status)
+		assert.Len(t, queue[1].Orders, 1)
+	}
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleOrderDetails() []entity.OrderDetail {
+	return []entity.OrderDetail{
+		{ProductID: 1, Price: 25000, Quantity: 4, Total: 100000},
+		{ProductID: 2, Price: 30000, Quantity: 1, Total: 30000},
+	}
+}
+
+func TestCalculatePromotionDiscount_Percentage(t *testing.T) {
+	promotion := &entity.Promotion{Type: model.PromotionTypePercentage, Value: 10, IsActive: true}
+
+	discount, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), time.Now())
+	assert.Nil(t, appErr)
+	assert.Equal(t, 13000.0, discount)
+
+	promotion.MaxDiscount = 5000
+	discount, appErr = service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), time.Now())
+	assert.Nil(t, appErr)
+	assert.Equal(t, 5000.0, discount)
+}
+
+func TestCalculatePromotionDiscount_Fixed(t *testing.T) {
+	promotion := &entity.Promotion{
+		Type:              model.PromotionTypeFixed,
+		Value:             50000,
+		IsActive:          true,
+		PromotionProducts: []entity.PromotionProduct{{ProductID: 2}},
+	}
+
+	// The discount cannot exceed the total of the eligible lines
+	discount, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), time.Now())
+	assert.Nil(t, appErr)
+	assert.Equal(t, 30000.0, discount)
+}
+
+func TestCalculatePromotionDiscount_BOGO(t *testing.T) {
+	promotion := &entity.Promotion{
+		Type:              model.PromotionTypeBOGO,
+		BuyQuantity:       1,
+		GetQuantity:       1,
+		IsActive:          true,
+		PromotionProducts: []entity.PromotionProduct{{ProductID: 1}},
+	}
+
+	discount, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), time.Now())
+	assert.Nil(t, appErr)
+	assert.Equal(t, 50000.0, discount)
+}
+
+func TestCalculatePromotionDiscount_Bundle(t *testing.T) {
+	promotion := &entity.Promotion{
+		Type:              model.PromotionTypeBundle,
+		Value:             45000,
+		IsActive:          true,
+		PromotionProducts: []entity.PromotionProduct{{ProductID: 1}, {ProductID: 2}},
+	}
+
+	discount, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), time.Now())
+	assert.Nil(t, appErr)
+	assert.Equal(t, 10000.0, discount)
+}
+
+func TestCalculatePromotionDiscount_NotApplicable(t *testing.T) {
+	now := time.Date(2024, 2, 10, 18, 0, 0, 0, time.Local)
+
+	// Happy hour between 15:00 and 17:00
+	promotion := &entity.Promotion{Type: model.PromotionTypePercentage, Value: 10, HourStart: 15, HourEnd: 17, IsActive: true}
+	_, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), now)
+	assert.NotNil(t, appErr)
+	assert.Equal(t, service.PromoNotApplicable, appErr.Code)
+
+	// Minimum spend not reached
+	promotion = &entity.Promotion{Type: model.PromotionTypeFixed, Value: 10000, MinSpend: 200000, IsActive: true}
+	_, appErr = service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), now)
+	assert.NotNil(t, appErr)
+	assert.Equal(t, service.PromoNotApplicable, appErr.Code)
+
+	// Expired promotion
+	endAt := now.Add(-time.Hour)
+	promotion = &entity.Promotion{Type: model.PromotionTypeFixed, Value: 10000, EndAt: &endAt, IsActive: true}
+	_, appErr = service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), now)
+	assert.NotNil(t, appErr)
+	assert.Equal(t, service.PromoNotApplicable, appErr.Code)
+}
+
+func TestCalculatePromotionDiscount_ClientTimezone(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+	setting := &entity.ClientSetting{Timezone: "Asia/Jakarta"}
+
+	// 09:00 UTC is 16:00 in Jakarta, inside the happy hour of the client
+	now := time.Date(2024, 2, 10, 9, 0, 0, 0, time.UTC)
+	promotion := &entity.Promotion{Type: model.PromotionTypePercentage, Value: 10, HourStart: 15, HourEnd: 17, IsActive: true}
+
+	_, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), now)
+	assert.NotNil(t, appErr)
+
+	discount, appErr := service.CalculatePromotionDiscount(promotion, sampleOrderDetails(), now.In(model.ClientLocation(setting)))
+	assert.Nil(t, appErr)
+	assert.Greater(t, discount, 0.0)
+	assert.Equal(t, jakarta.String(), model.ClientLocation(setting).String())
+}
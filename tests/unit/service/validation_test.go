@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"maqhaa/order_service/internal/app/i18n"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/service"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusOK, service.HTTPStatus(service.SuccessError))
+	assert.Equal(t, http.StatusUnauthorized, service.HTTPStatus(service.InvalidToken))
+	assert.Equal(t, http.StatusBadRequest, service.HTTPStatus(service.InvalidTotal))
+	assert.Equal(t, http.StatusNotFound, service.HTTPStatus(service.OrderNotFound))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.InvalidOrderStatus))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.StoreClosed))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.OutOfStock))
+	assert.Equal(t, http.StatusNotFound, service.HTTPStatus(service.StockNotFound))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.ShiftAlreadyOpen))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.OrderLocked))
+	assert.Equal(t, http.StatusConflict, service.HTTPStatus(service.OrderNotPaid))
+	assert.Equal(t, http.StatusNotFound, service.HTTPStatus(service.WebhookNotFound))
+	assert.Equal(t, http.StatusInternalServerError, service.HTTPStatus(service.QueryError))
+
+	assert.Equal(t, http.StatusNotFound, service.NewProductNotFoundError().Status)
+}
+
+func TestValidationError_FieldErrors(t *testing.T) {
+	// Validation runs before any repository is used
+	orderService := service.NewOrderService(nil, nil, nil, nil, nil, nil)
+
+	_, appErr := orderService.AddOrder(context.Background(), "token", &model.OrderRequest{
+		ClientID: 1,
+		Total:    10,
+		Orders: []model.OrderDetail{
+			{ProductID: 1, Price: 5, Quantity: 2, Total: 10},
+			{ProductID: 2, Price: 5, Quantity: 0, Total: 10},
+		},
+	})
+
+	assert.Equal(t, service.InvalidRequestError, appErr.Code)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+	assert.Equal(t, []model.FieldError{
+		{Field: "customer_name", Rule: "required", Message: "customer_name is a required field"},
+		{Field: "Orders[1].quantity", Rule: "required", Message: "quantity is a required field"},
+	}, appErr.Errors)
+	assert.Equal(t, "Invalid Request customer_name, Orders[1].quantity", appErr.Message)
+
+	localized := appErr.Localize(i18n.Indonesian)
+	assert.Equal(t, "Permintaan Tidak Valid customer_name, Orders[1].quantity", localized.Message)
+	assert.Equal(t, []model.FieldError{
+		{Field: "customer_name", Rule: "required", Message: "customer_name wajib diisi"},
+		{Field: "Orders[1].quantity", Rule: "required", Message: "quantity wajib diisi"},
+	}, localized.Errors)
+}
+
+func TestUpdateSetting_Validation(t *testing.T) {
+	// Validation runs before any repository is used, fields that are not sent are not validated
+	clientService := service.NewClientService(nil)
+
+	taxRate := 150.0
+	_, appErr := clientService.UpdateSetting(context.Background(), "token", &model.ClientSettingRequest{TaxRate: &taxRate})
+	assert.Equal(t, service.InvalidRequestError, appErr.Code)
+	assert.Equal(t, []model.FieldError{
+		{Field: "tax_rate", Rule: "lte", Message: "tax_rate must be 100 or less"},
+	}, appErr.Errors)
+}
+
+func TestLocalize_CatalogMessages(t *testing.T) {
+	appErr := service.NewOrderNotFoundError().Localize(i18n.Indonesian)
+	assert.Equal(t, service.OrderNotFound, appErr.Code)
+	assert.Equal(t, http.StatusNotFound, appErr.Status)
+	assert.Equal(t, "Pesanan Tidak Ditemukan", appErr.Message)
+
+	// Unsupported languages keep the English message
+	assert.Equal(t, service.OrderNotFoundMessage, service.NewOrderNotFoundError().Localize("fr").Message)
+	assert.Equal(t, "Invalid Request station id", service.NewInvalidRequestError("station id").Localize(i18n.English).Message)
+}
+
+func TestOutOfStockError_Localize(t *testing.T) {
+	appErr := service.NewOutOfStockError([]model.StockShortage{
+		{Line: 1, ProductID: 7, Requested: 3, Available: 2},
+	})
+
+	assert.Equal(t, service.OutOfStock, appErr.Code)
+	assert.Equal(t, []model.FieldError{
+		{Field: "Orders[1].quantity", Rule: "stock", Message: "only 2 left in stock"},
+	}, appErr.Errors)
+
+	localized := appErr.Localize(i18n.Indonesian)
+	assert.Equal(t, "Stok Habis", localized.Message)
+	assert.Equal(t, "stok hanya tersisa 2", localized.Errors[0].Message)
+}
+
+// The English catalog must stay in line with the message constants.
+func TestLocalize_EnglishCatalog(t *testing.T) {
+	errs := []*service.AppError{
+		service.NewSuccessError(), service.NewGeneralSystemError(), service.NewUserNotFoundError(),
+		service.NewInvalidPasswordError(), service.NewInvalidFormatError(), service.NewInvalidTokenError(),
+		service.NewProductNotFoundError(), service.NewInvalidProductPriceError(), service.NewInvalidTotalError(),
+		service.NewPromoNotFoundError(), service.NewPromoNotApplicableError(), service.NewPromoUsageLimitError(),
+		service.NewCurrencyMismatchError(), service.NewOrderNotFoundError(), service.NewOrderDetailNotFoundError(),
+		service.NewInvalidOrderStatusError(), service.NewKitchenTicketNotFoundError(),
+		service.NewWebhookDeliveryNotFoundError(), service.NewCustomerNotFoundError(),
+		service.NewInsufficientPointsError(), service.NewInvalidScheduledTimeError(), service.NewStoreClosedError(),
+		service.NewHolidayNotFoundError(), service.NewOutOfStockError(nil), service.NewStockNotFoundError(),
+		service.NewIngredientNotFoundError(), service.NewShiftAlreadyOpenError(), service.NewShiftNotFoundError(),
+		service.NewOrderLockedError(), service.NewOrderNotPaidError(), service.NewWebhookNotFoundError(),
+		service.NewQueryDBError(), service.NewUpdateQueryDBError(), service.NewDateCategoryNotFoundError(),
+	}
+
+	for _, appErr := range errs {
+		assert.Equal(t, appErr.Message, i18n.ErrorMessage(i18n.English, appErr.Code, ""), "code %d", appErr.Code)
+		assert.NotEmpty(t, i18n.ErrorMessage(i18n.Indonesian, appErr.Code, ""), "code %d", appErr.Code)
+	}
+	for status := model.OrderStatusIncoming; status <= model.OrderStatusCancelled; status++ {
+		assert.Equal(t, model.OrderStatusText(status), i18n.StatusText(i18n.English, status))
+	}
+}
@@ -0,0 +1,278 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"maqhaa/library/logging"
+	"maqhaa/order_service/internal/app/entity"
+	"maqhaa/order_service/internal/app/model"
+	"maqhaa/order_service/internal/app/outbox"
+	"maqhaa/order_service/internal/app/webhook"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryWebhookRepository keeps subscriptions and deliveries in memory.
+type memoryWebhookRepository struct {
+	subscriptions []*entity.WebhookSubscription
+	deliveries    []*entity.WebhookDelivery
+}
+
+func (r *memoryWebhookRepository) AddSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	subscription.ID = uint(len(r.subscriptions) + 1)
+	r.subscriptions = append(r.subscriptions, subscription)
+	return subscription, nil
+}
+
+func (r *memoryWebhookRepository) GetSubscriptions(ctx context.Context, clientID uint) ([]*entity.WebhookSubscription, error) {
+	var subscriptions []*entity.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		if subscription.ClientID == clientID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) DeactivateSubscription(ctx context.Context, clientID uint, subscriptionID uint) error {
+	r.subscriptions[subscriptionID-1].IsActive = false
+	return nil
+}
+
+func (r *memoryWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		exists := false
+		for _, existing := range r.deliveries {
+			if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID {
+				exists = true
+			}
+		}
+		if !exists {
+			delivery.ID = uint(len(r.deliveries) + 1)
+			r.deliveries = append(r.deliveries, delivery)
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	var due []*entity.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			for _, subscription := range r.subscriptions {
+				if subscription.ID == delivery.SubscriptionID {
+					delivery.Subscription = subscription
+				}
+			}
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetDeliveries(ctx context.Context, clientID uint, status string) ([]*entity.WebhookDelivery, error) {
+	return r.deliveries, nil
+}
+
+func (r *memoryWebhookRepository) GetDeliveryByID(ctx context.Context, clientID uint, deliveryID uint) (*entity.WebhookDelivery, error) {
+	return r.deliveries[deliveryID-1], nil
+}
+
+func TestMain(m *testing.M) {
+	logging.InitLogger()
+	os.Exit(m.Run())
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := webhook.Sign("secret", 1700000000, body)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, webhook.Verify("secret", 1700000000, body, signature))
+	assert.False(t, webhook.Verify("other", 1700000000, body, signature))
+	assert.False(t, webhook.Verify("secret", 1700000001, body, signature))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhook.Backoff(30*time.Second, 1))
+	assert.Equal(t, 60*time.Second, webhook.Backoff(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, webhook.Backoff(30*time.Second, 4))
+	assert.Equal(t, webhook.MaxBackoff, webhook.Backoff(30*time.Second, 20))
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	repo := &memoryWebhookRepository{}
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: "http://a", EventTypes: "order.created,order.cancelled", IsActive: true})
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: "http://b", EventTypes: "order.status_changed", IsActive: true})
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: "http://c", EventTypes: "order.created", IsActive: false})
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 2, URL: "http://d", EventTypes: "order.created", IsActive: true})
+
+	publisher := webhook.NewPublisher(repo)
+	message := outbox.Message{ID: 5, Type: "order.created", ClientID: 1, OrderID: 10, Data: []byte(`{"id":10}`)}
+
+	assert.NoError(t, publisher.Publish(context.Background(), message))
+	// Relaying the same outbox event again does not duplicate the delivery
+	assert.NoError(t, publisher.Publish(context.Background(), message))
+
+	assert.Len(t, repo.deliveries, 1)
+	assert.Equal(t, uint(1), repo.deliveries[0].SubscriptionID)
+	assert.Equal(t, model.WebhookDeliveryPending, repo.deliveries[0].Status)
+}
+
+func TestDispatcher_ProcessBatch(t *testing.T) {
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		assert.True(t, webhook.Verify("secret", timestamp, body, r.Header.Get(webhook.SignatureHeader)))
+		assert.Equal(t, "order.created", r.Header.Get(webhook.EventHeader))
+
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: server.URL, EventTypes: "order.created", Secret: "secret", IsActive: true})
+	now := time.Now()
+	repo.AddDeliveries(context.Background(), []*entity.WebhookDelivery{
+		{SubscriptionID: 1, ClientID: 1, EventID: 5, EventType: "order.created", Payload: `{"id":5}`, Status: model.WebhookDeliveryPending, NextAttemptAt: now},
+	})
+
+	dispatcher := webhook.NewDispatcher(repo, time.Second, 3, time.Minute, time.Second, true)
+
+	delivered, err := dispatcher.ProcessBatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	delivery := repo.deliveries[0]
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+	assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+
+	// Not due before the backoff has passed
+	delivered, _ = dispatcher.ProcessBatch(context.Background(), now.Add(30*time.Second))
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 1, delivery.Attempts)
+
+	delivered, err = dispatcher.ProcessBatch(context.Background(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, model.WebhookDeliveryDelivered, delivery.Status)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestDispatcher_DeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: server.URL, EventTypes: "order.created", Secret: "secret", IsActive: true})
+	now := time.Now()
+	repo.AddDeliveries(context.Background(), []*entity.WebhookDelivery{
+		{SubscriptionID: 1, ClientID: 1, EventID: 5, EventType: "order.created", Payload: `{"id":5}`, Status: model.WebhookDeliveryPending, NextAttemptAt: now},
+	})
+
+	dispatcher := webhook.NewDispatcher(repo, time.Second, 2, time.Minute, time.Second, true)
+
+	dispatcher.ProcessBatch(context.Background(), now)
+	dispatcher.ProcessBatch(context.Background(), now.Add(time.Minute))
+
+	delivery := repo.deliveries[0]
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, model.WebhookDeliveryDead, delivery.Status)
+
+	// Dead deliveries are not retried
+	delivered, _ := dispatcher.ProcessBatch(context.Background(), now.Add(time.Hour))
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 2, delivery.Attempts)
+}
+
+func TestPublicIP(t *testing.T) {
+	assert.True(t, webhook.PublicIP(net.ParseIP("8.8.8.8")))
+	assert.True(t, webhook.PublicIP(net.ParseIP("2606:4700::1111")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("127.0.0.1")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("10.1.2.3")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("192.168.0.10")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("169.254.169.254")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("::1")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("fe80::1")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("::ffff:127.0.0.1")))
+	assert.False(t, webhook.PublicIP(net.ParseIP("0.0.0.0")))
+}
+
+func TestCheckURL(t *testing.T) {
+	assert.NoError(t, webhook.CheckURL(context.Background(), "https://8.8.8.8/hook"))
+	assert.ErrorIs(t, webhook.CheckURL(context.Background(), "http://127.0.0.1:8080/hook"), webhook.ErrPrivateAddress)
+	assert.ErrorIs(t, webhook.CheckURL(context.Background(), "http://169.254.169.254/latest/meta-data"), webhook.ErrPrivateAddress)
+	assert.ErrorIs(t, webhook.CheckURL(context.Background(), "http://localhost/hook"), webhook.ErrPrivateAddress)
+	assert.Error(t, webhook.CheckURL(context.Background(), "ftp://8.8.8.8/hook"))
+	assert.Error(t, webhook.CheckURL(context.Background(), "file:///etc/passwd"))
+}
+
+func TestDispatcher_PrivateAddress(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: server.URL, EventTypes: "order.created", Secret: "secret", IsActive: true})
+	now := time.Now()
+	repo.AddDeliveries(context.Background(), []*entity.WebhookDelivery{
+		{SubscriptionID: 1, ClientID: 1, EventID: 5, EventType: "order.created", Payload: `{"id":5}`, Status: model.WebhookDeliveryPending, NextAttemptAt: now},
+	})
+
+	// The test server listens on loopback, it is refused when dialing
+	dispatcher := webhook.NewDispatcher(repo, time.Second, 3, time.Minute, time.Second, false)
+
+	delivered, err := dispatcher.ProcessBatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 0, requests)
+	assert.Contains(t, repo.deliveries[0].LastError, webhook.ErrPrivateAddress.Error())
+}
+
+func TestDispatcher_InactiveSubscription(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	repo.AddSubscription(context.Background(), &entity.WebhookSubscription{ClientID: 1, URL: server.URL, EventTypes: "order.created", Secret: "secret", IsActive: true})
+	now := time.Now()
+	repo.AddDeliveries(context.Background(), []*entity.WebhookDelivery{
+		{SubscriptionID: 1, ClientID: 1, EventID: 5, EventType: "order.created", Payload: `{"id":5}`, Status: model.WebhookDeliveryPending, NextAttemptAt: now},
+	})
+	repo.DeactivateSubscription(context.Background(), 1, 1)
+
+	dispatcher := webhook.NewDispatcher(repo, time.Second, 3, time.Minute, time.Second, true)
+
+	delivered, err := dispatcher.ProcessBatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 0, requests)
+	assert.Equal(t, model.WebhookDeliveryDead, repo.deliveries[0].Status)
+	assert.Equal(t, 0, repo.deliveries[0].Attempts)
+}